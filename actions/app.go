@@ -4,17 +4,22 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"os"
+	"regexp"
 
 	"github.com/YaleSpinup/apierror"
 	"github.com/YaleSpinup/rds-api/pkg/common"
 	"github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/YaleSpinup/rds-api/pkg/redact"
 	"github.com/YaleSpinup/rds-api/rdsapi"
 	"github.com/gobuffalo/buffalo"
 	"github.com/gobuffalo/envy"
 	paramlogger "github.com/gobuffalo/mw-paramlogger"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 
 	"github.com/gobuffalo/x/sessions"
@@ -48,7 +53,8 @@ var (
 )
 
 type rdsOrchestrator struct {
-	client *rds.Client
+	client    *rds.Client
+	operation *operation
 }
 
 // App is where all routes and middleware for buffalo should be defined
@@ -69,7 +75,20 @@ func App() *buffalo.App {
 			http.StatusInternalServerError: defaultErrorHandler,
 		}
 
+		// mask credentials in everything the service logs, with the stdlib, logrus and buffalo loggers
+		log.SetOutput(redact.NewWriter(os.Stderr))
+		logrus.SetOutput(redact.NewWriter(os.Stderr))
+		if l, ok := app.Logger.(interface{ SetOutput(io.Writer) }); ok {
+			l.SetOutput(redact.NewWriter(os.Stdout))
+		}
+
 		if ENV == "development" {
+			paramlogger.ParameterExclusionList = append(paramlogger.ParameterExclusionList,
+				"MasterUserPassword",
+				"Secret",
+				"Token",
+				"X-Auth-Token",
+			)
 			app.Use(paramlogger.ParameterLogger)
 		}
 
@@ -93,18 +112,45 @@ func App() *buffalo.App {
 
 		rdsV1API := app.Group("/v1/rds/{account}")
 		rdsV1API.Use(s.authHandler)
+		rdsV1API.Use(s.regionHandler)
 		rdsV1API.POST("/", s.DatabasesPost)
 		rdsV1API.GET("/", s.DatabasesList)
 		rdsV1API.DELETE("/snapshots", s.SnapshotsDeleteNonProd)
+		rdsV1API.GET("/operations/{id}", s.OperationsGet)
+		rdsV1API.GET("/maintenance", s.MaintenanceListAll)
+		rdsV1API.GET("/catalog/engines", s.CatalogEngines)
+		rdsV1API.GET("/catalog/instance-classes", s.CatalogInstanceClasses)
 		rdsV1API.GET("/{db}", s.DatabasesGet)
 		rdsV1API.PUT("/{db}", s.DatabasesPut)
 		rdsV1API.PUT("/{db}/power", s.DatabasesPutState)
+		rdsV1API.PUT("/{db}/unprotect", s.DatabasesUnprotect)
+		rdsV1API.POST("/{db}/reboot", s.DatabasesReboot)
+		rdsV1API.POST("/{db}/failover", s.DatabasesFailover)
+		rdsV1API.POST("/{db}/restore", s.DatabasesRestore)
+		rdsV1API.POST("/{db}/upgrade", s.DatabasesUpgrade)
+		rdsV1API.GET("/{db}/upgrade-path", s.DatabasesUpgradePath)
+		rdsV1API.GET("/{db}/maintenance", s.MaintenanceList)
+		rdsV1API.GET("/{db}/events", s.DatabasesEvents)
+		rdsV1API.GET("/{db}/logs", s.LogsList)
+		rdsV1API.GET("/{db}/logs/{file:.+}", s.LogsGet)
+		rdsV1API.POST("/{db}/maintenance", s.MaintenanceApply)
+		rdsV1API.POST("/{db}/credentials/rotate", s.CredentialsRotate)
+		rdsV1API.POST("/{db}/auth-token", s.CredentialsAuthToken)
+		rdsV1API.POST("/{db}/replicas", s.ReplicasPost)
+		rdsV1API.GET("/{db}/replicas", s.ReplicasList)
+		rdsV1API.PUT("/{db}/promote", s.ReplicasPromote)
+		rdsV1API.POST("/{db}/members", s.ClusterMembersPost)
+		rdsV1API.GET("/{db}/members", s.ClusterMembersList)
+		rdsV1API.DELETE("/{db}/members/{member}", s.ClusterMembersDelete)
 		rdsV1API.DELETE("/{db}", s.DatabasesDelete)
 		rdsV1API.POST("/{db}/snapshots", s.SnapshotsPost)
 		rdsV1API.GET("/{db}/snapshots", s.SnapshotsList)
 		rdsV1API.GET("/snapshots/{snap}/versions", s.SnapshotsVersionList)
+		rdsV1API.GET("/snapshots/{snap}/upgrade-path", s.SnapshotsUpgradePath)
+		rdsV1API.GET("/snapshots/{snap}/events", s.SnapshotsEvents)
 		rdsV1API.GET("/snapshots/{snap}", s.SnapshotsGet)
 		rdsV1API.DELETE("/snapshots/{snap}", s.SnapshotsDelete)
+		rdsV1API.POST("/snapshots/{snap}/copy", s.SnapshotsCopy)
 		rdsV1API.POST("/snapshots/{snap}", s.SnapshotModify)
 
 		log.Printf("Started rds-api in org %s", Org)
@@ -129,6 +175,22 @@ func (s *server) authHandler(next buffalo.Handler) buffalo.Handler {
 	}
 }
 
+// regionPattern matches AWS region names, like us-east-1 or us-gov-west-1
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-[0-9]$`)
+
+// regionHandler middleware validates the region query parameter and sets the X-Region header
+// to the region the request acts on
+func (s *server) regionHandler(next buffalo.Handler) buffalo.Handler {
+	return func(c buffalo.Context) error {
+		region := s.accountRegion(c)
+		if !regionPattern.MatchString(region) {
+			return c.Error(400, errors.New("Bad request: invalid region "+region))
+		}
+		c.Response().Header().Set("X-Region", region)
+		return next(c)
+	}
+}
+
 // handleError handles standard apierror return codes
 func handleError(c buffalo.Context, err error) error {
 	log.Println(err.Error())
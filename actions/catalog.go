@@ -0,0 +1,241 @@
+package actions
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/gobuffalo/buffalo"
+	"github.com/patrickmn/go-cache"
+	"github.com/pkg/errors"
+)
+
+// CatalogEngines lists the database engine versions available in a given account, including deprecated
+// versions, with their parameter group families.  The list can be filtered with the `engine` parameter
+// and paged with the `limit` and `cursor` parameters.
+func (s *server) CatalogEngines(c buffalo.Context) error {
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+	engine := c.Param("engine")
+
+	limit, after, err := pageParams(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := generatePolicy("rds:DescribeDBEngineVersions")
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	cacheKey := fmt.Sprintf("%s/%s/engines/%s", accountId, region, engine)
+
+	var versions []*CatalogEngineVersion
+	if item, found := s.catalog.Get(cacheKey); found {
+		versions = item.([]*CatalogEngineVersion)
+	} else {
+		rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+		out, err := rdsClient.ListEngineVersions(c, engine)
+		if err != nil {
+			return handleError(c, ErrCode("failed to list engine versions", err))
+		}
+
+		versions = catalogEngineVersions(out)
+		s.catalog.Set(cacheKey, versions, cache.DefaultExpiration)
+	}
+
+	keys := make([]string, len(versions))
+	for i, v := range versions {
+		keys[i] = v.key()
+	}
+
+	start, end, next := paginate(keys, limit, after)
+
+	output := struct {
+		EngineVersions []*CatalogEngineVersion
+	}{
+		EngineVersions: versions[start:end],
+	}
+
+	setPageHeaders(c, end-start, len(keys), next)
+	return c.Render(200, r.JSON(output))
+}
+
+// CatalogInstanceClasses lists the database instance classes that can be ordered in a given account for the engine
+// in the `engine` parameter, optionally only for the engine version in the `version` parameter.  Only instance
+// classes available in the availability zones of the default subnet group are listed.  The list can be paged with
+// the `limit` and `cursor` parameters.
+func (s *server) CatalogInstanceClasses(c buffalo.Context) error {
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+	engine := c.Param("engine")
+	version := c.Param("version")
+
+	if engine == "" {
+		return c.Error(400, errors.New("Bad request: specify the engine"))
+	}
+
+	limit, after, err := pageParams(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := generatePolicy("rds:DescribeOrderableDBInstanceOptions", "rds:DescribeDBSubnetGroups")
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	cacheKey := fmt.Sprintf("%s/%s/instance-classes/%s/%s", accountId, region, engine, version)
+
+	var classes []*CatalogInstanceClass
+	if item, found := s.catalog.Get(cacheKey); found {
+		classes = item.([]*CatalogInstanceClass)
+	} else {
+		rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+		var zones []string
+		if rdsClient.DefaultSubnetGroup != "" {
+			if zones, err = rdsClient.SubnetGroupAvailabilityZones(c, rdsClient.DefaultSubnetGroup); err != nil {
+				return handleError(c, ErrCode("failed to describe default subnet group", err))
+			}
+		}
+
+		out, err := rdsClient.ListOrderableInstanceOptions(c, engine, version)
+		if err != nil {
+			return handleError(c, ErrCode("failed to list orderable instance options", err))
+		}
+
+		classes = catalogInstanceClasses(out, zones)
+		s.catalog.Set(cacheKey, classes, cache.DefaultExpiration)
+	}
+
+	keys := make([]string, len(classes))
+	for i, cl := range classes {
+		keys[i] = cl.key()
+	}
+
+	start, end, next := paginate(keys, limit, after)
+
+	output := struct {
+		InstanceClasses []*CatalogInstanceClass
+	}{
+		InstanceClasses: classes[start:end],
+	}
+
+	setPageHeaders(c, end-start, len(keys), next)
+	return c.Render(200, r.JSON(output))
+}
+
+// catalogEngineVersions returns the catalog entries for the given engine versions, sorted by engine and version
+func catalogEngineVersions(versions []*rds.DBEngineVersion) []*CatalogEngineVersion {
+	catalog := make([]*CatalogEngineVersion, 0, len(versions))
+	for _, v := range versions {
+		catalog = append(catalog, &CatalogEngineVersion{
+			Engine:                 aws.StringValue(v.Engine),
+			EngineVersion:          aws.StringValue(v.EngineVersion),
+			DBParameterGroupFamily: aws.StringValue(v.DBParameterGroupFamily),
+			Status:                 aws.StringValue(v.Status),
+			Deprecated:             aws.StringValue(v.Status) == "deprecated",
+		})
+	}
+
+	sort.Slice(catalog, func(i, j int) bool { return catalog[i].key() < catalog[j].key() })
+
+	return catalog
+}
+
+// catalogInstanceClasses returns the catalog entries for the given orderable instance options, one per instance
+// class and engine version, sorted by instance class and engine version.  If availability zones are given, only
+// instance classes available in at least one of them are returned, with the availability zones they are available in.
+func catalogInstanceClasses(options []*rds.OrderableDBInstanceOption, zones []string) []*CatalogInstanceClass {
+	classes := map[string]*CatalogInstanceClass{}
+	for _, o := range options {
+		available := []string{}
+		for _, z := range o.AvailabilityZones {
+			name := aws.StringValue(z.Name)
+			if len(zones) == 0 || containsString(zones, name) {
+				available = append(available, name)
+			}
+		}
+
+		// options without availability zones are available in any zone
+		if len(o.AvailabilityZones) == 0 {
+			available = append(available, zones...)
+		} else if len(available) == 0 {
+			continue
+		}
+
+		cl := &CatalogInstanceClass{
+			DBInstanceClass: aws.StringValue(o.DBInstanceClass),
+			Engine:          aws.StringValue(o.Engine),
+			EngineVersion:   aws.StringValue(o.EngineVersion),
+		}
+		if existing, ok := classes[cl.key()]; ok {
+			cl = existing
+		} else {
+			classes[cl.key()] = cl
+		}
+
+		if st := aws.StringValue(o.StorageType); st != "" && !containsString(cl.StorageTypes, st) {
+			cl.StorageTypes = append(cl.StorageTypes, st)
+		}
+		for _, z := range available {
+			if !containsString(cl.AvailabilityZones, z) {
+				cl.AvailabilityZones = append(cl.AvailabilityZones, z)
+			}
+		}
+		cl.MultiAZCapable = cl.MultiAZCapable || aws.BoolValue(o.MultiAZCapable)
+		cl.SupportsClusters = cl.SupportsClusters || aws.BoolValue(o.SupportsClusters)
+	}
+
+	catalog := make([]*CatalogInstanceClass, 0, len(classes))
+	for _, cl := range classes {
+		sort.Strings(cl.StorageTypes)
+		sort.Strings(cl.AvailabilityZones)
+		catalog = append(catalog, cl)
+	}
+
+	sort.Slice(catalog, func(i, j int) bool { return catalog[i].key() < catalog[j].key() })
+
+	return catalog
+}
+
+// containsString returns true if the given list contains the given string
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
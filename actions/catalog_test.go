@@ -0,0 +1,100 @@
+package actions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+func TestCatalogInstanceClasses(t *testing.T) {
+	option := func(class, storage string, multiAZ bool, zones ...string) *rds.OrderableDBInstanceOption {
+		o := &rds.OrderableDBInstanceOption{
+			DBInstanceClass: aws.String(class),
+			Engine:          aws.String("postgres"),
+			EngineVersion:   aws.String("16.3"),
+			MultiAZCapable:  aws.Bool(multiAZ),
+			StorageType:     aws.String(storage),
+		}
+		for _, z := range zones {
+			o.AvailabilityZones = append(o.AvailabilityZones, &rds.AvailabilityZone{Name: aws.String(z)})
+		}
+		return o
+	}
+
+	options := []*rds.OrderableDBInstanceOption{
+		option("db.t3.micro", "gp3", false, "us-east-1a", "us-east-1c"),
+		option("db.t3.micro", "gp2", true, "us-east-1a", "us-east-1b"),
+		option("db.r6g.large", "gp3", true, "us-east-1c"),
+		option("db.m5.large", "io1", true),
+	}
+
+	tests := []struct {
+		name  string
+		zones []string
+		want  []*CatalogInstanceClass
+	}{
+		{
+			name:  "subnet group zones",
+			zones: []string{"us-east-1a", "us-east-1b"},
+			want: []*CatalogInstanceClass{
+				{DBInstanceClass: "db.m5.large", Engine: "postgres", EngineVersion: "16.3", StorageTypes: []string{"io1"}, AvailabilityZones: []string{"us-east-1a", "us-east-1b"}, MultiAZCapable: true},
+				{DBInstanceClass: "db.t3.micro", Engine: "postgres", EngineVersion: "16.3", StorageTypes: []string{"gp2", "gp3"}, AvailabilityZones: []string{"us-east-1a", "us-east-1b"}, MultiAZCapable: true},
+			},
+		},
+		{
+			name: "no zones",
+			want: []*CatalogInstanceClass{
+				{DBInstanceClass: "db.m5.large", Engine: "postgres", EngineVersion: "16.3", StorageTypes: []string{"io1"}, MultiAZCapable: true},
+				{DBInstanceClass: "db.r6g.large", Engine: "postgres", EngineVersion: "16.3", StorageTypes: []string{"gp3"}, AvailabilityZones: []string{"us-east-1c"}, MultiAZCapable: true},
+				{DBInstanceClass: "db.t3.micro", Engine: "postgres", EngineVersion: "16.3", StorageTypes: []string{"gp2", "gp3"}, AvailabilityZones: []string{"us-east-1a", "us-east-1b", "us-east-1c"}, MultiAZCapable: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := catalogInstanceClasses(options, tt.zones); !reflect.DeepEqual(got, tt.want) {
+				for _, g := range got {
+					t.Logf("%+v", g)
+				}
+				t.Errorf("unexpected instance classes")
+			}
+		})
+	}
+}
+
+func TestCatalogEngineVersions(t *testing.T) {
+	versions := []*rds.DBEngineVersion{}
+	for _, v := range []string{"16.1", "9.6.24", "13.10", "13.9", "9.6.3"} {
+		versions = append(versions, &rds.DBEngineVersion{Engine: aws.String("postgres"), EngineVersion: aws.String(v)})
+	}
+
+	got := []string{}
+	for _, v := range catalogEngineVersions(versions) {
+		got = append(got, v.EngineVersion)
+	}
+
+	expected := []string{"9.6.3", "9.6.24", "13.9", "13.10", "16.1"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected versions sorted as %v, got %v", expected, got)
+	}
+}
+
+func TestVersionKey(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{a: "9.6", b: "16.1"},
+		{a: "13.9", b: "13.10"},
+		{a: "8.0.mysql_aurora.3.4.0", b: "8.0.mysql_aurora.3.04.1"},
+		{a: "19.0.0.0.ru-2023-07.rur-2023-07.r1", b: "19.0.0.0.ru-2023-10.rur-2023-10.r1"},
+	}
+
+	for _, tt := range tests {
+		if versionKey(tt.a) >= versionKey(tt.b) {
+			t.Errorf("expected %s to sort before %s", tt.a, tt.b)
+		}
+	}
+}
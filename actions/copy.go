@@ -0,0 +1,340 @@
+package actions
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/gobuffalo/buffalo"
+	"github.com/pkg/errors"
+)
+
+// snapshotCopy is a snapshot copy resolved against the source snapshot and the target account and region
+type snapshotCopy struct {
+	cluster       bool
+	identifier    string
+	arn           string
+	status        string
+	snapshotType  string
+	kmsKeyId      string
+	tags          []*rds.Tag
+	sourceAccount string
+	sourceRegion  string
+	targetAccount string
+	targetRegion  string
+}
+
+// crossAccount returns true if the snapshot is copied to another account, which has to be shared the snapshot first
+func (cp *snapshotCopy) crossAccount() bool {
+	return cp.sourceAccount != cp.targetAccount
+}
+
+// crossRegion returns true if the snapshot is copied to another region, which needs a pre-signed URL
+func (cp *snapshotCopy) crossRegion() bool {
+	return cp.sourceRegion != cp.targetRegion
+}
+
+// sourceIdentifier returns the identifier of the source snapshot for the copy request, which has to be the ARN
+// unless the copy stays in the same account and region
+func (cp *snapshotCopy) sourceIdentifier() string {
+	if cp.crossAccount() || cp.crossRegion() {
+		return cp.arn
+	}
+	return cp.identifier
+}
+
+// SnapshotsCopy copies a database instance or cluster snapshot in a given account to a new snapshot, in the same
+// or another region of the same or another mapped account
+func (s *server) SnapshotsCopy(c buffalo.Context) error {
+	req := SnapshotCopyRequest{}
+	if err := c.Bind(&req); err != nil {
+		log.Println(err)
+		return c.Error(400, err)
+	}
+
+	if err := rdsapi.ValidateIdentifier(req.TargetSnapshotIdentifier); err != nil {
+		return c.Error(400, errors.New("Bad request: TargetSnapshotIdentifier: "+err.Error()))
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+	snap := c.Param("snap")
+
+	targetName := c.Param("account")
+	if req.TargetAccount != "" {
+		targetName = req.TargetAccount
+	}
+
+	targetAccount, targetRegion, err := s.snapshotCopyTarget(targetName, region, &req)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// the source is looked up with a read only session, before the session for the copy is requested
+	readPolicy, err := generatePolicy("rds:DescribeDBClusterSnapshots", "rds:DescribeDBSnapshots")
+	if err != nil {
+		return handleError(c, err)
+	}
+	readClient, err := s.scopedClient(accountId, region, readPolicy)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	if err := s.ensureSnapshotOrg(c, readClient, snap); err != nil {
+		return handleError(c, err)
+	}
+
+	cp, err := snapshotCopySource(c, readClient, snap)
+	if err != nil {
+		return handleError(c, err)
+	}
+	cp.sourceAccount, cp.sourceRegion = accountId, region
+	cp.targetAccount, cp.targetRegion = targetAccount, targetRegion
+
+	// an encrypted copy in another account or region needs a key there, which is the default key of the target account if not
+	// given.  the default key is only used in the region of the target account, other regions need the key to be given.
+	if cp.kmsKeyId != "" && req.KmsKeyId == nil && (cp.crossAccount() || cp.crossRegion()) {
+		req.KmsKeyId = s.defaultKmsKeyId(targetName, targetRegion)
+	}
+
+	if err := validateSnapshotCopy(cp, &req); err != nil {
+		return handleError(c, err)
+	}
+
+	if cp.crossAccount() && cp.kmsKeyId != "" {
+		if err := s.ensureShareableKey(c, accountId, region, cp.kmsKeyId); err != nil {
+			return handleError(c, err)
+		}
+	}
+
+	if req.KmsKeyId != nil {
+		kmsClient, err := s.kmsClient(c, targetAccount, targetRegion)
+		if err != nil {
+			return handleError(c, err)
+		}
+		if req.KmsKeyId, err = ensureKmsKey(c, kmsClient, req.KmsKeyId); err != nil {
+			return handleError(c, err)
+		}
+	}
+
+	policy, err := s.snapshotCopyPolicy(targetAccount, cp.arn, req.TargetSnapshotIdentifier, cp.kmsKeyId, aws.StringValue(req.KmsKeyId))
+	if err != nil {
+		return handleError(c, err)
+	}
+	targetClient, err := s.scopedClient(targetAccount, targetRegion, policy)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	sharePolicy, err := s.snapshotPolicy(accountId, snap, "rds:ModifyDBClusterSnapshotAttribute", "rds:ModifyDBSnapshotAttribute")
+	if err != nil {
+		return handleError(c, err)
+	}
+	shareClient := s.scopedClient(accountId, region, sharePolicy)
+
+	op := s.newOperation("copy snapshot", accountId, targetRegion, req.TargetSnapshotIdentifier)
+	c.Response().Header().Set("X-Operation-Id", op.id())
+	op.setStatus(operationCopying)
+
+	if cp.crossAccount() {
+		step := op.startStep("share snapshot " + snap + " with account " + targetAccount)
+		client, err := shareClient(c)
+		if err == nil {
+			err = client.ShareSnapshot(c, snap, cp.cluster, targetAccount)
+		}
+		if err != nil {
+			step.fail(err)
+			op.fail(err)
+			if _, ok := err.(apierror.Error); ok {
+				return handleError(c, err)
+			}
+			return handleError(c, ErrCode("failed to share snapshot", err))
+		}
+		step.complete()
+	}
+
+	tags := []*Tag{}
+	if req.CopyTags {
+		tags = fromRDSTags(cp.tags)
+	}
+	input := &rdsapi.SnapshotCopyInput{
+		SourceSnapshotIdentifier: cp.sourceIdentifier(),
+		TargetSnapshotIdentifier: req.TargetSnapshotIdentifier,
+		KmsKeyId:                 aws.StringValue(req.KmsKeyId),
+		Tags:                     toRDSTags(normalizeTags(mergeTags(tags, req.Tags))),
+	}
+	if cp.crossRegion() {
+		input.SourceRegion = region
+	}
+
+	resp := &SnapshotCopyResponse{
+		Account: targetAccount,
+		Region:  targetRegion,
+	}
+
+	step := op.startStep("copy snapshot " + snap + " to " + req.TargetSnapshotIdentifier)
+	if cp.cluster {
+		resp.DBClusterSnapshot, err = targetClient.CopyClusterSnapshot(c, input)
+	} else {
+		resp.DBSnapshot, err = targetClient.CopySnapshot(c, input)
+	}
+	if err != nil {
+		step.fail(err)
+		op.fail(err)
+
+		// the snapshot is only shared for the copy
+		if cp.crossAccount() {
+			if client, cerr := shareClient(c); cerr != nil {
+				log.Printf("failed to unshare snapshot %s with account %s: %s", snap, targetAccount, cerr)
+			} else if cerr := client.UnshareSnapshot(c, snap, cp.cluster, targetAccount); cerr != nil {
+				log.Printf("failed to unshare snapshot %s with account %s: %s", snap, targetAccount, cerr)
+			}
+		}
+
+		return handleError(c, ErrCode("failed to copy snapshot", err))
+	}
+	step.complete()
+
+	resp.OperationID = op.id()
+
+	waits := []operationWait{waitSnapshotAvailable(req.TargetSnapshotIdentifier, cp.cluster, operationCopying)}
+	if cp.crossAccount() {
+		waits = append(waits, unshareSnapshot(shareClient, snap, cp.cluster, targetAccount))
+	}
+	s.watchOperationTimeout(op, copyOperationTimeout, s.readOnlyClient(targetAccount, targetRegion), operationAvailable, waits...)
+
+	return c.Render(200, r.JSON(resp))
+}
+
+// snapshotCopyTarget returns the account number and region to copy a snapshot to.  Snapshots can only be
+// copied to mapped accounts, since the snapshot is shared with the account for the copy.
+func (s *server) snapshotCopyTarget(targetName, sourceRegion string, req *SnapshotCopyRequest) (string, string, error) {
+	targetAccount := s.mapAccountNumber(targetName)
+	targetRegion := sourceRegion
+	if req.TargetRegion != "" {
+		targetRegion = req.TargetRegion
+	}
+
+	if req.TargetAccount != "" {
+		a, ok := s.accountsMap[req.TargetAccount]
+		if !ok {
+			msg := fmt.Sprintf("target account %s is not a mapped account", req.TargetAccount)
+			return "", "", apierror.New(apierror.ErrBadRequest, msg, nil)
+		}
+		targetAccount = a.Id
+		targetRegion = s.mapAccountRegion(req.TargetAccount, req.TargetRegion)
+	}
+
+	if !regionPattern.MatchString(targetRegion) {
+		msg := fmt.Sprintf("invalid target region %s", targetRegion)
+		return "", "", apierror.New(apierror.ErrBadRequest, msg, nil)
+	}
+
+	return targetAccount, targetRegion, nil
+}
+
+// snapshotCopySource looks up the cluster or instance snapshot with the given identifier to copy
+func snapshotCopySource(c buffalo.Context, client *rdsapi.Client, id string) (*snapshotCopy, error) {
+	clusterSnapshot, err := client.DescribeDBClusterSnaphot(c, id)
+	if err != nil && !isNotFoundError(err) {
+		return nil, ErrCode("failed to describe snapshot", err)
+	}
+	if clusterSnapshot != nil {
+		return &snapshotCopy{
+			cluster:      true,
+			identifier:   aws.StringValue(clusterSnapshot.DBClusterSnapshotIdentifier),
+			arn:          aws.StringValue(clusterSnapshot.DBClusterSnapshotArn),
+			status:       aws.StringValue(clusterSnapshot.Status),
+			snapshotType: aws.StringValue(clusterSnapshot.SnapshotType),
+			kmsKeyId:     aws.StringValue(clusterSnapshot.KmsKeyId),
+			tags:         clusterSnapshot.TagList,
+		}, nil
+	}
+
+	instanceSnapshot, err := client.DescribeDBSnaphot(c, id)
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, err
+		}
+		return nil, ErrCode("failed to describe snapshot", err)
+	}
+
+	return &snapshotCopy{
+		identifier:   aws.StringValue(instanceSnapshot.DBSnapshotIdentifier),
+		arn:          aws.StringValue(instanceSnapshot.DBSnapshotArn),
+		status:       aws.StringValue(instanceSnapshot.Status),
+		snapshotType: aws.StringValue(instanceSnapshot.SnapshotType),
+		kmsKeyId:     aws.StringValue(instanceSnapshot.KmsKeyId),
+		tags:         instanceSnapshot.TagList,
+	}, nil
+}
+
+// validateSnapshotCopy checks that the snapshot can be copied to the target account and region
+func validateSnapshotCopy(cp *snapshotCopy, req *SnapshotCopyRequest) error {
+	if !cp.crossAccount() && !cp.crossRegion() && cp.identifier == req.TargetSnapshotIdentifier {
+		return apierror.New(apierror.ErrBadRequest, "the copy needs a different identifier in the same account and region", nil)
+	}
+
+	if cp.status != "available" {
+		msg := fmt.Sprintf("snapshot %s is %s, only available snapshots can be copied", cp.identifier, cp.status)
+		return apierror.New(apierror.ErrConflict, msg, nil)
+	}
+
+	if cp.crossAccount() && cp.snapshotType == "automated" {
+		msg := fmt.Sprintf("automated snapshot %s can't be shared with another account, copy it in its own account first", cp.identifier)
+		return apierror.New(apierror.ErrBadRequest, msg, nil)
+	}
+
+	if cp.kmsKeyId == "" && cp.cluster && req.KmsKeyId != nil {
+		return apierror.New(apierror.ErrBadRequest, "KmsKeyId cannot be specified for the copy of an unencrypted cluster snapshot", nil)
+	}
+
+	if cp.kmsKeyId != "" && req.KmsKeyId == nil && (cp.crossAccount() || cp.crossRegion()) {
+		return apierror.New(apierror.ErrBadRequest, "KmsKeyId is required to copy an encrypted snapshot to another account or region", nil)
+	}
+
+	return nil
+}
+
+// ensureShareableKey checks that the snapshot key with the given ARN is a customer managed key, since
+// snapshots encrypted with the AWS managed key can't be shared with other accounts
+func (s *server) ensureShareableKey(c buffalo.Context, accountId, region, kmsKeyId string) error {
+	kmsClient, err := s.kmsClient(c, accountId, region)
+	if err != nil {
+		return err
+	}
+
+	key, err := kmsClient.DescribeKey(c, kmsKeyId)
+	if err != nil {
+		// the copy fails later if the key really can't be used
+		log.Printf("failed to describe kms key %s of snapshot: %s", kmsKeyId, err)
+		return nil
+	}
+
+	if aws.StringValue(key.KeyManager) == "AWS" {
+		msg := "snapshots encrypted with the AWS managed key can't be shared with another account, copy it with a customer managed KmsKeyId first"
+		return apierror.New(apierror.ErrBadRequest, msg, nil)
+	}
+
+	return nil
+}
+
+// mergeTags returns the given tags with the overrides added, replacing tags with the same key
+func mergeTags(tags, overrides []*Tag) []*Tag {
+	keys := map[string]bool{}
+	for _, t := range overrides {
+		keys[aws.StringValue(t.Key)] = true
+	}
+
+	merged := []*Tag{}
+	for _, t := range tags {
+		if !keys[aws.StringValue(t.Key)] {
+			merged = append(merged, t)
+		}
+	}
+
+	return append(merged, overrides...)
+}
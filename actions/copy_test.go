@@ -0,0 +1,293 @@
+package actions
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/YaleSpinup/apierror"
+	"github.com/YaleSpinup/aws-go/services/iam"
+	"github.com/YaleSpinup/rds-api/pkg/common"
+	"github.com/YaleSpinup/rds-api/pkg/session"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestSnapshotCopyTarget(t *testing.T) {
+	sess := session.New(session.WithRegion("us-east-1"))
+	s := &server{
+		accountsMap: map[string]common.MappedAccount{
+			"spinup": {Id: "0123456789"},
+			"dr":     {Id: "9876543210", Region: "us-west-2"},
+		},
+		session: &sess,
+	}
+
+	tests := []struct {
+		name        string
+		target      string
+		req         *SnapshotCopyRequest
+		wantAccount string
+		wantRegion  string
+		wantErr     bool
+	}{
+		{
+			name:        "same account and region",
+			target:      "spinup",
+			req:         &SnapshotCopyRequest{},
+			wantAccount: "0123456789",
+			wantRegion:  "us-east-2",
+		},
+		{
+			name:        "same account other region",
+			target:      "spinup",
+			req:         &SnapshotCopyRequest{TargetRegion: "us-west-1"},
+			wantAccount: "0123456789",
+			wantRegion:  "us-west-1",
+		},
+		{
+			name:        "mapped account region",
+			target:      "dr",
+			req:         &SnapshotCopyRequest{TargetAccount: "dr"},
+			wantAccount: "9876543210",
+			wantRegion:  "us-west-2",
+		},
+		{
+			name:        "mapped account region override",
+			target:      "dr",
+			req:         &SnapshotCopyRequest{TargetAccount: "dr", TargetRegion: "eu-west-1"},
+			wantAccount: "9876543210",
+			wantRegion:  "eu-west-1",
+		},
+		{
+			name:    "unmapped account",
+			target:  "1111111111",
+			req:     &SnapshotCopyRequest{TargetAccount: "1111111111"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid region",
+			target:  "spinup",
+			req:     &SnapshotCopyRequest{TargetRegion: "mars"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, region, err := s.snapshotCopyTarget(tt.target, "us-east-2", tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("snapshotCopyTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if account != tt.wantAccount || region != tt.wantRegion {
+				t.Errorf("snapshotCopyTarget() = %s, %s, want %s, %s", account, region, tt.wantAccount, tt.wantRegion)
+			}
+		})
+	}
+}
+
+func TestValidateSnapshotCopy(t *testing.T) {
+	snapshot := func(cluster bool, snapshotType, kmsKeyId, targetAccount, targetRegion string) *snapshotCopy {
+		return &snapshotCopy{
+			cluster:       cluster,
+			identifier:    "mydb-snap",
+			arn:           "arn:aws:rds:us-east-1:0123456789:snapshot:mydb-snap",
+			status:        "available",
+			snapshotType:  snapshotType,
+			kmsKeyId:      kmsKeyId,
+			sourceAccount: "0123456789",
+			sourceRegion:  "us-east-1",
+			targetAccount: targetAccount,
+			targetRegion:  targetRegion,
+		}
+	}
+
+	key := "arn:aws:kms:us-east-1:0123456789:key/1234"
+
+	tests := []struct {
+		name     string
+		snapshot *snapshotCopy
+		req      *SnapshotCopyRequest
+		wantCode string
+	}{
+		{
+			name:     "same account copy",
+			snapshot: snapshot(false, "automated", key, "0123456789", "us-east-1"),
+			req:      &SnapshotCopyRequest{TargetSnapshotIdentifier: "mydb-copy"},
+		},
+		{
+			name:     "same identifier",
+			snapshot: snapshot(false, "manual", "", "0123456789", "us-east-1"),
+			req:      &SnapshotCopyRequest{TargetSnapshotIdentifier: "mydb-snap"},
+			wantCode: apierror.ErrBadRequest,
+		},
+		{
+			name:     "same identifier in other region",
+			snapshot: snapshot(false, "manual", "", "0123456789", "us-west-2"),
+			req:      &SnapshotCopyRequest{TargetSnapshotIdentifier: "mydb-snap"},
+		},
+		{
+			name: "unavailable snapshot",
+			snapshot: func() *snapshotCopy {
+				s := snapshot(false, "manual", "", "0123456789", "us-east-1")
+				s.status = "creating"
+				return s
+			}(),
+			req:      &SnapshotCopyRequest{TargetSnapshotIdentifier: "mydb-copy"},
+			wantCode: apierror.ErrConflict,
+		},
+		{
+			name:     "automated snapshot to other account",
+			snapshot: snapshot(false, "automated", "", "9876543210", "us-east-1"),
+			req:      &SnapshotCopyRequest{TargetSnapshotIdentifier: "mydb-snap"},
+			wantCode: apierror.ErrBadRequest,
+		},
+		{
+			name:     "encrypted snapshot to other region without key",
+			snapshot: snapshot(false, "manual", key, "0123456789", "us-west-2"),
+			req:      &SnapshotCopyRequest{TargetSnapshotIdentifier: "mydb-snap"},
+			wantCode: apierror.ErrBadRequest,
+		},
+		{
+			name:     "encrypted snapshot to other account with key",
+			snapshot: snapshot(true, "manual", key, "9876543210", "us-west-2"),
+			req:      &SnapshotCopyRequest{TargetSnapshotIdentifier: "mydb-snap", KmsKeyId: aws.String("alias/dr")},
+		},
+		{
+			name:     "unencrypted cluster snapshot with key",
+			snapshot: snapshot(true, "manual", "", "0123456789", "us-east-1"),
+			req:      &SnapshotCopyRequest{TargetSnapshotIdentifier: "mydb-copy", KmsKeyId: aws.String("alias/rds")},
+			wantCode: apierror.ErrBadRequest,
+		},
+		{
+			name:     "unencrypted instance snapshot with key",
+			snapshot: snapshot(false, "manual", "", "0123456789", "us-east-1"),
+			req:      &SnapshotCopyRequest{TargetSnapshotIdentifier: "mydb-copy", KmsKeyId: aws.String("alias/rds")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSnapshotCopy(tt.snapshot, tt.req)
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("expected nil error, got %s", err)
+				}
+				return
+			}
+
+			aerr, ok := err.(apierror.Error)
+			if !ok {
+				t.Fatalf("expected apierror.Error, got %v", err)
+			}
+			if aerr.Code != tt.wantCode {
+				t.Errorf("expected code %s, got %s", tt.wantCode, aerr.Code)
+			}
+		})
+	}
+}
+
+func TestSnapshotCopySourceIdentifier(t *testing.T) {
+	cp := &snapshotCopy{
+		identifier:    "mydb-snap",
+		arn:           "arn:aws:rds:us-east-1:0123456789:snapshot:mydb-snap",
+		sourceAccount: "0123456789",
+		sourceRegion:  "us-east-1",
+		targetAccount: "0123456789",
+		targetRegion:  "us-east-1",
+	}
+	if got := cp.sourceIdentifier(); got != "mydb-snap" {
+		t.Errorf("expected identifier in the same account and region, got %s", got)
+	}
+
+	cp.targetRegion = "us-west-2"
+	if got := cp.sourceIdentifier(); got != cp.arn {
+		t.Errorf("expected arn in another region, got %s", got)
+	}
+
+	cp.targetRegion, cp.targetAccount = "us-east-1", "9876543210"
+	if got := cp.sourceIdentifier(); got != cp.arn {
+		t.Errorf("expected arn in another account, got %s", got)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	tags := []*Tag{
+		{Key: aws.String("Name"), Value: aws.String("mydb")},
+		{Key: aws.String("env"), Value: aws.String("prod")},
+	}
+	overrides := []*Tag{{Key: aws.String("env"), Value: aws.String("dr")}}
+
+	expected := []*Tag{
+		{Key: aws.String("Name"), Value: aws.String("mydb")},
+		{Key: aws.String("env"), Value: aws.String("dr")},
+	}
+	if got := mergeTags(tags, overrides); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestSnapshotCopyPolicy(t *testing.T) {
+	s := &server{org: "localdev"}
+
+	source := "arn:aws:rds:us-east-1:0123456789:snapshot:mydb-snap"
+	sourceKey := "arn:aws:kms:us-east-1:0123456789:key/1234"
+	targetKey := "arn:aws:kms:us-west-2:9876543210:key/5678"
+	policy, err := s.snapshotCopyPolicy("9876543210", source, "mydb-dr", sourceKey, targetKey)
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	doc := iam.PolicyDocument{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		t.Fatalf("failed to unmarshal policy: %s", err)
+	}
+
+	if len(doc.Statement) != 3 {
+		t.Fatalf("expected 3 statements, got %+v", doc.Statement)
+	}
+
+	expected := iam.Value{
+		"arn:aws:rds:*:9876543210:cluster-snapshot:mydb-dr",
+		"arn:aws:rds:*:9876543210:snapshot:mydb-dr",
+		source,
+	}
+	if !reflect.DeepEqual(doc.Statement[0].Resource, expected) {
+		t.Errorf("expected copy resources %v, got %v", expected, doc.Statement[0].Resource)
+	}
+	if !reflect.DeepEqual(doc.Statement[1].Resource, iam.Value{sourceKey}) || !reflect.DeepEqual(doc.Statement[2].Resource, iam.Value{targetKey}) {
+		t.Errorf("expected statements for the source and target keys, got %+v", doc.Statement[1:])
+	}
+}
+
+func TestSnapshotCopyDefaultKmsKey(t *testing.T) {
+	s := &server{
+		accountsMap: map[string]common.MappedAccount{
+			"spinup": {Id: "0123456789", Region: "us-east-1"},
+			"dr":     {Id: "0123456789", Region: "us-west-2"},
+		},
+		defaultConfig: common.CommonConfig{
+			DefaultKmsKeyId: map[string]string{"spinup": "alias/spinup", "dr": "alias/dr"},
+		},
+	}
+
+	// a copy to another region of the account can't use the default key of the account's region
+	cp := &snapshotCopy{
+		identifier:    "mydb-snap",
+		status:        "available",
+		snapshotType:  "manual",
+		kmsKeyId:      "arn:aws:kms:us-east-1:0123456789:key/1234",
+		sourceAccount: "0123456789",
+		sourceRegion:  "us-east-1",
+		targetAccount: "0123456789",
+		targetRegion:  "us-west-2",
+	}
+	req := &SnapshotCopyRequest{TargetSnapshotIdentifier: "mydb-snap", KmsKeyId: s.defaultKmsKeyId("spinup", cp.targetRegion)}
+	if aerr, ok := validateSnapshotCopy(cp, req).(apierror.Error); !ok || aerr.Code != apierror.ErrBadRequest {
+		t.Errorf("expected bad request without a key in the target region, got %v", aerr)
+	}
+
+	// the account name mapped to the target region has its own default key
+	if key := s.defaultKmsKeyId("dr", cp.targetRegion); aws.StringValue(key) != "alias/dr" {
+		t.Errorf("expected the default key of the target region, got %v", key)
+	}
+}
@@ -0,0 +1,259 @@
+package actions
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/YaleSpinup/rds-api/pkg/secretsmanager"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/gobuffalo/buffalo"
+	"github.com/pkg/errors"
+)
+
+// passwordCharacters are the characters used in generated master user passwords.
+// RDS doesn't allow '/', '@', '"' or spaces in the master user password.
+const passwordCharacters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789!#$%^&*()-_=+[]{}<>.,:;~"
+
+// passwordLength is the length of generated master user passwords
+const passwordLength = 32
+
+// CredentialsRotate rotates the master user credentials of a database in a given account.  If the password
+// is managed in Secrets Manager, an immediate rotation of the secret is started.  Otherwise a new password is
+// generated and set on the database, and it's returned in the response.  It's not stored anywhere, so it's
+// only returned once.
+func (s *server) CredentialsRotate(c buffalo.Context) error {
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	// the policy depends on where the password is managed, so look up the database with a read only session first
+	readClient, err := s.readOnlyClient(accountId, region)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	if err := s.ensureDatabaseOrg(c, readClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	cluster, instance, err := readClient.DescribeDatabase(c, c.Param("db"))
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to describe database", err))
+	}
+
+	// the master user of a cluster member belongs to its cluster
+	var id, secretArn string
+	if cluster != nil {
+		id = aws.StringValue(cluster.DBClusterIdentifier)
+		if cluster.MasterUserSecret != nil {
+			secretArn = aws.StringValue(cluster.MasterUserSecret.SecretArn)
+		}
+	} else {
+		id = aws.StringValue(instance.DBInstanceIdentifier)
+		if instance.MasterUserSecret != nil {
+			secretArn = aws.StringValue(instance.MasterUserSecret.SecretArn)
+		}
+	}
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.credentialsRotatePolicy(accountId, id, secretArn)
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	op := s.newOperation("rotate credentials", accountId, region, id)
+	c.Response().Header().Set("X-Operation-Id", op.id())
+	op.setStatus(operationModifying)
+
+	resp := &CredentialsRotateResponse{
+		MasterUserSecretArn: secretArn,
+		OperationID:         op.id(),
+		Region:              region,
+	}
+
+	if secretArn != "" {
+		smClient := secretsmanager.New(secretsmanager.WithSession(session.Session))
+
+		step := op.startStep("rotate master user secret of " + id)
+		if _, err := smClient.RotateSecret(c, secretArn); err != nil {
+			step.fail(err)
+			op.fail(err)
+			if _, ok := err.(apierror.Error); ok {
+				return handleError(c, err)
+			}
+			return handleError(c, ErrCode("failed to rotate master user secret", err))
+		}
+		step.complete()
+	} else {
+		password, err := generatePassword(passwordLength)
+		if err != nil {
+			op.fail(err)
+			return handleError(c, apierror.New(apierror.ErrInternalError, "failed to generate password", err))
+		}
+
+		rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+		step := op.startStep("set master user password of " + id)
+		if err := rdsClient.SetMasterUserPassword(c, id, password); err != nil {
+			step.fail(err)
+			op.fail(err)
+			return handleError(c, ErrCode("failed to set master user password", err))
+		}
+		step.complete()
+
+		resp.MasterUserPassword = password
+	}
+
+	s.watchOperation(op, s.readOnlyClient(accountId, region), operationAvailable, waitDatabaseAvailable(id, operationModifying))
+
+	// the response can contain the new password, make sure it isn't cached anywhere
+	c.Response().Header().Set("Cache-Control", "no-store")
+
+	return c.Render(200, r.JSON(resp))
+}
+
+// CredentialsAuthToken issues a short lived IAM database authentication token for a database user, to be used
+// instead of a password when connecting to a database in a given account with IAM authentication enabled.
+// The token is signed with the assumed role session, so it expires with the session, at most 15 minutes
+// after it's issued.
+func (s *server) CredentialsAuthToken(c buffalo.Context) error {
+	req := AuthTokenRequest{}
+	if err := c.Bind(&req); err != nil {
+		log.Println(err)
+		return c.Error(400, err)
+	}
+
+	// the user is part of the rds-db:connect resource, so it can't contain IAM wildcards
+	if req.DBUser == "" || strings.ContainsAny(req.DBUser, "*?/ ") {
+		return c.Error(400, errors.New("Bad request: specify a valid DBUser in request"))
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	// the policy is scoped to the resource id of the database, so look it up first with a read only session
+	readClient, err := s.readOnlyClient(accountId, region)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	if err := s.ensureDatabaseOrg(c, readClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	cluster, instance, err := readClient.DescribeDatabase(c, c.Param("db"))
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to describe database", err))
+	}
+
+	// IAM authentication is enabled on the cluster for Aurora, but a member instance can be connected to directly
+	var resourceId, address string
+	var port int64
+	var enabled bool
+	if cluster != nil {
+		resourceId = aws.StringValue(cluster.DbClusterResourceId)
+		enabled = aws.BoolValue(cluster.IAMDatabaseAuthenticationEnabled)
+		address, port = aws.StringValue(cluster.Endpoint), aws.Int64Value(cluster.Port)
+	} else {
+		resourceId = aws.StringValue(instance.DbiResourceId)
+		enabled = aws.BoolValue(instance.IAMDatabaseAuthenticationEnabled)
+	}
+	if instance != nil && instance.Endpoint != nil {
+		address, port = aws.StringValue(instance.Endpoint.Address), aws.Int64Value(instance.Endpoint.Port)
+	}
+
+	if !enabled {
+		msg := fmt.Sprintf("IAM database authentication is not enabled for database %s", c.Param("db"))
+		return handleError(c, apierror.New(apierror.ErrBadRequest, msg, nil))
+	}
+
+	if address == "" {
+		msg := fmt.Sprintf("database %s doesn't have an endpoint yet", c.Param("db"))
+		return handleError(c, apierror.New(apierror.ErrConflict, msg, nil))
+	}
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.authTokenPolicy(accountId, resourceId, req.DBUser)
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	token, err := rdsapi.BuildAuthToken(address, port, region, req.DBUser, session.Session.Config.Credentials)
+	if err != nil {
+		return handleError(c, apierror.New(apierror.ErrInternalError, "failed to build auth token", err))
+	}
+
+	resp := &AuthTokenResponse{
+		AuthToken: token,
+		DBUser:    req.DBUser,
+		Endpoint:  address,
+		Port:      port,
+		ExpiresAt: authTokenExpiration(time.Now(), session.Expiration),
+	}
+
+	// the token is a credential, make sure it isn't cached anywhere
+	c.Response().Header().Set("Cache-Control", "no-store")
+
+	return c.Render(200, r.JSON(resp))
+}
+
+// authTokenExpiration returns when an auth token signed at now expires.  the token can't outlive the (possibly cached)
+// session credentials it's signed with, so it expires with them if they expire first.
+func authTokenExpiration(now, credentialsExpiration time.Time) time.Time {
+	expiration := now.Add(rdsapi.AuthTokenDuration)
+	if !credentialsExpiration.IsZero() && credentialsExpiration.Before(expiration) {
+		expiration = credentialsExpiration
+	}
+	return expiration.UTC()
+}
+
+// generatePassword generates a random password of the given length from the passwordCharacters
+func generatePassword(length int) (string, error) {
+	max := big.NewInt(int64(len(passwordCharacters)))
+
+	password := make([]byte, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		password[i] = passwordCharacters[n.Int64()]
+	}
+
+	return string(password), nil
+}
@@ -0,0 +1,52 @@
+package actions
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 10; i++ {
+		password, err := generatePassword(passwordLength)
+		if err != nil {
+			t.Fatalf("expected nil error, got %s", err)
+		}
+
+		if len(password) != passwordLength {
+			t.Errorf("expected password of length %d, got %d", passwordLength, len(password))
+		}
+
+		if strings.ContainsAny(password, `/@" `) {
+			t.Errorf("expected password without '/', '@', '\"' or spaces, got %s", password)
+		}
+
+		if seen[password] {
+			t.Errorf("expected unique passwords, got %s twice", password)
+		}
+		seen[password] = true
+	}
+}
+
+func TestAuthTokenExpiration(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		credentials time.Time
+		want        time.Time
+	}{
+		{name: "credentials without expiration", want: now.Add(15 * time.Minute)},
+		{name: "credentials outlive token", credentials: now.Add(time.Hour), want: now.Add(15 * time.Minute)},
+		{name: "cached credentials expire first", credentials: now.Add(5 * time.Minute), want: now.Add(5 * time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authTokenExpiration(now, tt.credentials); !got.Equal(tt.want) {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/YaleSpinup/apierror"
 	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
@@ -17,19 +19,40 @@ import (
 
 // DatabasesList gets a list of databases for a given account
 // If the `all=true` parameter is passed it will return a list of clusters in addition to instances.
+// The list can be filtered with the `engine`, `status`, `engineVersion` and `tag:<key>=<value>` parameters
+// and paged with the `limit` and `cursor` parameters.
 func (s *server) DatabasesList(c buffalo.Context) error {
 	// if all param is given, we'll return information about both instances and clusters
 	// otherwise, only database instances will be returned
 	all, _ := strconv.ParseBool(c.Param("all"))
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	limit, after, err := pageParams(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	filter := &rdsapi.DatabaseFilter{
+		Engine:        c.Param("engine"),
+		EngineVersion: c.Param("engineVersion"),
+		Status:        c.Param("status"),
+		Tags:          map[string]string{},
+	}
+	for k, v := range c.Request().URL.Query() {
+		if strings.HasPrefix(k, "tag:") && len(v) > 0 {
+			filter.Tags[strings.TrimPrefix(k, "tag:")] = v[0]
+		}
+	}
 
 	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
-	policy, err := generatePolicy("rds:DescribeDBInstances")
+	policy, err := generatePolicy("rds:DescribeDBClusters", "rds:DescribeDBInstances")
 	if err != nil {
 		return handleError(c, err)
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -42,25 +65,58 @@ func (s *server) DatabasesList(c buffalo.Context) error {
 
 	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
 
-	var clustersOutput *rds.DescribeDBClustersOutput
-	var instancesOutput *rds.DescribeDBInstancesOutput
+	// only databases belonging to the org are listed
+	clusters := []*rds.DBCluster{}
 	if all {
-		if clustersOutput, err = rdsClient.Service.DescribeDBClustersWithContext(c, &rds.DescribeDBClustersInput{}); err != nil {
+		clustersOutput, err := rdsClient.ListDBClusters(c, filter)
+		if err != nil {
 			log.Println(err.Error())
 		}
+		for _, cl := range clustersOutput {
+			if s.ownedByOrg(cl.TagList) {
+				clusters = append(clusters, cl)
+			}
+		}
 	}
-	instancesOutput, err = rdsClient.Service.DescribeDBInstancesWithContext(c, &rds.DescribeDBInstancesInput{})
+
+	instancesOutput, err := rdsClient.ListDBInstances(c, filter)
 	if err != nil {
-		return handleError(c, err)
+		return handleError(c, ErrCode("failed to list database instances", err))
+	}
+	instances := []*rds.DBInstance{}
+	for _, i := range instancesOutput {
+		if s.ownedByOrg(i.TagList) {
+			instances = append(instances, i)
+		}
+	}
+
+	// clusters and instances are paged together, clusters first
+	keys := make([]string, 0, len(clusters)+len(instances))
+	for _, cl := range clusters {
+		keys = append(keys, "cluster/"+aws.StringValue(cl.DBClusterIdentifier))
+	}
+	for _, i := range instances {
+		keys = append(keys, "instance/"+aws.StringValue(i.DBInstanceIdentifier))
 	}
 
+	start, end, next := paginate(keys, limit, after)
+
 	output := struct {
-		*rds.DescribeDBClustersOutput
-		*rds.DescribeDBInstancesOutput
+		DBClusters  []*rds.DBCluster  `json:"DBClusters,omitempty"`
+		DBInstances []*rds.DBInstance `json:"DBInstances"`
 	}{
-		clustersOutput,
-		instancesOutput,
+		DBInstances: []*rds.DBInstance{},
+	}
+
+	for i := start; i < end; i++ {
+		if i < len(clusters) {
+			output.DBClusters = append(output.DBClusters, clusters[i])
+		} else {
+			output.DBInstances = append(output.DBInstances, instances[i-len(clusters)])
+		}
 	}
+
+	setPageHeaders(c, end-start, len(keys), next)
 	return c.Render(200, r.JSON(output))
 }
 
@@ -71,6 +127,7 @@ func (s *server) DatabasesGet(c buffalo.Context) error {
 	// otherwise, only database instances will be searched
 	all, _ := strconv.ParseBool(c.Param("all"))
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
 
 	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
 	policy, err := generatePolicy("rds:DescribeDBInstances")
@@ -79,6 +136,7 @@ func (s *server) DatabasesGet(c buffalo.Context) error {
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -91,6 +149,10 @@ func (s *server) DatabasesGet(c buffalo.Context) error {
 
 	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
 
+	if err := s.ensureDatabaseOrg(c, rdsClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
 	var clustersOutput *rds.DescribeDBClustersOutput
 	var instancesOutput *rds.DescribeDBInstancesOutput
 
@@ -143,7 +205,7 @@ func (s *server) DatabasesGet(c buffalo.Context) error {
 	return c.Render(200, r.JSON(output))
 }
 
-// DatabasesPost creates a database in a given account
+// DatabasesPost creates a database in a given account, or only validates the request with dryRun=true
 func (s *server) DatabasesPost(c buffalo.Context) error {
 	req := DatabaseCreateRequest{}
 	if err := c.Bind(&req); err != nil {
@@ -155,15 +217,42 @@ func (s *server) DatabasesPost(c buffalo.Context) error {
 		return c.Error(400, errors.New("Bad request: specify Cluster or Instance in request"))
 	}
 
+	if req.Cluster != nil && aws.BoolValue(req.Cluster.ManageMasterUserPassword) && req.Cluster.MasterUserPassword != nil {
+		return c.Error(400, errors.New("Bad request: cannot specify both ManageMasterUserPassword and MasterUserPassword"))
+	}
+
+	if req.Instance != nil && aws.BoolValue(req.Instance.ManageMasterUserPassword) && req.Instance.MasterUserPassword != nil {
+		return c.Error(400, errors.New("Bad request: cannot specify both ManageMasterUserPassword and MasterUserPassword"))
+	}
+
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	if dryRun, _ := strconv.ParseBool(c.Param("dryRun")); dryRun {
+		return s.databaseCreateDryRun(c, accountId, region, &req)
+	}
+
+	if err := validateCreateStorage(&req); err != nil {
+		return c.Error(400, errors.New("Bad request: "+err.Error()))
+	}
+
+	if err := validateCreateKmsKeys(&req); err != nil {
+		return c.Error(400, errors.New("Bad request: "+err.Error()))
+	}
+
+	s.setDefaultKmsKeys(c.Param("account"), region, &req)
+	if err := s.ensureCreateKmsKeys(c, accountId, region, &req); err != nil {
+		return handleError(c, err)
+	}
 
 	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
-	policy, err := generatePolicy("rds:DescribeDBClusters", "rds:ModifyDBSnapshot", "rds:AddTagsToResource", "rds:DescribeDBSnapshots", "rds:RestoreDBClusterFromSnapshot", "rds:CreateDBInstance", "rds:CreateDBCluster", "rds:DeleteDBCluster", "rds:RestoreDBInstanceFromDBSnapshot")
+	policy, err := s.databaseCreatePolicy(accountId, &req)
 	if err != nil {
 		return handleError(c, err)
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -176,27 +265,177 @@ func (s *server) DatabasesPost(c buffalo.Context) error {
 
 	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
 
-	orch := &rdsOrchestrator{
-		client: rdsClient,
+	var dbName string
+	if req.Cluster != nil {
+		dbName = aws.StringValue(req.Cluster.DBClusterIdentifier)
+	} else {
+		dbName = aws.StringValue(req.Instance.DBInstanceIdentifier)
 	}
 
 	var resp *DatabaseResponse
 
 	if (req.Cluster != nil && req.Cluster.SnapshotIdentifier != nil) || (req.Instance != nil && req.Instance.SnapshotIdentifier != nil) {
 		// restoring database from snapshot
-		if resp, err = orch.databaseRestore(c, &req); err != nil {
+		var snapshotId string
+		if req.Cluster != nil {
+			snapshotId = aws.StringValue(req.Cluster.SnapshotIdentifier)
+		} else {
+			snapshotId = aws.StringValue(req.Instance.SnapshotIdentifier)
+		}
+		if err := s.ensureSnapshotOrg(c, rdsClient, snapshotId); err != nil {
+			return handleError(c, err)
+		}
+
+		op := s.newOperation("restore", accountId, region, dbName)
+		c.Response().Header().Set("X-Operation-Id", op.id())
+
+		orch := &rdsOrchestrator{
+			client:    rdsClient,
+			operation: op,
+		}
+
+		resp, err = orch.databaseRestore(c, &req)
+		if err != nil && resp == nil {
+			op.fail(err)
 			return handleError(c, err)
 		}
+		resp.OperationID = op.id()
+		resp.Region = region
+
+		waits := databaseWaits(resp, operationCreatingCluster, operationCreatingInstance)
+		watchClient := s.readOnlyClient(accountId, region)
+
+		// restoring from a snapshot doesn't enable storage autoscaling, so the instance is modified once it's available
+		if resp.Instance != nil && req.Instance.MaxAllocatedStorage != nil {
+			id := aws.StringValue(resp.Instance.DBInstanceIdentifier)
+			waits = append(waits, setMaxAllocatedStorage(id, aws.Int64Value(req.Instance.MaxAllocatedStorage)), waitInstanceAvailable(id, operationModifying))
+			watchClient = s.scopedClient(accountId, region, policy)
+		}
+
+		// the database was only partially restored, what was created is still watched before the operation fails
+		op.setError(err)
+		s.watchOperation(op, watchClient, operationAvailable, waits...)
 	} else {
 		// creating database from scratch
+		op := s.newOperation("create", accountId, region, dbName)
+		c.Response().Header().Set("X-Operation-Id", op.id())
+
+		orch := &rdsOrchestrator{
+			client:    rdsClient,
+			operation: op,
+		}
+
 		if resp, err = orch.databaseCreate(c, &req); err != nil {
+			op.fail(err)
 			return handleError(c, err)
 		}
+		resp.OperationID = op.id()
+		resp.Region = region
+
+		s.watchOperation(op, s.readOnlyClient(accountId, region), operationAvailable, databaseWaits(resp, operationCreatingCluster, operationCreatingInstance)...)
 	}
 
 	return c.Render(200, r.JSON(resp))
 }
 
+// DatabasesRestore restores a database cluster or instance in a given account to a point in time as a new database
+func (s *server) DatabasesRestore(c buffalo.Context) error {
+	req := DatabaseRestoreRequest{}
+	if err := c.Bind(&req); err != nil {
+		log.Println(err)
+		return c.Error(400, err)
+	}
+
+	if aws.StringValue(req.TargetIdentifier) == "" {
+		return c.Error(400, errors.New("Bad request: specify TargetIdentifier in request"))
+	}
+
+	if (req.RestoreTime == nil) == !aws.BoolValue(req.UseLatestRestorableTime) {
+		return c.Error(400, errors.New("Bad request: specify either RestoreTime or UseLatestRestorableTime in request"))
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	// the restore policy is scoped to the source database, so look it up first with a read only session
+	readClient, err := s.readOnlyClient(accountId, region)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	if err := s.ensureDatabaseOrg(c, readClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	sourceCluster, sourceInstance, err := readClient.DescribeDatabase(c, c.Param("db"))
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to describe database", err))
+	}
+
+	if err := validateRestoreStorage(sourceCluster, sourceInstance, &req); err != nil {
+		return handleError(c, err)
+	}
+
+	if err := validateRestoreKmsKey(sourceCluster != nil, &req); err != nil {
+		return handleError(c, err)
+	}
+
+	if req.KmsKeyId != nil {
+		kmsClient, err := s.kmsClient(c, accountId, region)
+		if err != nil {
+			return handleError(c, err)
+		}
+		if req.KmsKeyId, err = ensureKmsKey(c, kmsClient, req.KmsKeyId); err != nil {
+			return handleError(c, err)
+		}
+	}
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.databaseRestorePolicy(accountId, sourceCluster, sourceInstance, &req)
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	op := s.newOperation("restore", accountId, region, aws.StringValue(req.TargetIdentifier))
+	c.Response().Header().Set("X-Operation-Id", op.id())
+
+	orch := &rdsOrchestrator{
+		client:    rdsClient,
+		operation: op,
+	}
+
+	resp, err := orch.databasePointInTimeRestore(c, sourceCluster, sourceInstance, &req)
+	if err != nil && resp == nil {
+		op.fail(err)
+		return handleError(c, err)
+	}
+	resp.OperationID = op.id()
+	resp.Region = region
+
+	// the database was only partially restored, what was created is still watched before the operation fails
+	op.setError(err)
+	s.watchOperation(op, s.readOnlyClient(accountId, region), operationAvailable, databaseWaits(resp, operationCreatingCluster, operationCreatingInstance)...)
+
+	return c.Render(200, r.JSON(resp))
+}
+
 // DatabasesPut modifies a database in a given account
 func (s *server) DatabasesPut(c buffalo.Context) error {
 	input := DatabaseModifyInput{}
@@ -213,15 +452,27 @@ func (s *server) DatabasesPut(c buffalo.Context) error {
 		return c.Error(400, errors.New("Bad request: cannot specify both Cluster and Instance"))
 	}
 
+	// deletion protection is only removed with the audited unprotect call
+	if (input.Cluster != nil && input.Cluster.DeletionProtection != nil && !aws.BoolValue(input.Cluster.DeletionProtection)) ||
+		(input.Instance != nil && input.Instance.DeletionProtection != nil && !aws.BoolValue(input.Instance.DeletionProtection)) {
+		return c.Error(400, errors.New("Bad request: deletion protection can only be removed with PUT /{db}/unprotect"))
+	}
+
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	if err := s.ensureModifyKmsKeys(c, accountId, region, &input); err != nil {
+		return handleError(c, err)
+	}
 
 	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
-	policy, err := generatePolicy("rds:DescribeDBClusters", "rds:ModifyDBCluster", "rds:DescribeDBInstances", "rds:ModifyDBInstance", "rds:AddTagsToResource")
+	policy, err := s.databaseModifyPolicy(accountId, c.Param("db"), &input)
 	if err != nil {
 		return handleError(c, err)
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -234,14 +485,31 @@ func (s *server) DatabasesPut(c buffalo.Context) error {
 
 	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
 
+	if err := s.ensureDatabaseOrg(c, rdsClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	if err := s.validateModifyStorage(c, rdsClient, c.Param("db"), &input); err != nil {
+		return handleError(c, err)
+	}
+
+	op := s.newOperation("modify", accountId, region, c.Param("db"))
+	c.Response().Header().Set("X-Operation-Id", op.id())
+
 	orch := &rdsOrchestrator{
-		client: rdsClient,
+		client:    rdsClient,
+		operation: op,
 	}
 
 	resp, err := orch.databaseModify(c, c.Param("db"), &input)
 	if err != nil {
+		op.fail(err)
 		return handleError(c, err)
 	}
+	resp.OperationID = op.id()
+	resp.Region = region
+
+	s.watchOperation(op, s.readOnlyClient(accountId, region), operationAvailable, databaseWaits(resp, operationModifying, operationModifying)...)
 
 	return c.Render(200, r.JSON(resp))
 }
@@ -255,14 +523,16 @@ func (s *server) DatabasesPutState(c buffalo.Context) error {
 	}
 
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
 
 	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
-	policy, err := generatePolicy("rds:StartDBCluster", "rds:StartDBInstance", "rds:StopDBCluster", "rds:StopDBInstance")
+	policy, err := s.databaseStatePolicy(accountId, c.Param("db"))
 	if err != nil {
 		return handleError(c, err)
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -275,25 +545,186 @@ func (s *server) DatabasesPutState(c buffalo.Context) error {
 
 	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
 
+	if err := s.ensureDatabaseOrg(c, rdsClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
 	id := c.Param("db")
 	if id == "" {
 		return c.Error(400, errors.New("Bad request: missing database identifier"))
 	}
 
+	var op *operation
 	switch input.State {
 	case "start":
+		op = s.newOperation("start", accountId, region, id)
+		c.Response().Header().Set("X-Operation-Id", op.id())
+
+		step := op.startStep("start database " + id)
 		if err := rdsClient.StartDatabase(c, id); err != nil {
+			step.fail(err)
+			op.fail(err)
 			return c.Error(400, err)
 		}
+		step.complete()
+
+		s.watchOperation(op, s.readOnlyClient(accountId, region), operationAvailable, waitDatabaseAvailable(id, operationStarting))
 	case "stop":
+		op = s.newOperation("stop", accountId, region, id)
+		c.Response().Header().Set("X-Operation-Id", op.id())
+
+		step := op.startStep("stop database " + id)
 		if err := rdsClient.StopDatabase(c, id); err != nil {
+			step.fail(err)
+			op.fail(err)
 			return c.Error(400, err)
 		}
+		step.complete()
+
+		s.watchOperation(op, s.readOnlyClient(accountId, region), operationStopped, waitDatabaseStopped(id))
 	default:
 		return c.Error(400, errors.New("Invalid state.  Valid states are 'stop' or 'start'."))
 	}
 
-	return c.Render(200, r.JSON("OK"))
+	return c.Render(200, r.JSON(op.response()))
+}
+
+// DatabasesReboot reboots a database instance, or all of the instances in a cluster, in a given account
+// A Multi-AZ instance can be rebooted with a failover to its standby with `ForceFailover`.
+func (s *server) DatabasesReboot(c buffalo.Context) error {
+	// the request body is optional
+	input := DatabaseRebootInput{}
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&input); err != nil {
+			log.Println(err)
+			return c.Error(400, err)
+		}
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+	id := c.Param("db")
+
+	// the reboot policy is scoped to the cluster members, so look them up first with a read only session
+	readClient, err := s.readOnlyClient(accountId, region)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	if err := s.ensureDatabaseOrg(c, readClient, id); err != nil {
+		return handleError(c, err)
+	}
+
+	instances := []string{id}
+	if cluster, _, err := readClient.DescribeDatabase(c, id); err == nil && cluster != nil {
+		for _, m := range cluster.DBClusterMembers {
+			instances = append(instances, aws.StringValue(m.DBInstanceIdentifier))
+		}
+	}
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.databaseRebootPolicy(accountId, instances...)
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	op := s.newOperation("reboot", accountId, region, id)
+	c.Response().Header().Set("X-Operation-Id", op.id())
+	op.setStatus(operationRebooting)
+
+	step := op.startStep("reboot database " + id)
+	rebooted, err := rdsClient.RebootDatabase(c, id, input.ForceFailover)
+	if err != nil {
+		step.fail(err)
+		op.fail(err)
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to reboot database", err))
+	}
+	step.complete()
+
+	waits := []operationWait{}
+	for _, i := range rebooted {
+		waits = append(waits, waitInstanceAvailable(aws.StringValue(i.DBInstanceIdentifier), operationRebooting))
+	}
+	s.watchOperation(op, s.readOnlyClient(accountId, region), operationAvailable, waits...)
+
+	return c.Render(200, r.JSON(op.response()))
+}
+
+// DatabasesFailover forces a failover of a database cluster in a given account, optionally to the
+// instance given as `TargetDBInstanceIdentifier`
+func (s *server) DatabasesFailover(c buffalo.Context) error {
+	// the request body is optional
+	input := DatabaseFailoverInput{}
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&input); err != nil {
+			log.Println(err)
+			return c.Error(400, err)
+		}
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+	id := c.Param("db")
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.databaseFailoverPolicy(accountId, id)
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	if err := s.ensureDatabaseOrg(c, rdsClient, id); err != nil {
+		return handleError(c, err)
+	}
+
+	op := s.newOperation("failover", accountId, region, id)
+	c.Response().Header().Set("X-Operation-Id", op.id())
+	op.setStatus(operationFailingOver)
+
+	step := op.startStep("fail over cluster " + id)
+	if _, err := rdsClient.FailoverDatabase(c, id, input.TargetDBInstanceIdentifier); err != nil {
+		step.fail(err)
+		op.fail(err)
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to fail over database cluster", err))
+	}
+	step.complete()
+
+	s.watchOperation(op, s.readOnlyClient(accountId, region), operationAvailable, waitClusterAvailable(id, operationFailingOver))
+
+	return c.Render(200, r.JSON(op.response()))
 }
 
 // DatabasesDelete deletes a database in a given account
@@ -303,20 +734,66 @@ func (s *server) DatabasesDelete(c buffalo.Context) error {
 		snapshot = b
 	}
 
+	cascade := false
+	if b, err := strconv.ParseBool(c.Param("cascade")); err == nil {
+		cascade = b
+	}
+
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
 
-	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
-	
-	actions := []string{"rds:DescribeDBInstances", "rds:DeleteDBInstance", "rds:DeleteDBCluster"}
-	if snapshot {
-		actions = append(actions, "rds:CreateDBClusterSnapshot", "rds:CreateDBSnapshot", "rds:AddTagsToResource")
+	// the delete policy is scoped to the cluster the instance belongs to and its read replicas,
+	// so look them up first with a read only session
+	readClient, err := s.readOnlyClient(accountId, region)(c)
+	if err != nil {
+		return handleError(c, err)
 	}
-	policy, err := generatePolicy(actions...)
+
+	if err := s.ensureDatabaseOrg(c, readClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	cluster, instance, err := readClient.DescribeDatabase(c, c.Param("db"))
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to describe database", err))
+	}
+
+	// protected databases have to be unprotected first, with a separate call
+	if err := ensureUnprotected(cluster, instance); err != nil {
+		return handleError(c, err)
+	}
+
+	clusterName := c.Param("db")
+	if cluster != nil {
+		clusterName = aws.StringValue(cluster.DBClusterIdentifier)
+	}
+
+	// deleting a cluster, or one of its members, deletes the whole cluster, so the other members have to be
+	// deleted first, or along with it with cascade
+	others := clusterOtherMembers(cluster, instance, c.Param("db"))
+	if len(others) > 0 && !cascade {
+		msg := fmt.Sprintf("database cluster %s has members %s, delete them first or pass cascade=true", clusterName, strings.Join(others, ", "))
+		return handleError(c, apierror.New(apierror.ErrConflict, msg, nil))
+	}
+	cascadeCluster := len(others) > 0
+
+	// the other instances deleted along with the database: the read replicas of an instance or the cluster members
+	instances := others
+	if cascade && instance != nil && cluster == nil {
+		instances = aws.StringValueSlice(instance.ReadReplicaDBInstanceIdentifiers)
+	}
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.databaseDeletePolicy(accountId, c.Param("db"), clusterName, snapshot, instances...)
 	if err != nil {
 		return handleError(c, err)
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -329,14 +806,101 @@ func (s *server) DatabasesDelete(c buffalo.Context) error {
 
 	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
 
+	op := s.newOperation("delete", accountId, region, c.Param("db"))
+	c.Response().Header().Set("X-Operation-Id", op.id())
+
 	orch := &rdsOrchestrator{
-		client: rdsClient,
+		client:    rdsClient,
+		operation: op,
+	}
+
+	if cascadeCluster {
+		return s.clusterDeleteCascade(c, orch, accountId, region, cluster, snapshot, policy)
 	}
 
-	resp, err := orch.databaseDelete(c, c.Param("db"), snapshot)
+	resp, err := orch.databaseDelete(c, c.Param("db"), snapshot, cascade)
 	if err != nil {
+		op.fail(err)
 		return handleError(c, err)
 	}
+	resp.OperationID = op.id()
+	resp.Region = region
+
+	waits := []operationWait{}
+	for _, replica := range resp.Replicas {
+		waits = append(waits, waitInstanceDeleted(aws.StringValue(replica.DBInstanceIdentifier)))
+	}
+	if resp.Instance != nil {
+		waits = append(waits, waitInstanceDeleted(aws.StringValue(resp.Instance.DBInstanceIdentifier)))
+	}
+	if resp.Cluster != nil {
+		waits = append(waits, waitClusterDeleted(aws.StringValue(resp.Cluster.DBClusterIdentifier)))
+	}
+	s.watchOperation(op, s.readOnlyClient(accountId, region), operationDeleted, waits...)
+
+	return c.Render(200, r.JSON(resp))
+}
+
+// clusterOtherMembers returns the members of the cluster other than the database being deleted.  A cluster and its
+// writer can share a name, like restored clusters do, then the database is the cluster but the writer is deleted
+// as the database instance.
+func clusterOtherMembers(cluster *rds.DBCluster, instance *rds.DBInstance, id string) []string {
+	others := []string{}
+	if cluster == nil {
+		return others
+	}
+
+	if instance != nil {
+		id = aws.StringValue(instance.DBInstanceIdentifier)
+	}
+	for _, m := range cluster.DBClusterMembers {
+		if aws.StringValue(m.DBInstanceIdentifier) != id {
+			others = append(others, aws.StringValue(m.DBInstanceIdentifier))
+		}
+	}
+
+	return others
+}
+
+// clusterDeleteCascade deletes all of the members of a database cluster and, once they are gone, the cluster
+// itself with an optional final snapshot.  The policy has to allow deleting the members and the cluster.
+func (s *server) clusterDeleteCascade(c buffalo.Context, orch *rdsOrchestrator, accountId, region string, cluster *rds.DBCluster, snapshot bool, policy string) error {
+	op := orch.operation
+	id := aws.StringValue(cluster.DBClusterIdentifier)
+
+	resp, err := orch.clusterMembersDelete(c, cluster)
+	if err != nil && len(resp.Members) == 0 {
+		op.fail(err)
+		return handleError(c, err)
+	}
+	resp.OperationID = op.id()
+	resp.Region = region
+
+	// the cluster is left in place when only some of its members were deleted, the watcher waits for those
+	// members to be gone and fails the operation with the error
+	if err != nil {
+		log.Printf("deleted %d of %d members of cluster %s: %s", len(resp.Members), len(cluster.DBClusterMembers), id, err)
+		op.setError(err)
+
+		waits := []operationWait{}
+		for _, m := range resp.Members {
+			waits = append(waits, waitInstanceDeleted(aws.StringValue(m.DBInstanceIdentifier)))
+		}
+		s.watchOperation(op, s.readOnlyClient(accountId, region), operationDeleted, waits...)
+
+		return c.Render(200, r.JSON(resp))
+	}
+
+	if snapshot {
+		resp.FinalSnapshotIdentifier = finalSnapshotIdentifier(id, time.Now())
+	}
+
+	waits := []operationWait{}
+	for _, m := range resp.Members {
+		waits = append(waits, waitInstanceDeleted(aws.StringValue(m.DBInstanceIdentifier)))
+	}
+	waits = append(waits, deleteCluster(id, resp.FinalSnapshotIdentifier), waitClusterDeleted(id))
+	s.watchOperation(op, s.scopedClient(accountId, region, policy), operationDeleted, waits...)
 
 	return c.Render(200, r.JSON(resp))
 }
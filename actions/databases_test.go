@@ -0,0 +1,69 @@
+package actions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+func TestClusterOtherMembers(t *testing.T) {
+	cluster := &rds.DBCluster{
+		DBClusterIdentifier: aws.String("mydb"),
+		DBClusterMembers: []*rds.DBClusterMember{
+			{DBInstanceIdentifier: aws.String("mydb"), IsClusterWriter: aws.Bool(true)},
+			{DBInstanceIdentifier: aws.String("mydb-1")},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		cluster  *rds.DBCluster
+		instance *rds.DBInstance
+		id       string
+		want     []string
+	}{
+		{
+			name: "instance",
+			id:   "mydb",
+			want: []string{},
+		},
+		{
+			name:     "cluster member",
+			cluster:  cluster,
+			instance: &rds.DBInstance{DBInstanceIdentifier: aws.String("mydb-1")},
+			id:       "mydb-1",
+			want:     []string{"mydb"},
+		},
+		{
+			name:    "cluster and writer share a name",
+			cluster: cluster,
+			id:      "mydb",
+			want:    []string{"mydb-1"},
+		},
+		{
+			name: "single member cluster and writer share a name",
+			cluster: &rds.DBCluster{
+				DBClusterIdentifier: aws.String("mydb"),
+				DBClusterMembers:    []*rds.DBClusterMember{{DBInstanceIdentifier: aws.String("mydb")}},
+			},
+			id:   "mydb",
+			want: []string{},
+		},
+		{
+			name:    "cluster with other names",
+			cluster: cluster,
+			id:      "mycluster",
+			want:    []string{"mydb", "mydb-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clusterOtherMembers(tt.cluster, tt.instance, tt.id); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("clusterOtherMembers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
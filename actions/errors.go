@@ -339,6 +339,12 @@ func ErrCode(msg string, err error) error {
 			// DBInstanceIdentifier doesn't refer to an existing DB instance.
 			rds.ErrCodeDBInstanceNotFoundFault,
 
+			// ErrCodeDBLogFileNotFoundFault for service response error code
+			// "DBLogFileNotFoundFault".
+			//
+			// LogFileName doesn't refer to an existing DB log file.
+			rds.ErrCodeDBLogFileNotFoundFault,
+
 			// ErrCodeGlobalClusterNotFoundFault for service response error code
 			// "GlobalClusterNotFoundFault".
 			//
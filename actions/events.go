@@ -0,0 +1,257 @@
+package actions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/gobuffalo/buffalo"
+	"github.com/pkg/errors"
+)
+
+// defaultEventsSince is how far back events are listed if the `since` parameter isn't given.
+// RDS keeps events for 14 days.
+const defaultEventsSince = 24 * time.Hour
+
+// DatabasesEvents lists the events of a database in a given account: the events of the cluster and its members,
+// and/or of the instance and the cluster it belongs to.  The events are sorted oldest first and can be filtered
+// with the `since` (RFC3339) and `category` parameters, and paged with the `limit` and `cursor` parameters.
+func (s *server) DatabasesEvents(c buffalo.Context) error {
+	since, categories, err := eventParams(c)
+	if err != nil {
+		return c.Error(400, err)
+	}
+
+	limit, after, err := pageParams(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	since, until, after, err := eventsWindow(since, after)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	rdsClient, err := s.eventsClient(c, accountId, region)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	if err := s.ensureDatabaseOrg(c, rdsClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	cluster, instance, err := rdsClient.DescribeDatabase(c, c.Param("db"))
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to describe database", err))
+	}
+
+	sources := []rdsapi.EventSource{}
+	clusterId := ""
+	if cluster != nil {
+		clusterId = aws.StringValue(cluster.DBClusterIdentifier)
+	} else if instance != nil {
+		clusterId = aws.StringValue(instance.DBClusterIdentifier)
+	}
+	if clusterId != "" {
+		sources = append(sources, rdsapi.EventSource{Identifier: clusterId, SourceType: rds.SourceTypeDbCluster})
+	}
+
+	instances := map[string]bool{}
+	if instance != nil {
+		instances[aws.StringValue(instance.DBInstanceIdentifier)] = true
+	}
+	if cluster != nil {
+		for _, m := range cluster.DBClusterMembers {
+			instances[aws.StringValue(m.DBInstanceIdentifier)] = true
+		}
+	}
+	for id := range instances {
+		sources = append(sources, rdsapi.EventSource{Identifier: id, SourceType: rds.SourceTypeDbInstance})
+	}
+
+	events, err := rdsClient.ListEvents(c, since, until, categories, sources...)
+	if err != nil {
+		return handleError(c, ErrCode("failed to describe events", err))
+	}
+
+	return renderEvents(c, events, since, until, limit, after)
+}
+
+// SnapshotsEvents lists the events of a cluster and/or instance snapshot in a given account, with the same
+// parameters as the database events
+func (s *server) SnapshotsEvents(c buffalo.Context) error {
+	since, categories, err := eventParams(c)
+	if err != nil {
+		return c.Error(400, err)
+	}
+
+	limit, after, err := pageParams(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	since, until, after, err := eventsWindow(since, after)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	policy, err := generatePolicy("rds:DescribeDBClusterSnapshots", "rds:DescribeDBSnapshots", "rds:DescribeEvents")
+	if err != nil {
+		return handleError(c, err)
+	}
+	rdsClient, err := s.scopedClient(accountId, region, policy)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	tags, err := rdsClient.SnapshotTags(c, c.Param("snap"))
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to describe snapshot", err))
+	}
+
+	// the snapshots are keyed by ARN, which tells cluster snapshots from instance snapshots
+	sources := []rdsapi.EventSource{}
+	for arn, t := range tags {
+		if !s.ownedByOrg(t) {
+			msg := fmt.Sprintf("snapshot %s doesn't belong to org %s", c.Param("snap"), s.org)
+			return handleError(c, apierror.New(apierror.ErrForbidden, msg, nil))
+		}
+
+		sourceType := rds.SourceTypeDbSnapshot
+		if strings.Contains(arn, ":cluster-snapshot:") {
+			sourceType = rds.SourceTypeDbClusterSnapshot
+		}
+		sources = append(sources, rdsapi.EventSource{Identifier: c.Param("snap"), SourceType: sourceType})
+	}
+
+	events, err := rdsClient.ListEvents(c, since, until, categories, sources...)
+	if err != nil {
+		return handleError(c, ErrCode("failed to describe events", err))
+	}
+
+	return renderEvents(c, events, since, until, limit, after)
+}
+
+// eventsClient returns a read only rds client for the given account, that can describe database events
+func (s *server) eventsClient(c buffalo.Context, accountId, region string) (*rdsapi.Client, error) {
+	policy, err := generatePolicy("rds:DescribeDBClusters", "rds:DescribeDBInstances", "rds:DescribeEvents")
+	if err != nil {
+		return nil, err
+	}
+	return s.scopedClient(accountId, region, policy)(c)
+}
+
+// eventParams parses the `since` and `category` query parameters.  Categories can be given as a comma
+// separated list and/or by repeating the parameter.
+func eventParams(c buffalo.Context) (time.Time, []string, error) {
+	since := time.Now().Add(-defaultEventsSince)
+	if p := c.Param("since"); p != "" {
+		t, err := time.Parse(time.RFC3339, p)
+		if err != nil {
+			return time.Time{}, nil, errors.New("Bad request: since must be an RFC3339 timestamp")
+		}
+		since = t
+	}
+
+	categories := []string{}
+	for _, p := range c.Request().URL.Query()["category"] {
+		for _, category := range strings.Split(p, ",") {
+			if category = strings.TrimSpace(category); category != "" {
+				categories = append(categories, category)
+			}
+		}
+	}
+
+	return since, categories, nil
+}
+
+// eventsWindow returns the time window of the events to list, and the key of the last event of the previous page.
+// The window is pinned in the cursor on the first page, so events happening while paging don't shift the pages.
+func eventsWindow(since time.Time, after string) (time.Time, time.Time, string, error) {
+	if after == "" {
+		return since, time.Now().UTC(), "", nil
+	}
+
+	parts := strings.SplitN(after, "|", 3)
+	if len(parts) != 3 {
+		return time.Time{}, time.Time{}, "", apierror.New(apierror.ErrBadRequest, "invalid cursor", nil)
+	}
+
+	cursorSince, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, time.Time{}, "", apierror.New(apierror.ErrBadRequest, "invalid cursor", err)
+	}
+	until, err := time.Parse(time.RFC3339Nano, parts[1])
+	if err != nil {
+		return time.Time{}, time.Time{}, "", apierror.New(apierror.ErrBadRequest, "invalid cursor", err)
+	}
+
+	return cursorSince, until, parts[2], nil
+}
+
+// eventKey returns the key used for sorting and paging events, from the fields of the event that don't change
+func eventKey(e *rds.Event) string {
+	return fmt.Sprintf("%s/%s/%s/%s",
+		aws.TimeValue(e.Date).UTC().Format("2006-01-02T15:04:05.000000000Z"),
+		aws.StringValue(e.SourceType),
+		aws.StringValue(e.SourceIdentifier),
+		aws.StringValue(e.Message),
+	)
+}
+
+// pageEvents returns a page of the given events sorted oldest first, the total number of events and the cursor
+// for the next page, which has the time window of the events
+func pageEvents(events []*rds.Event, since, until time.Time, limit int, after string) ([]*rds.Event, int, string) {
+	sort.SliceStable(events, func(i, j int) bool { return eventKey(events[i]) < eventKey(events[j]) })
+
+	// identical events have the same key, and are only listed once so the keys are unique
+	unique := []*rds.Event{}
+	keys := []string{}
+	for _, e := range events {
+		key := eventKey(e)
+		if len(keys) > 0 && keys[len(keys)-1] == key {
+			continue
+		}
+		unique = append(unique, e)
+		keys = append(keys, key)
+	}
+
+	start, end, next := paginate(keys, limit, after)
+	if next != "" {
+		next = since.UTC().Format(time.RFC3339Nano) + "|" + until.UTC().Format(time.RFC3339Nano) + "|" + next
+	}
+
+	return unique[start:end], len(keys), next
+}
+
+// renderEvents renders a page of the given events, sorted oldest first
+func renderEvents(c buffalo.Context, events []*rds.Event, since, until time.Time, limit int, after string) error {
+	page, total, next := pageEvents(events, since, until, limit, after)
+
+	output := struct {
+		Events []*rds.Event
+	}{
+		Events: page,
+	}
+
+	setPageHeaders(c, len(page), total, next)
+	return c.Render(200, r.JSON(output))
+}
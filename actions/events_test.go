@@ -0,0 +1,74 @@
+package actions
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/YaleSpinup/apierror"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+func TestPageEvents(t *testing.T) {
+	event := func(source, message string, minute int) *rds.Event {
+		return &rds.Event{
+			Date:             aws.Time(time.Date(2024, 1, 1, 12, minute, 0, 0, time.UTC)),
+			SourceType:       aws.String(rds.SourceTypeDbInstance),
+			SourceIdentifier: aws.String(source),
+			Message:          aws.String(message),
+		}
+	}
+	messages := func(events []*rds.Event) []string {
+		m := []string{}
+		for _, e := range events {
+			m = append(m, aws.StringValue(e.Message))
+		}
+		return m
+	}
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	events := []*rds.Event{
+		event("mydb-1", "instance created", 0),
+		event("mydb-2", "instance created", 0),
+		event("mydb-1", "instance rebooted", 10),
+		event("mydb-1", "instance rebooted", 10),
+		event("mydb-2", "backing up instance", 20),
+	}
+
+	page, total, next := pageEvents(events, since, until, 2, "")
+	if got := messages(page); !reflect.DeepEqual(got, []string{"instance created", "instance created"}) || total != 4 {
+		t.Fatalf("unexpected first page %v of %d events", got, total)
+	}
+
+	cursorSince, cursorUntil, after, err := eventsWindow(time.Now(), next)
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if !cursorSince.Equal(since) || !cursorUntil.Equal(until) {
+		t.Errorf("expected the window to be pinned in the cursor, got %s - %s", cursorSince, cursorUntil)
+	}
+
+	// the oldest event dropping out of the list doesn't shift the next page
+	page, _, next = pageEvents(events[1:], cursorSince, cursorUntil, 2, after)
+	if got := messages(page); !reflect.DeepEqual(got, []string{"instance rebooted", "backing up instance"}) || next != "" {
+		t.Errorf("unexpected second page %v, next cursor %q", got, next)
+	}
+}
+
+func TestEventsWindow(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	got, until, after, err := eventsWindow(since, "")
+	if err != nil || !got.Equal(since) || after != "" || time.Since(until) > time.Minute {
+		t.Errorf("expected the window from since until now on the first page, got %s - %s, %q, %v", got, until, after, err)
+	}
+
+	for _, cursor := range []string{"2024-01-01T00:00:00Z", "yesterday|today|key", "2024-01-01T00:00:00Z|today|key"} {
+		_, _, _, err := eventsWindow(since, cursor)
+		if aerr, ok := err.(apierror.Error); !ok || aerr.Code != apierror.ErrBadRequest {
+			t.Errorf("expected bad request for cursor %q, got %v", cursor, err)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/YaleSpinup/apierror"
+	"github.com/YaleSpinup/rds-api/pkg/kms"
+	"github.com/aws/aws-sdk-go/aws"
+	log "github.com/sirupsen/logrus"
+)
+
+// kmsClient returns a kms client for the given account and region that can only describe keys and list their aliases
+func (s *server) kmsClient(ctx context.Context, accountId, region string) (*kms.KMS, error) {
+	policy, err := generatePolicy("kms:DescribeKey", "kms:ListAliases")
+	if err != nil {
+		return nil, err
+	}
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	session, err := s.assumeRole(
+		ctx,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return nil, apierror.New(apierror.ErrForbidden, msg, err)
+	}
+
+	client := kms.New(kms.WithSession(session.Session))
+	return &client, nil
+}
+
+// defaultKmsKeyId returns the configured default KMS key for the given account name or number, if any.  KMS keys
+// are regional, so the default key is only returned for the region of the account.
+func (s *server) defaultKmsKeyId(account, region string) *string {
+	if region != s.mapAccountRegion(account, "") {
+		log.Debugf("not using the default kms key of account %s in region %s", account, region)
+		return nil
+	}
+
+	if key, ok := s.defaultConfig.DefaultKmsKeyId[account]; ok && key != "" {
+		return aws.String(key)
+	}
+	if key, ok := s.defaultConfig.DefaultKmsKeyId[s.mapAccountNumber(account)]; ok && key != "" {
+		return aws.String(key)
+	}
+	return nil
+}
+
+// setDefaultKmsKeys sets the default KMS key of the account on a new encrypted cluster or standalone instance that
+// doesn't specify one.  Databases restored from a snapshot keep the key of the snapshot unless one is given.
+func (s *server) setDefaultKmsKeys(account, region string, req *DatabaseCreateRequest) {
+	if req.Cluster != nil && req.Cluster.SnapshotIdentifier == nil && req.Cluster.KmsKeyId == nil &&
+		(req.Cluster.StorageEncrypted == nil || aws.BoolValue(req.Cluster.StorageEncrypted)) {
+		req.Cluster.KmsKeyId = s.defaultKmsKeyId(account, region)
+	}
+
+	if req.Instance != nil && req.Instance.SnapshotIdentifier == nil && req.Instance.DBClusterIdentifier == nil && req.Instance.KmsKeyId == nil &&
+		(req.Instance.StorageEncrypted == nil || aws.BoolValue(req.Instance.StorageEncrypted)) {
+		req.Instance.KmsKeyId = s.defaultKmsKeyId(account, region)
+	}
+}
+
+// validateCreateKmsKeys checks that the KMS keys in a database create request are given where RDS can use them
+func validateCreateKmsKeys(req *DatabaseCreateRequest) error {
+	if req.Cluster != nil && req.Cluster.KmsKeyId != nil && req.Cluster.StorageEncrypted != nil && !aws.BoolValue(req.Cluster.StorageEncrypted) {
+		return errors.New("Cluster.KmsKeyId cannot be specified with StorageEncrypted false")
+	}
+
+	if req.Instance != nil && req.Instance.KmsKeyId != nil {
+		switch {
+		case req.Instance.DBClusterIdentifier != nil || req.Cluster != nil:
+			return errors.New("Instance.KmsKeyId cannot be specified for a cluster member, the key is set on the cluster")
+		case req.Instance.SnapshotIdentifier != nil:
+			return errors.New("Instance.KmsKeyId cannot be specified when restoring an instance snapshot, copy the snapshot with the key first")
+		case req.Instance.StorageEncrypted != nil && !aws.BoolValue(req.Instance.StorageEncrypted):
+			return errors.New("Instance.KmsKeyId cannot be specified with StorageEncrypted false")
+		}
+	}
+
+	return nil
+}
+
+// validateRestoreKmsKey checks that the KMS key in a point in time restore request can be used for the source database
+func validateRestoreKmsKey(sourceIsCluster bool, req *DatabaseRestoreRequest) error {
+	if req.KmsKeyId != nil && !sourceIsCluster {
+		return apierror.New(apierror.ErrBadRequest, "KmsKeyId can only be specified when restoring a cluster", nil)
+	}
+	return nil
+}
+
+// ensureKmsKey checks that the given KMS key is an enabled encryption key in the account and
+// returns its ARN, so the session policy can be scoped to the key even if it's given by alias
+func ensureKmsKey(ctx context.Context, client *kms.KMS, kmsKeyId *string) (*string, error) {
+	if kmsKeyId == nil {
+		return nil, nil
+	}
+
+	key, err := client.EnsureEncryptionKey(ctx, aws.StringValue(kmsKeyId))
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return nil, err
+		}
+		return nil, ErrCode("failed to describe kms key", err)
+	}
+
+	log.Debugf("using kms key %s for %s", aws.StringValue(key.Arn), aws.StringValue(kmsKeyId))
+
+	return key.Arn, nil
+}
+
+// ensureCreateKmsKeys checks the KMS keys in a database create request, and replaces them with their ARN.  The session
+// policy allows the keys by ARN, and IAM doesn't authorize using a key by its alias ARN.
+func (s *server) ensureCreateKmsKeys(ctx context.Context, accountId, region string, req *DatabaseCreateRequest) error {
+	if (req.Cluster == nil || (req.Cluster.KmsKeyId == nil && req.Cluster.MasterUserSecretKmsKeyId == nil)) &&
+		(req.Instance == nil || (req.Instance.KmsKeyId == nil && req.Instance.MasterUserSecretKmsKeyId == nil)) {
+		return nil
+	}
+
+	client, err := s.kmsClient(ctx, accountId, region)
+	if err != nil {
+		return err
+	}
+
+	if req.Cluster != nil {
+		if req.Cluster.KmsKeyId, err = ensureKmsKey(ctx, client, req.Cluster.KmsKeyId); err != nil {
+			return err
+		}
+		if req.Cluster.MasterUserSecretKmsKeyId, err = ensureKmsKey(ctx, client, req.Cluster.MasterUserSecretKmsKeyId); err != nil {
+			return err
+		}
+	}
+
+	if req.Instance != nil {
+		if req.Instance.KmsKeyId, err = ensureKmsKey(ctx, client, req.Instance.KmsKeyId); err != nil {
+			return err
+		}
+		if req.Instance.MasterUserSecretKmsKeyId, err = ensureKmsKey(ctx, client, req.Instance.MasterUserSecretKmsKeyId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensureModifyKmsKeys checks the KMS key for the master user secret in a database modify request, and replaces it with its ARN
+func (s *server) ensureModifyKmsKeys(ctx context.Context, accountId, region string, input *DatabaseModifyInput) error {
+	if (input.Cluster == nil || input.Cluster.MasterUserSecretKmsKeyId == nil) && (input.Instance == nil || input.Instance.MasterUserSecretKmsKeyId == nil) {
+		return nil
+	}
+
+	client, err := s.kmsClient(ctx, accountId, region)
+	if err != nil {
+		return err
+	}
+
+	if input.Cluster != nil {
+		if input.Cluster.MasterUserSecretKmsKeyId, err = ensureKmsKey(ctx, client, input.Cluster.MasterUserSecretKmsKeyId); err != nil {
+			return err
+		}
+	}
+
+	if input.Instance != nil {
+		if input.Instance.MasterUserSecretKmsKeyId, err = ensureKmsKey(ctx, client, input.Instance.MasterUserSecretKmsKeyId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// kmsKeys describes the given KMS keys, which is best effort since keys may be shared from other
+// accounts or not be described by the role.  Keys that can't be described only have their ARN.
+func kmsKeys(ctx context.Context, client *kms.KMS, keyIds ...string) map[string]*KmsKeyResponse {
+	keys := map[string]*KmsKeyResponse{}
+	for _, id := range keyIds {
+		if id == "" {
+			continue
+		}
+		if _, ok := keys[id]; ok {
+			continue
+		}
+
+		key, err := client.DescribeKey(ctx, id)
+		if err != nil {
+			log.Warnf("failed to describe kms key %s: %s", id, err)
+			keys[id] = &KmsKeyResponse{Arn: id}
+			continue
+		}
+
+		aliases, err := client.ListKeyAliases(ctx, aws.StringValue(key.KeyId))
+		if err != nil {
+			log.Warnf("failed to list aliases of kms key %s: %s", id, err)
+		}
+
+		keys[id] = &KmsKeyResponse{
+			KeyId:      aws.StringValue(key.KeyId),
+			Arn:        aws.StringValue(key.Arn),
+			KeyManager: aws.StringValue(key.KeyManager),
+			KeyState:   aws.StringValue(key.KeyState),
+			Aliases:    aliases,
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,260 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/YaleSpinup/aws-go/services/iam"
+	"github.com/YaleSpinup/rds-api/pkg/common"
+	"github.com/YaleSpinup/rds-api/pkg/kms"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	awskms "github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+type mockKMSClient struct {
+	kmsiface.KMSAPI
+	keys map[string]*awskms.KeyMetadata
+}
+
+func (m *mockKMSClient) DescribeKeyWithContext(_ aws.Context, input *awskms.DescribeKeyInput, _ ...request.Option) (*awskms.DescribeKeyOutput, error) {
+	key, ok := m.keys[aws.StringValue(input.KeyId)]
+	if !ok {
+		return nil, awserr.New(awskms.ErrCodeNotFoundException, "not found", nil)
+	}
+	return &awskms.DescribeKeyOutput{KeyMetadata: key}, nil
+}
+
+func (m *mockKMSClient) ListAliasesPagesWithContext(_ aws.Context, input *awskms.ListAliasesInput, fn func(*awskms.ListAliasesOutput, bool) bool, _ ...request.Option) error {
+	fn(&awskms.ListAliasesOutput{Aliases: []*awskms.AliasListEntry{{AliasName: aws.String("alias/rds")}}}, true)
+	return nil
+}
+
+func TestValidateCreateKmsKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *DatabaseCreateRequest
+		wantErr bool
+	}{
+		{
+			name: "cluster key",
+			req:  &DatabaseCreateRequest{Cluster: &CreateDBClusterInput{KmsKeyId: aws.String("alias/rds")}},
+		},
+		{
+			name:    "cluster key unencrypted",
+			req:     &DatabaseCreateRequest{Cluster: &CreateDBClusterInput{KmsKeyId: aws.String("alias/rds"), StorageEncrypted: aws.Bool(false)}},
+			wantErr: true,
+		},
+		{
+			name: "instance key",
+			req:  &DatabaseCreateRequest{Instance: &CreateDBInstanceInput{KmsKeyId: aws.String("alias/rds")}},
+		},
+		{
+			name: "instance key in cluster",
+			req: &DatabaseCreateRequest{
+				Cluster:  &CreateDBClusterInput{},
+				Instance: &CreateDBInstanceInput{KmsKeyId: aws.String("alias/rds"), DBClusterIdentifier: aws.String("mycluster")},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "instance key from snapshot",
+			req:     &DatabaseCreateRequest{Instance: &CreateDBInstanceInput{KmsKeyId: aws.String("alias/rds"), SnapshotIdentifier: aws.String("snap")}},
+			wantErr: true,
+		},
+		{
+			name:    "instance key unencrypted",
+			req:     &DatabaseCreateRequest{Instance: &CreateDBInstanceInput{KmsKeyId: aws.String("alias/rds"), StorageEncrypted: aws.Bool(false)}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateCreateKmsKeys(tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("validateCreateKmsKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetDefaultKmsKeys(t *testing.T) {
+	s := &server{
+		accountsMap: map[string]common.MappedAccount{
+			"spinup": {Id: "0123456789"},
+			"dr":     {Id: "0123456789", Region: "us-west-2"},
+		},
+		defaultConfig: common.CommonConfig{
+			DefaultKmsKeyId: map[string]string{"0123456789": "alias/default", "dr": "alias/dr"},
+		},
+	}
+
+	req := &DatabaseCreateRequest{
+		Cluster:  &CreateDBClusterInput{},
+		Instance: &CreateDBInstanceInput{DBClusterIdentifier: aws.String("mycluster")},
+	}
+	s.setDefaultKmsKeys("spinup", "us-east-1", req)
+	if aws.StringValue(req.Cluster.KmsKeyId) != "alias/default" {
+		t.Errorf("expected default cluster key, got %v", req.Cluster.KmsKeyId)
+	}
+	if req.Instance.KmsKeyId != nil {
+		t.Errorf("expected no key on cluster member, got %s", aws.StringValue(req.Instance.KmsKeyId))
+	}
+
+	req = &DatabaseCreateRequest{Instance: &CreateDBInstanceInput{KmsKeyId: aws.String("alias/mine")}}
+	s.setDefaultKmsKeys("spinup", "us-east-1", req)
+	if aws.StringValue(req.Instance.KmsKeyId) != "alias/mine" {
+		t.Errorf("expected given key to be kept, got %s", aws.StringValue(req.Instance.KmsKeyId))
+	}
+
+	for _, req := range []*DatabaseCreateRequest{
+		{Instance: &CreateDBInstanceInput{StorageEncrypted: aws.Bool(false)}},
+		{Instance: &CreateDBInstanceInput{SnapshotIdentifier: aws.String("snap")}},
+	} {
+		s.setDefaultKmsKeys("spinup", "us-east-1", req)
+		if req.Instance.KmsKeyId != nil {
+			t.Errorf("expected no default key, got %s", aws.StringValue(req.Instance.KmsKeyId))
+		}
+	}
+
+	req = &DatabaseCreateRequest{Instance: &CreateDBInstanceInput{}}
+	s.setDefaultKmsKeys("other", "us-east-1", req)
+	if req.Instance.KmsKeyId != nil {
+		t.Errorf("expected no default key for other account, got %s", aws.StringValue(req.Instance.KmsKeyId))
+	}
+
+	req = &DatabaseCreateRequest{Instance: &CreateDBInstanceInput{}}
+	s.setDefaultKmsKeys("spinup", "us-west-2", req)
+	if req.Instance.KmsKeyId != nil {
+		t.Errorf("expected no default key in other region, got %s", aws.StringValue(req.Instance.KmsKeyId))
+	}
+
+	req = &DatabaseCreateRequest{Instance: &CreateDBInstanceInput{}}
+	s.setDefaultKmsKeys("dr", "us-west-2", req)
+	if aws.StringValue(req.Instance.KmsKeyId) != "alias/dr" {
+		t.Errorf("expected default key of the account name for its region, got %v", req.Instance.KmsKeyId)
+	}
+}
+
+func TestKmsKeys(t *testing.T) {
+	arn := "arn:aws:kms:us-east-1:0123456789:key/1234"
+	client := kms.KMS{Service: &mockKMSClient{
+		keys: map[string]*awskms.KeyMetadata{
+			arn: {
+				Arn:        aws.String(arn),
+				KeyId:      aws.String("1234"),
+				KeyManager: aws.String("CUSTOMER"),
+				KeyState:   aws.String("Enabled"),
+			},
+		},
+	}}
+
+	other := "arn:aws:kms:us-east-1:9876543210:key/5678"
+	got := kmsKeys(context.TODO(), &client, arn, "", arn, other)
+
+	expected := map[string]*KmsKeyResponse{
+		arn: {
+			KeyId:      "1234",
+			Arn:        arn,
+			KeyManager: "CUSTOMER",
+			KeyState:   "Enabled",
+			Aliases:    []string{"alias/rds"},
+		},
+		other: {Arn: other},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestDatabaseCreatePolicyKmsKey(t *testing.T) {
+	s := &server{org: "localdev"}
+
+	arn := "arn:aws:kms:us-east-1:0123456789:key/1234"
+	policy, err := s.databaseCreatePolicy("0123456789", &DatabaseCreateRequest{
+		Instance: &CreateDBInstanceInput{
+			DBInstanceIdentifier: aws.String("myinstance"),
+			KmsKeyId:             aws.String(arn),
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	doc := iam.PolicyDocument{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		t.Fatalf("failed to unmarshal policy: %s", err)
+	}
+
+	found := false
+	for _, s := range doc.Statement {
+		if reflect.DeepEqual(s.Resource, iam.Value{arn}) {
+			found = true
+			if !reflect.DeepEqual(s.Action, iam.Value{"kms:CreateGrant", "kms:Decrypt", "kms:DescribeKey", "kms:GenerateDataKey"}) {
+				t.Errorf("unexpected kms actions %v", s.Action)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected a statement scoped to the kms key, got %+v", doc.Statement)
+	}
+}
+
+func TestMasterUserSecretKmsKeyPolicy(t *testing.T) {
+	arn := "arn:aws:kms:us-east-1:0123456789:key/1234"
+	client := kms.KMS{Service: &mockKMSClient{
+		keys: map[string]*awskms.KeyMetadata{
+			"alias/secrets": {
+				Arn:      aws.String(arn),
+				KeyState: aws.String(awskms.KeyStateEnabled),
+				KeyUsage: aws.String(awskms.KeyUsageTypeEncryptDecrypt),
+				KeySpec:  aws.String(awskms.KeySpecSymmetricDefault),
+			},
+		},
+	}}
+
+	req := &DatabaseCreateRequest{
+		Instance: &CreateDBInstanceInput{
+			DBInstanceIdentifier:     aws.String("mydb"),
+			ManageMasterUserPassword: aws.Bool(true),
+			MasterUserSecretKmsKeyId: aws.String("alias/secrets"),
+		},
+	}
+
+	// IAM doesn't authorize using a key by its alias ARN, so the alias is resolved before the policy is generated
+	var err error
+	if req.Instance.MasterUserSecretKmsKeyId, err = ensureKmsKey(context.TODO(), &client, req.Instance.MasterUserSecretKmsKeyId); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	s := &server{org: "localdev"}
+	policy, err := s.databaseCreatePolicy("0123456789", req)
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	doc := iam.PolicyDocument{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		t.Fatalf("failed to unmarshal policy: %s", err)
+	}
+
+	found := false
+	for _, st := range doc.Statement {
+		for _, a := range st.Action {
+			if a != "kms:GenerateDataKey" {
+				continue
+			}
+			found = true
+			if !reflect.DeepEqual(st.Resource, iam.Value{arn}) {
+				t.Errorf("expected the secret key to be allowed by its key ARN, got %v", st.Resource)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected a statement for the secret key, got %s", policy)
+	}
+}
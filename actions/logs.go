@@ -0,0 +1,161 @@
+package actions
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/gobuffalo/buffalo"
+	"github.com/pkg/errors"
+)
+
+// LogsList lists the engine log files of a database instance in a given account
+func (s *server) LogsList(c buffalo.Context) error {
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	rdsClient, err := s.logsClient(c, accountId, region, c.Param("db"))
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	files, err := rdsClient.ListLogFiles(c, c.Param("db"))
+	if err != nil {
+		return handleError(c, ErrCode("failed to describe log files", err))
+	}
+
+	output := struct {
+		LogFiles []*rds.DescribeDBLogFilesDetails
+	}{
+		LogFiles: files,
+	}
+
+	return c.Render(200, r.JSON(output))
+}
+
+// LogsGet streams an engine log file of a database instance in a given account.  With the `tail` parameter
+// only the given number of lines from the end of the file are returned, and with the `maxBytes` parameter
+// the download stops after the given number of bytes.
+func (s *server) LogsGet(c buffalo.Context) error {
+	var tail, maxBytes int64
+	if t := c.Param("tail"); t != "" {
+		n, err := strconv.ParseInt(t, 10, 64)
+		if err != nil || n < 1 {
+			return c.Error(400, errors.New("Bad request: tail must be a positive integer"))
+		}
+		tail = n
+	}
+	if m := c.Param("maxBytes"); m != "" {
+		n, err := strconv.ParseInt(m, 10, 64)
+		if err != nil || n < 1 {
+			return c.Error(400, errors.New("Bad request: maxBytes must be a positive integer"))
+		}
+		maxBytes = n
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	rdsClient, err := s.logsClient(c, accountId, region, c.Param("db"))
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// the response is only started once the first portion is downloaded, so errors like a missing
+	// file still get an error status.  After that, the download can only be cut short.
+	res := c.Response()
+	var written int64
+	var started bool
+	var writeErr error
+	err = rdsClient.DownloadLogFilePages(c, c.Param("db"), c.Param("file"), tail, func(data string, lastPage bool) bool {
+		if !started {
+			res.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			res.WriteHeader(200)
+			started = true
+		}
+
+		if maxBytes > 0 && written+int64(len(data)) > maxBytes {
+			data = data[:maxBytes-written]
+		}
+
+		n, err := res.Write([]byte(data))
+		written += int64(n)
+		if err != nil {
+			writeErr = err
+			return false
+		}
+
+		if f, ok := res.(http.Flusher); ok {
+			f.Flush()
+		}
+
+		return maxBytes == 0 || written < maxBytes
+	})
+	if err != nil {
+		if !started {
+			return handleError(c, ErrCode("failed to download log file", err))
+		}
+		log.Printf("failed to download log file %s of %s after %d bytes: %s", c.Param("file"), c.Param("db"), written, err)
+	}
+	if writeErr != nil {
+		log.Printf("failed to stream log file %s of %s after %d bytes: %s", c.Param("file"), c.Param("db"), written, writeErr)
+	}
+
+	return nil
+}
+
+// logsClient returns an rds client for the given account that can list and download the log files of the given
+// database, after checking that it belongs to the org and is an instance.  Clusters don't have log files of
+// their own, the ones of their members are listed instead.
+func (s *server) logsClient(c buffalo.Context, accountId, region, id string) (*rdsapi.Client, error) {
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.logsPolicy(accountId, id)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return nil, apierror.New(apierror.ErrForbidden, msg, err)
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	if err := s.ensureDatabaseOrg(c, rdsClient, id); err != nil {
+		return nil, err
+	}
+
+	cluster, instance, err := rdsClient.DescribeDatabase(c, id)
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return nil, err
+		}
+		return nil, ErrCode("failed to describe database", err)
+	}
+
+	if instance == nil {
+		members := []string{}
+		if cluster != nil {
+			for _, m := range cluster.DBClusterMembers {
+				members = append(members, aws.StringValue(m.DBInstanceIdentifier))
+			}
+		}
+		msg := fmt.Sprintf("database %s is a cluster, get the log files of one of its members: %s", id, strings.Join(members, ", "))
+		return nil, apierror.New(apierror.ErrBadRequest, msg, nil)
+	}
+
+	return rdsClient, nil
+}
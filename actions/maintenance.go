@@ -0,0 +1,206 @@
+package actions
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/gobuffalo/buffalo"
+	"github.com/pkg/errors"
+)
+
+// MaintenanceList lists the pending maintenance actions for a database cluster and/or instance in a given account
+func (s *server) MaintenanceList(c buffalo.Context) error {
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	rdsClient, err := s.maintenanceClient(c, accountId, region)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	if err := s.ensureDatabaseOrg(c, rdsClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	arns, err := rdsClient.DetermineArn(c.Param("db"))
+	if err != nil {
+		return handleError(c, apierror.New(apierror.ErrNotFound, err.Error(), nil))
+	}
+
+	actions, err := rdsClient.ListPendingMaintenanceActions(c, arns...)
+	if err != nil {
+		return handleError(c, ErrCode("failed to describe pending maintenance actions", err))
+	}
+
+	output := struct {
+		PendingMaintenanceActions []*rds.ResourcePendingMaintenanceActions
+	}{
+		PendingMaintenanceActions: actions,
+	}
+
+	return c.Render(200, r.JSON(output))
+}
+
+// MaintenanceListAll lists the pending maintenance actions for all of the databases belonging to the org in a given
+// account.  With the before parameter (RFC3339), only actions forced or automatically applied before then are listed.
+func (s *server) MaintenanceListAll(c buffalo.Context) error {
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	var before time.Time
+	if b := c.Param("before"); b != "" {
+		var err error
+		if before, err = time.Parse(time.RFC3339, b); err != nil {
+			return c.Error(400, errors.New("Bad request: before must be an RFC3339 timestamp"))
+		}
+	}
+
+	rdsClient, err := s.maintenanceClient(c, accountId, region)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// pending actions only have the resource ARN, so the org's databases are listed to filter them
+	owned := map[string]bool{}
+	clusters, err := rdsClient.ListDBClusters(c, nil)
+	if err != nil {
+		return handleError(c, ErrCode("failed to list database clusters", err))
+	}
+	for _, cl := range clusters {
+		owned[aws.StringValue(cl.DBClusterArn)] = s.ownedByOrg(cl.TagList)
+	}
+
+	instances, err := rdsClient.ListDBInstances(c, nil)
+	if err != nil {
+		return handleError(c, ErrCode("failed to list database instances", err))
+	}
+	for _, i := range instances {
+		owned[aws.StringValue(i.DBInstanceArn)] = s.ownedByOrg(i.TagList)
+	}
+
+	pending, err := rdsClient.ListPendingMaintenanceActions(c)
+	if err != nil {
+		return handleError(c, ErrCode("failed to describe pending maintenance actions", err))
+	}
+
+	actions := []*rds.ResourcePendingMaintenanceActions{}
+	for _, a := range pending {
+		if owned[aws.StringValue(a.ResourceIdentifier)] {
+			actions = append(actions, a)
+		}
+	}
+
+	if !before.IsZero() {
+		actions = rdsapi.MaintenanceDueBefore(actions, before)
+	}
+
+	output := struct {
+		PendingMaintenanceActions []*rds.ResourcePendingMaintenanceActions
+	}{
+		PendingMaintenanceActions: actions,
+	}
+
+	return c.Render(200, r.JSON(output))
+}
+
+// MaintenanceApply applies a pending maintenance action for a database cluster and/or instance in a given account,
+// either immediately or at the next maintenance window, or undoes an earlier opt in
+func (s *server) MaintenanceApply(c buffalo.Context) error {
+	req := MaintenanceApplyRequest{}
+	if err := c.Bind(&req); err != nil {
+		log.Println(err)
+		return c.Error(400, err)
+	}
+
+	if req.ApplyAction == "" {
+		return c.Error(400, errors.New("Bad request: specify ApplyAction in request"))
+	}
+
+	switch req.OptInType {
+	case rdsapi.OptInImmediate, rdsapi.OptInNextMaintenance, rdsapi.OptInUndo:
+	default:
+		msg := fmt.Sprintf("Bad request: OptInType must be one of %s, %s or %s", rdsapi.OptInImmediate, rdsapi.OptInNextMaintenance, rdsapi.OptInUndo)
+		return c.Error(400, errors.New(msg))
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.maintenanceApplyPolicy(accountId, c.Param("db"))
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	if err := s.ensureDatabaseOrg(c, rdsClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	arns, err := rdsClient.DetermineArn(c.Param("db"))
+	if err != nil {
+		return handleError(c, apierror.New(apierror.ErrNotFound, err.Error(), nil))
+	}
+
+	pending, err := rdsClient.ListPendingMaintenanceActions(c, arns...)
+	if err != nil {
+		return handleError(c, ErrCode("failed to describe pending maintenance actions", err))
+	}
+
+	// the action is applied to the cluster and/or instance that has it pending
+	applied := []*rds.ResourcePendingMaintenanceActions{}
+	for _, p := range pending {
+		for _, d := range p.PendingMaintenanceActionDetails {
+			if aws.StringValue(d.Action) != req.ApplyAction {
+				continue
+			}
+
+			out, err := rdsClient.ApplyPendingMaintenanceAction(c, aws.StringValue(p.ResourceIdentifier), req.ApplyAction, req.OptInType)
+			if err != nil {
+				return handleError(c, ErrCode("failed to apply pending maintenance action", err))
+			}
+			applied = append(applied, out)
+			break
+		}
+	}
+
+	if len(applied) == 0 {
+		msg := fmt.Sprintf("maintenance action %s is not pending for database %s", req.ApplyAction, c.Param("db"))
+		return handleError(c, apierror.New(apierror.ErrNotFound, msg, nil))
+	}
+
+	output := struct {
+		PendingMaintenanceActions []*rds.ResourcePendingMaintenanceActions
+	}{
+		PendingMaintenanceActions: applied,
+	}
+
+	return c.Render(200, r.JSON(output))
+}
+
+// maintenanceClient returns a read only rds client for the given account, that can describe pending maintenance actions
+func (s *server) maintenanceClient(c buffalo.Context, accountId, region string) (*rdsapi.Client, error) {
+	policy, err := generatePolicy("rds:DescribeDBClusters", "rds:DescribeDBInstances", "rds:DescribePendingMaintenanceActions")
+	if err != nil {
+		return nil, err
+	}
+	return s.scopedClient(accountId, region, policy)(c)
+}
@@ -0,0 +1,233 @@
+package actions
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/gobuffalo/buffalo"
+	"github.com/pkg/errors"
+)
+
+// ClusterMembersPost adds reader instances to a database cluster in a given account
+func (s *server) ClusterMembersPost(c buffalo.Context) error {
+	req := ClusterMembersRequest{}
+	if err := c.Bind(&req); err != nil {
+		log.Println(err)
+		return c.Error(400, err)
+	}
+
+	if req.Count < 1 {
+		return c.Error(400, errors.New("Bad request: specify a positive Count in request"))
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	// the policy is scoped to the cluster subnet and parameter groups, so look them up first with a read only session
+	readClient, err := s.readOnlyClient(accountId, region)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	cluster, members, err := s.clusterWithMembers(c, readClient, c.Param("db"))
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	// new readers are created like the existing members
+	instanceClass := req.DBInstanceClass
+	parameterGroup := ""
+	if len(members) > 0 {
+		if instanceClass == nil {
+			instanceClass = members[0].DBInstanceClass
+		}
+		if len(members[0].DBParameterGroups) > 0 {
+			parameterGroup = aws.StringValue(members[0].DBParameterGroups[0].DBParameterGroupName)
+		}
+	}
+
+	if instanceClass == nil {
+		return c.Error(400, errors.New("Bad request: specify DBInstanceClass in request"))
+	}
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.clusterMembersPolicy(accountId, aws.StringValue(cluster.DBClusterIdentifier), aws.StringValue(cluster.DBSubnetGroup), parameterGroup)
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	op := s.newOperation("add members", accountId, region, aws.StringValue(cluster.DBClusterIdentifier))
+	c.Response().Header().Set("X-Operation-Id", op.id())
+	op.setStatus(operationCreatingInstance)
+
+	orch := &rdsOrchestrator{
+		client:    rdsClient,
+		operation: op,
+	}
+
+	readers, err := orch.clusterReadersCreate(c, cluster, instanceClass, normalizeTags(fromRDSTags(cluster.TagList)), int(req.Count), members)
+	if err != nil && len(readers) == 0 {
+		op.fail(err)
+		return handleError(c, err)
+	}
+
+	resp := &DatabaseResponse{
+		Members:     readers,
+		OperationID: op.id(),
+		Region:      region,
+	}
+
+	// some of the readers were created, they're still watched before the operation fails
+	op.setError(err)
+	s.watchOperation(op, s.readOnlyClient(accountId, region), operationAvailable, databaseWaits(resp, operationCreatingCluster, operationCreatingInstance)...)
+
+	return c.Render(200, r.JSON(resp))
+}
+
+// ClusterMembersList lists the member instances of a database cluster in a given account
+func (s *server) ClusterMembersList(c buffalo.Context) error {
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	rdsClient, err := s.readOnlyClient(accountId, region)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	_, members, err := s.clusterWithMembers(c, rdsClient, c.Param("db"))
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	output := struct {
+		DBInstances []*rds.DBInstance
+	}{
+		DBInstances: members,
+	}
+
+	return c.Render(200, r.JSON(output))
+}
+
+// ClusterMembersDelete deletes a member instance of a database cluster in a given account.  The last
+// member of a cluster can't be deleted this way, the whole database has to be deleted instead.
+func (s *server) ClusterMembersDelete(c buffalo.Context) error {
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+	member := c.Param("member")
+
+	readClient, err := s.readOnlyClient(accountId, region)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	cluster, members, err := s.clusterWithMembers(c, readClient, c.Param("db"))
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	found := false
+	for _, m := range members {
+		if aws.StringValue(m.DBInstanceIdentifier) == member {
+			found = true
+		}
+	}
+	if !found {
+		msg := fmt.Sprintf("instance %s is not a member of cluster %s", member, aws.StringValue(cluster.DBClusterIdentifier))
+		return handleError(c, apierror.New(apierror.ErrNotFound, msg, nil))
+	}
+
+	if len(members) == 1 {
+		msg := fmt.Sprintf("instance %s is the last member of cluster %s, delete the database instead", member, aws.StringValue(cluster.DBClusterIdentifier))
+		return handleError(c, apierror.New(apierror.ErrConflict, msg, nil))
+	}
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.clusterMemberDeletePolicy(accountId, member)
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	op := s.newOperation("remove member", accountId, region, member)
+	c.Response().Header().Set("X-Operation-Id", op.id())
+
+	orch := &rdsOrchestrator{
+		client:    rdsClient,
+		operation: op,
+	}
+
+	instance, err := orch.clusterMemberDelete(c, member)
+	if err != nil {
+		op.fail(err)
+		return handleError(c, err)
+	}
+
+	resp := &DatabaseResponse{
+		Instance:    instance,
+		OperationID: op.id(),
+		Region:      region,
+	}
+
+	s.watchOperation(op, s.readOnlyClient(accountId, region), operationDeleted, waitInstanceDeleted(member))
+
+	return c.Render(200, r.JSON(resp))
+}
+
+// clusterWithMembers returns the database cluster with the given name and its member instances,
+// after checking that it belongs to the org
+func (s *server) clusterWithMembers(c buffalo.Context, client *rdsapi.Client, id string) (*rds.DBCluster, []*rds.DBInstance, error) {
+	if err := s.ensureDatabaseOrg(c, client, id); err != nil {
+		return nil, nil, err
+	}
+
+	cluster, _, err := client.DescribeDatabase(c, id)
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return nil, nil, err
+		}
+		return nil, nil, ErrCode("failed to describe database", err)
+	}
+
+	if cluster == nil {
+		msg := fmt.Sprintf("database %s is not a cluster", id)
+		return nil, nil, apierror.New(apierror.ErrBadRequest, msg, nil)
+	}
+
+	members, err := client.ListClusterMembers(c, aws.StringValue(cluster.DBClusterIdentifier))
+	if err != nil {
+		return nil, nil, ErrCode("failed to list cluster members", err)
+	}
+
+	return cluster, members, nil
+}
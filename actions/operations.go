@@ -0,0 +1,570 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/gobuffalo/buffalo"
+	"github.com/google/uuid"
+	"github.com/patrickmn/go-cache"
+)
+
+// operation statuses
+const (
+	operationPending          = "pending"
+	operationCreatingCluster  = "creating cluster"
+	operationCreatingInstance = "creating instance"
+	operationModifying        = "modifying"
+	operationStarting         = "starting"
+	operationStopping         = "stopping"
+	operationPromoting        = "promoting"
+	operationRebooting        = "rebooting"
+	operationFailingOver      = "failing over"
+	operationBackingUp        = "backing up"
+	operationCopying          = "copying"
+	operationUpgrading        = "upgrading"
+	operationDeleting         = "deleting"
+	operationAvailable        = "available"
+	operationStopped          = "stopped"
+	operationDeleted          = "deleted"
+	operationFailed           = "failed"
+	operationRolledBack       = "rolled back"
+)
+
+// operation step statuses
+const (
+	stepRunning  = "running"
+	stepComplete = "complete"
+	stepFailed   = "failed"
+)
+
+const (
+	// operationTimeout is the maximum amount of time a background watcher will wait for an operation to finish
+	operationTimeout = 2 * time.Hour
+
+	// operationRetention is how long an operation is kept in the operations cache after it's created or its
+	// watcher finishes, so its outcome can still be looked up
+	operationRetention = 24 * time.Hour
+
+	// copyOperationTimeout is the maximum amount of time a background watcher will wait for a snapshot copy, since
+	// copying a large snapshot to another region can take much longer than other operations
+	copyOperationTimeout = 24 * time.Hour
+
+	// waiterRoundAttempts and waiterDelay control a single round of the RDS waiters. Between rounds the rds
+	// client is refreshed, so a round has to be shorter than the 300s buffer on cached assumed role sessions.
+	waiterRoundAttempts = 8
+	waiterDelay         = 30 * time.Second
+)
+
+// operation tracks the progress of an asynchronous database operation.
+// All methods are safe to call on a nil operation, which makes tracking optional for the orchestrator.
+type operation struct {
+	mu   sync.Mutex
+	resp OperationResponse
+}
+
+// operationStep is a handle to a single step recorded on an operation
+type operationStep struct {
+	op    *operation
+	index int
+}
+
+// operationWait is a condition a background watcher waits for after the AWS call is accepted.  It can
+// also be an action that can only run once the previous conditions are met, like upgrading a database
+// after the pre-upgrade snapshot is available.
+type operationWait struct {
+	name   string
+	status string
+	wait   func(ctx context.Context, client *rdsapi.Client, opts ...request.WaiterOption) error
+	// cleanup waits still run when an earlier wait failed or the operation timed out, with their own timeout
+	cleanup bool
+}
+
+// newOperation creates a new pending operation and stores it in the operations cache
+func (s *server) newOperation(opType, account, region, database string) *operation {
+	now := time.Now().UTC()
+	op := &operation{
+		resp: OperationResponse{
+			ID:        uuid.New().String(),
+			Type:      opType,
+			Account:   account,
+			Region:    region,
+			Database:  database,
+			Status:    operationPending,
+			Steps:     []*OperationStep{},
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+	}
+
+	log.Printf("created %s operation %s for database %s", opType, op.resp.ID, database)
+
+	s.operations.Set(op.resp.ID, op, cache.DefaultExpiration)
+
+	return op
+}
+
+// keepOperation stores the operation in the operations cache again, so it expires after the given duration
+func (s *server) keepOperation(op *operation, d time.Duration) {
+	if op == nil {
+		return
+	}
+	s.operations.Set(op.id(), op, d)
+}
+
+// id returns the operation identifier
+func (op *operation) id() string {
+	if op == nil {
+		return ""
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.resp.ID
+}
+
+// setStatus sets the overall status of the operation
+func (op *operation) setStatus(status string) {
+	if op == nil {
+		return
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	op.resp.Status = status
+	op.resp.UpdatedAt = time.Now().UTC()
+}
+
+// fail marks the operation as failed, unless it has already been rolled back
+func (op *operation) fail(err error) {
+	if op == nil {
+		return
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if op.resp.Status != operationRolledBack {
+		op.resp.Status = operationFailed
+	}
+	if err != nil {
+		op.resp.Error = err.Error()
+	}
+	op.resp.UpdatedAt = time.Now().UTC()
+}
+
+// setError records the error of a request that only partially succeeded on the operation.  The operation isn't
+// failed right away, so the watcher still waits for what was created and then finishes the operation as failed.
+func (op *operation) setError(err error) {
+	if op == nil || err == nil {
+		return
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	op.resp.Error = err.Error()
+	op.resp.UpdatedAt = time.Now().UTC()
+}
+
+// hasError returns true if an error was recorded on the operation
+func (op *operation) hasError() bool {
+	if op == nil {
+		return false
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.resp.Error != ""
+}
+
+// startStep records a new running step on the operation
+func (op *operation) startStep(name string) *operationStep {
+	if op == nil {
+		return nil
+	}
+
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	now := time.Now().UTC()
+	op.resp.Steps = append(op.resp.Steps, &OperationStep{
+		Name:      name,
+		Status:    stepRunning,
+		StartedAt: now,
+	})
+	op.resp.UpdatedAt = now
+
+	return &operationStep{op: op, index: len(op.resp.Steps) - 1}
+}
+
+// complete marks the step as complete
+func (s *operationStep) complete() {
+	s.finish(stepComplete, nil)
+}
+
+// fail marks the step as failed with the given error
+func (s *operationStep) fail(err error) {
+	s.finish(stepFailed, err)
+}
+
+func (s *operationStep) finish(status string, err error) {
+	if s == nil {
+		return
+	}
+
+	s.op.mu.Lock()
+	defer s.op.mu.Unlock()
+
+	now := time.Now().UTC()
+	step := s.op.resp.Steps[s.index]
+	step.Status = status
+	step.FinishedAt = &now
+	if err != nil {
+		step.Error = err.Error()
+	}
+	s.op.resp.UpdatedAt = now
+}
+
+// response returns a copy of the current state of the operation
+func (op *operation) response() *OperationResponse {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+
+	resp := op.resp
+	resp.Steps = make([]*OperationStep, 0, len(op.resp.Steps))
+	for _, s := range op.resp.Steps {
+		step := *s
+		resp.Steps = append(resp.Steps, &step)
+	}
+
+	return &resp
+}
+
+// watchOperation waits in the background for each of the given conditions, in order, using the RDS waiters
+// and records the outcome on the operation.  Since watchers usually outlive the assumed role session of the
+// request, a new rds client is requested for every waiter round and the session cache hands out fresh credentials.
+func (s *server) watchOperation(op *operation, newClient func(ctx context.Context) (*rdsapi.Client, error), doneStatus string, waits ...operationWait) {
+	s.watchOperationTimeout(op, operationTimeout, newClient, doneStatus, waits...)
+}
+
+// watchOperationTimeout is watchOperation for operations that need a different timeout than operationTimeout.
+// When a wait fails or the operation times out, the remaining waits are skipped except for cleanup waits.
+func (s *server) watchOperationTimeout(op *operation, timeout time.Duration, newClient func(ctx context.Context) (*rdsapi.Client, error), doneStatus string, waits ...operationWait) {
+	// the operation is kept while it's watched, and for operationRetention after the watcher finishes
+	s.keepOperation(op, timeout+operationRetention)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		defer s.keepOperation(op, operationRetention)
+
+		failed := false
+		for _, w := range waits {
+			if failed && !w.cleanup {
+				continue
+			}
+
+			if !failed {
+				op.setStatus(w.status)
+			}
+
+			wctx := ctx
+			if w.cleanup {
+				var wcancel context.CancelFunc
+				wctx, wcancel = context.WithTimeout(context.Background(), operationTimeout)
+				defer wcancel()
+			}
+
+			if err := watchWait(wctx, op, newClient, w); err != nil && !failed {
+				op.fail(err)
+				failed = true
+			}
+		}
+
+		if failed {
+			return
+		}
+
+		if op.hasError() {
+			log.Printf("operation %s: finished with an error", op.id())
+			op.fail(nil)
+			return
+		}
+
+		log.Printf("operation %s: finished with status %s", op.id(), doneStatus)
+		op.setStatus(doneStatus)
+	}()
+}
+
+// watchWait waits for a single condition of an operation in rounds, and records it as a step of the operation
+func watchWait(ctx context.Context, op *operation, newClient func(ctx context.Context) (*rdsapi.Client, error), w operationWait) error {
+	step := op.startStep(w.name)
+
+	for {
+		client, err := newClient(ctx)
+		if err != nil {
+			log.Printf("operation %s: failed to get rds client: %s", op.id(), err)
+			step.fail(err)
+			return err
+		}
+
+		err = w.wait(ctx, client,
+			request.WithWaiterMaxAttempts(waiterRoundAttempts),
+			request.WithWaiterDelay(request.ConstantWaiterDelay(waiterDelay)),
+		)
+		if err == nil {
+			break
+		}
+
+		if rdsapi.IsWaiterTimeout(err) && ctx.Err() == nil {
+			log.Printf("operation %s: still waiting to %s", op.id(), w.name)
+			continue
+		}
+
+		log.Printf("operation %s: failed to %s: %s", op.id(), w.name, err)
+		step.fail(err)
+		return err
+	}
+
+	step.complete()
+	return nil
+}
+
+// readOnlyClient returns a function to get a read only rds client for the given account, used by background
+// watchers and for lookups before a scoped session can be requested
+func (s *server) readOnlyClient(accountId, region string) func(ctx context.Context) (*rdsapi.Client, error) {
+	return func(ctx context.Context) (*rdsapi.Client, error) {
+		policy, err := generatePolicy("rds:DescribeDBClusters", "rds:DescribeDBInstances")
+		if err != nil {
+			return nil, err
+		}
+		return s.scopedClient(accountId, region, policy)(ctx)
+	}
+}
+
+// scopedClient returns a function to get an rds client for the given account with the given session policy,
+// used by background watchers that run actions
+func (s *server) scopedClient(accountId, region, policy string) func(ctx context.Context) (*rdsapi.Client, error) {
+	return func(ctx context.Context) (*rdsapi.Client, error) {
+		role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+		session, err := s.assumeRole(
+			ctx,
+			region,
+			s.session.ExternalID,
+			role,
+			policy,
+			"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+		)
+		if err != nil {
+			msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+			return nil, apierror.New(apierror.ErrForbidden, msg, err)
+		}
+
+		return rdsapi.NewSession(session.Session, s.defaultConfig), nil
+	}
+}
+
+// OperationsGet returns the progress of an asynchronous database operation
+func (s *server) OperationsGet(c buffalo.Context) error {
+	accountId := s.mapAccountNumber(c.Param("account"))
+
+	item, found := s.operations.Get(c.Param("id"))
+	if !found {
+		return handleError(c, apierror.New(apierror.ErrNotFound, "operation not found", nil))
+	}
+
+	op, ok := item.(*operation)
+	if !ok {
+		return handleError(c, apierror.New(apierror.ErrInternalError, "unexpected operation type", nil))
+	}
+
+	resp := op.response()
+
+	// operations can only be retrieved from the account they were started in
+	if resp.Account != accountId {
+		return handleError(c, apierror.New(apierror.ErrNotFound, "operation not found", nil))
+	}
+
+	return c.Render(200, r.JSON(resp))
+}
+
+// waitInstanceAvailable returns an operationWait for the given database instance to become available
+func waitInstanceAvailable(id, status string) operationWait {
+	return operationWait{
+		name:   "wait for instance " + id + " to become available",
+		status: status,
+		wait: func(ctx context.Context, client *rdsapi.Client, opts ...request.WaiterOption) error {
+			return client.WaitUntilInstanceAvailable(ctx, id, opts...)
+		},
+	}
+}
+
+// waitClusterAvailable returns an operationWait for the given database cluster to become available
+func waitClusterAvailable(id, status string) operationWait {
+	return operationWait{
+		name:   "wait for cluster " + id + " to become available",
+		status: status,
+		wait: func(ctx context.Context, client *rdsapi.Client, opts ...request.WaiterOption) error {
+			return client.WaitUntilClusterAvailable(ctx, id, opts...)
+		},
+	}
+}
+
+// waitInstanceDeleted returns an operationWait for the given database instance to be deleted
+func waitInstanceDeleted(id string) operationWait {
+	return operationWait{
+		name:   "wait for instance " + id + " to be deleted",
+		status: operationDeleting,
+		wait: func(ctx context.Context, client *rdsapi.Client, opts ...request.WaiterOption) error {
+			return client.WaitUntilInstanceDeleted(ctx, id, opts...)
+		},
+	}
+}
+
+// waitClusterDeleted returns an operationWait for the given database cluster to be deleted
+func waitClusterDeleted(id string) operationWait {
+	return operationWait{
+		name:   "wait for cluster " + id + " to be deleted",
+		status: operationDeleting,
+		wait: func(ctx context.Context, client *rdsapi.Client, opts ...request.WaiterOption) error {
+			return client.WaitUntilClusterDeleted(ctx, id, opts...)
+		},
+	}
+}
+
+// waitDatabaseAvailable returns an operationWait for the given database cluster or instance to become available
+func waitDatabaseAvailable(id, status string) operationWait {
+	return operationWait{
+		name:   "wait for database " + id + " to become available",
+		status: status,
+		wait: func(ctx context.Context, client *rdsapi.Client, opts ...request.WaiterOption) error {
+			return client.WaitUntilDatabaseAvailable(ctx, id, opts...)
+		},
+	}
+}
+
+// waitDatabaseStopped returns an operationWait for the given database cluster or instance to be stopped
+func waitDatabaseStopped(id string) operationWait {
+	return operationWait{
+		name:   "wait for database " + id + " to be stopped",
+		status: operationStopping,
+		wait: func(ctx context.Context, client *rdsapi.Client, opts ...request.WaiterOption) error {
+			return client.WaitUntilDatabaseStopped(ctx, id, opts...)
+		},
+	}
+}
+
+// waitSnapshotAvailable returns an operationWait for the given database instance or cluster snapshot to become available
+func waitSnapshotAvailable(id string, cluster bool, status string) operationWait {
+	return operationWait{
+		name:   "wait for snapshot " + id + " to become available",
+		status: status,
+		wait: func(ctx context.Context, client *rdsapi.Client, opts ...request.WaiterOption) error {
+			if cluster {
+				return client.WaitUntilClusterSnapshotAvailable(ctx, id, opts...)
+			}
+			return client.WaitUntilSnapshotAvailable(ctx, id, opts...)
+		},
+	}
+}
+
+// upgradeDatabase returns an operationWait that upgrades the database in the given input.  It's an action
+// rather than a condition, so it needs a client that's allowed to modify the database.
+func upgradeDatabase(input *rdsapi.UpgradeInput) operationWait {
+	return operationWait{
+		name:   "upgrade database " + input.Identifier + " to engine version " + input.EngineVersion,
+		status: operationUpgrading,
+		wait: func(ctx context.Context, client *rdsapi.Client, _ ...request.WaiterOption) error {
+			return client.UpgradeDatabase(ctx, input)
+		},
+	}
+}
+
+// waitEngineVersion returns an operationWait for the database in the given upgrade to run the new engine version
+func waitEngineVersion(input *rdsapi.UpgradeInput) operationWait {
+	return operationWait{
+		name:   "wait for database " + input.Identifier + " to run engine version " + input.EngineVersion,
+		status: operationUpgrading,
+		wait: func(ctx context.Context, client *rdsapi.Client, opts ...request.WaiterOption) error {
+			return client.WaitUntilEngineVersion(ctx, input.Identifier, input.Cluster, input.EngineVersion, opts...)
+		},
+	}
+}
+
+// deleteCluster returns an operationWait that deletes the given database cluster, with a final snapshot if one is given.
+// It's an action rather than a condition, so it needs a client that's allowed to delete and tag the cluster.  The cluster
+// is only tagged for the final snapshot right before it's deleted, and the tag is removed again if the delete fails.
+func deleteCluster(id, finalSnapshot string) operationWait {
+	return operationWait{
+		name:   "delete cluster " + id,
+		status: operationDeleting,
+		wait: func(ctx context.Context, client *rdsapi.Client, _ ...request.WaiterOption) error {
+			if finalSnapshot == "" {
+				return client.DeleteCluster(ctx, id, "")
+			}
+
+			orch := &rdsOrchestrator{client: client}
+			if err := orch.markFinalSnapshot(ctx, id, true); err != nil {
+				return err
+			}
+			if err := client.DeleteCluster(ctx, id, finalSnapshot); err != nil {
+				orch.unmarkFinalSnapshot(ctx, id, true)
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+// unshareSnapshot returns an operationWait that stops sharing the given database instance or cluster snapshot with
+// the given account.  It's an action in the source account of a snapshot copy, so it gets its own client instead
+// of the one the watcher has for the target account.  The snapshot is only shared for the copy, so it's a cleanup
+// that runs even if the copy fails.
+func unshareSnapshot(newClient func(ctx context.Context) (*rdsapi.Client, error), id string, cluster bool, account string) operationWait {
+	return operationWait{
+		name:   "unshare snapshot " + id + " with account " + account,
+		status: operationCopying,
+		wait: func(ctx context.Context, _ *rdsapi.Client, _ ...request.WaiterOption) error {
+			client, err := newClient(ctx)
+			if err != nil {
+				return err
+			}
+			return client.UnshareSnapshot(ctx, id, cluster, account)
+		},
+		cleanup: true,
+	}
+}
+
+// setMaxAllocatedStorage returns an operationWait that enables storage autoscaling of the given database instance
+// up to the given size.  It's an action rather than a condition, so it needs a client that's allowed to modify the instance.
+func setMaxAllocatedStorage(id string, max int64) operationWait {
+	return operationWait{
+		name:   "enable storage autoscaling of " + id,
+		status: operationModifying,
+		wait: func(ctx context.Context, client *rdsapi.Client, _ ...request.WaiterOption) error {
+			return client.SetMaxAllocatedStorage(ctx, id, max)
+		},
+	}
+}
+
+// databaseWaits returns the conditions to wait for after a database create, restore or modify
+// based on the cluster and instance returned by the orchestrator
+func databaseWaits(resp *DatabaseResponse, clusterStatus, instanceStatus string) []operationWait {
+	waits := []operationWait{}
+	if resp.Cluster != nil {
+		waits = append(waits, waitClusterAvailable(aws.StringValue(resp.Cluster.DBClusterIdentifier), clusterStatus))
+	}
+	if resp.Instance != nil {
+		waits = append(waits, waitInstanceAvailable(aws.StringValue(resp.Instance.DBInstanceIdentifier), instanceStatus))
+	}
+	for _, m := range resp.Members {
+		waits = append(waits, waitInstanceAvailable(aws.StringValue(m.DBInstanceIdentifier), instanceStatus))
+	}
+	return waits
+}
@@ -0,0 +1,250 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/patrickmn/go-cache"
+)
+
+func TestOperationSteps(t *testing.T) {
+	s := &server{operations: cache.New(cache.NoExpiration, cache.NoExpiration)}
+
+	op := s.newOperation("create", "1234567890", "us-east-1", "mydb")
+	if _, found := s.operations.Get(op.id()); !found {
+		t.Fatal("expected operation to be stored in the operations cache")
+	}
+
+	step := op.startStep("create cluster mydb")
+	step.complete()
+
+	op.setStatus(operationCreatingInstance)
+	step = op.startStep("create instance mydb")
+	step.fail(errors.New("boom"))
+
+	op.startStep("delete cluster mydb").complete()
+	op.setStatus(operationRolledBack)
+	op.fail(errors.New("failed to create database instance"))
+
+	resp := op.response()
+	if resp.Status != operationRolledBack {
+		t.Errorf("expected status %s, got %s", operationRolledBack, resp.Status)
+	}
+	if resp.Error != "failed to create database instance" {
+		t.Errorf("unexpected operation error %s", resp.Error)
+	}
+	if len(resp.Steps) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(resp.Steps))
+	}
+
+	want := []string{stepComplete, stepFailed, stepComplete}
+	for i, s := range resp.Steps {
+		if s.Status != want[i] {
+			t.Errorf("expected step %d (%s) status %s, got %s", i, s.Name, want[i], s.Status)
+		}
+		if s.FinishedAt == nil {
+			t.Errorf("expected step %d (%s) to be finished", i, s.Name)
+		}
+	}
+	if resp.Steps[1].Error != "boom" {
+		t.Errorf("expected step error 'boom', got %s", resp.Steps[1].Error)
+	}
+
+	// the response is a copy and shouldn't change with the operation
+	op.startStep("another step")
+	if len(resp.Steps) != 3 {
+		t.Errorf("expected response copy to be unchanged, got %d steps", len(resp.Steps))
+	}
+}
+
+func TestOperationFail(t *testing.T) {
+	s := &server{operations: cache.New(cache.NoExpiration, cache.NoExpiration)}
+
+	op := s.newOperation("delete", "1234567890", "us-east-1", "mydb")
+	op.fail(errors.New("boom"))
+
+	resp := op.response()
+	if resp.Status != operationFailed {
+		t.Errorf("expected status %s, got %s", operationFailed, resp.Status)
+	}
+	if resp.Error != "boom" {
+		t.Errorf("expected error boom, got %s", resp.Error)
+	}
+}
+
+func TestOperationPartialFailure(t *testing.T) {
+	s := &server{operations: cache.New(cache.NoExpiration, cache.NoExpiration)}
+
+	op := s.newOperation("restore", "1234567890", "us-east-1", "mydb")
+	op.setError(errors.New("failed to create reader instance"))
+	if resp := op.response(); resp.Status != operationPending {
+		t.Errorf("expected status %s before the watcher finishes, got %s", operationPending, resp.Status)
+	}
+
+	waited := make(chan struct{})
+	newClient := func(context.Context) (*rdsapi.Client, error) { return &rdsapi.Client{}, nil }
+	s.watchOperation(op, newClient, operationAvailable, operationWait{
+		name:   "wait for instance mydb",
+		status: operationCreatingInstance,
+		wait: func(context.Context, *rdsapi.Client, ...request.WaiterOption) error {
+			close(waited)
+			return nil
+		},
+	})
+
+	<-waited
+	deadline := time.Now().Add(5 * time.Second)
+	for op.response().Status == operationCreatingInstance && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	resp := op.response()
+	if resp.Status != operationFailed {
+		t.Errorf("expected status %s, got %s", operationFailed, resp.Status)
+	}
+	if resp.Error != "failed to create reader instance" {
+		t.Errorf("unexpected operation error %s", resp.Error)
+	}
+	if len(resp.Steps) != 1 || resp.Steps[0].Status != stepComplete {
+		t.Errorf("expected the created instance to be watched, got %+v", resp.Steps)
+	}
+}
+
+func TestOperationCleanup(t *testing.T) {
+	s := &server{operations: cache.New(cache.NoExpiration, cache.NoExpiration)}
+	newClient := func(context.Context) (*rdsapi.Client, error) { return &rdsapi.Client{}, nil }
+
+	tests := []struct {
+		name string
+		wait func(ctx context.Context, _ *rdsapi.Client, _ ...request.WaiterOption) error
+	}{
+		{
+			name: "failed wait",
+			wait: func(context.Context, *rdsapi.Client, ...request.WaiterOption) error {
+				return errors.New("snapshot copy failed")
+			},
+		},
+		{
+			name: "timed out wait",
+			wait: func(ctx context.Context, _ *rdsapi.Client, _ ...request.WaiterOption) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := s.newOperation("copy snapshot", "1234567890", "us-east-1", "mydb-copy")
+
+			skipped := false
+			cleaned := make(chan error, 1)
+			s.watchOperationTimeout(op, 50*time.Millisecond, newClient, operationAvailable,
+				operationWait{name: "wait for snapshot mydb-copy", status: operationCopying, wait: tt.wait},
+				operationWait{
+					name:   "wait for something else",
+					status: operationCopying,
+					wait: func(context.Context, *rdsapi.Client, ...request.WaiterOption) error {
+						skipped = true
+						return nil
+					},
+				},
+				operationWait{
+					name:   "unshare snapshot mydb-snap",
+					status: operationCopying,
+					wait: func(ctx context.Context, _ *rdsapi.Client, _ ...request.WaiterOption) error {
+						cleaned <- ctx.Err()
+						return nil
+					},
+					cleanup: true,
+				},
+			)
+
+			select {
+			case err := <-cleaned:
+				if err != nil {
+					t.Errorf("expected the cleanup to get its own context, got %s", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("expected the cleanup to run")
+			}
+
+			deadline := time.Now().Add(5 * time.Second)
+			for len(op.response().Steps) < 2 || op.response().Steps[1].Status == stepRunning {
+				if time.Now().After(deadline) {
+					t.Fatal("timed out waiting for the cleanup step")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			resp := op.response()
+			if skipped {
+				t.Error("expected the waits after the failure to be skipped")
+			}
+			if resp.Status != operationFailed {
+				t.Errorf("expected status %s, got %s", operationFailed, resp.Status)
+			}
+			if len(resp.Steps) != 2 || resp.Steps[0].Status != stepFailed || resp.Steps[1].Status != stepComplete {
+				t.Errorf("expected a failed wait and a complete cleanup, got %+v", resp.Steps)
+			}
+		})
+	}
+}
+
+func TestOperationRetention(t *testing.T) {
+	// a watched operation has to outlive its watcher, so its outcome can be looked up when it times out
+	s := &server{operations: cache.New(operationRetention, time.Hour)}
+	op := s.newOperation("copy snapshot", "1234567890", "us-east-1", "mydb-copy")
+
+	release := make(chan struct{})
+	newClient := func(context.Context) (*rdsapi.Client, error) { return &rdsapi.Client{}, nil }
+	start := time.Now()
+	s.watchOperationTimeout(op, copyOperationTimeout, newClient, operationAvailable, operationWait{
+		name:   "wait for snapshot mydb-copy",
+		status: operationCopying,
+		wait: func(context.Context, *rdsapi.Client, ...request.WaiterOption) error {
+			<-release
+			return nil
+		},
+	})
+
+	_, expiration, found := s.operations.GetWithExpiration(op.id())
+	if !found || expiration.Before(start.Add(copyOperationTimeout+operationRetention)) {
+		t.Errorf("expected the operation to be kept until after its watcher times out, got %s", expiration)
+	}
+
+	close(release)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, expiration, _ = s.operations.GetWithExpiration(op.id())
+		if op.response().Status == operationAvailable && expiration.Before(start.Add(copyOperationTimeout+operationRetention)) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the operation to be kept for %s after it finished, got %s", operationRetention, expiration)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if expiration.Before(time.Now().Add(operationRetention - time.Minute)) {
+		t.Errorf("expected the finished operation to be kept for %s, got %s", operationRetention, expiration)
+	}
+}
+
+func TestNilOperation(t *testing.T) {
+	var op *operation
+
+	// tracking is optional for the orchestrator, so none of these should panic
+	op.setStatus(operationAvailable)
+	op.fail(errors.New("boom"))
+	op.setError(errors.New("boom"))
+	op.startStep("step").complete()
+	op.startStep("step").fail(errors.New("boom"))
+
+	if op.id() != "" {
+		t.Errorf("expected empty id for nil operation, got %s", op.id())
+	}
+}
@@ -1,15 +1,53 @@
 package actions
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/gobuffalo/buffalo"
 )
 
+// maxClusterMembers is the maximum number of instances in an Aurora cluster, a writer and up to 15 readers
+const maxClusterMembers = 16
+
+// defaultParameterGroupName returns the parameter group configured as the default for the parameter group family
+// of the given engine and version, or nil to use the AWS default parameter group.  The family lookup is recorded
+// as a step of the operation.
+func (o *rdsOrchestrator) defaultParameterGroupName(cluster bool, engine, engineVersion *string) (*string, error) {
+	stepName, defaults := "determine parameter group", o.client.DefaultDBParameterGroupName
+	if cluster {
+		stepName, defaults = "determine cluster parameter group", o.client.DefaultDBClusterParameterGroupName
+	}
+
+	step := o.operation.startStep(stepName)
+	pgFamily, err := o.client.DetermineParameterGroupFamily(engine, engineVersion)
+	if err != nil {
+		log.Println(err.Error())
+		step.fail(err)
+		return nil, err
+	}
+	step.complete()
+	log.Println("determined ParameterGroupFamily based on Engine:", pgFamily)
+
+	pg, ok := defaults[pgFamily]
+	if !ok {
+		log.Println("no matching default parameter group found in config for", pgFamily, "using AWS default PG")
+		return nil, nil
+	}
+	log.Println("using default parameter group:", pg)
+
+	return aws.String(pg), nil
+}
+
 // databaseRestore orchestrates the creation of a database from a snapshot in the DatabaseCreateInput
 func (o *rdsOrchestrator) databaseRestore(c buffalo.Context, req *DatabaseCreateRequest) (*DatabaseResponse, error) {
 	log.Printf("creating database from snapshot request %+v", req)
@@ -27,15 +65,24 @@ func (o *rdsOrchestrator) databaseRestore(c buffalo.Context, req *DatabaseCreate
 			return nil, errors.New("empty DBClusterIdentifier")
 		}
 
+		if aws.Int64Value(req.Cluster.InstanceCount) > maxClusterMembers {
+			return nil, apierror.New(apierror.ErrBadRequest, fmt.Sprintf("InstanceCount can be at most %d", maxClusterMembers), nil)
+		}
+
+		step := o.operation.startStep("describe cluster snapshot " + snapshotId)
 		snapshotsOutput, err := o.client.Service.DescribeDBClusterSnapshotsWithContext(c, &rds.DescribeDBClusterSnapshotsInput{
 			DBClusterSnapshotIdentifier: aws.String(snapshotId),
 		})
 		if err != nil {
+			step.fail(err)
 			return nil, err
 		}
 		if len(snapshotsOutput.DBClusterSnapshots) > 1 {
-			return nil, errors.New("unexpected number of snapshots")
+			err = errors.New("unexpected number of snapshots")
+			step.fail(err)
+			return nil, err
 		}
+		step.complete()
 
 		snapshot := snapshotsOutput.DBClusterSnapshots[0]
 
@@ -53,6 +100,10 @@ func (o *rdsOrchestrator) databaseRestore(c buffalo.Context, req *DatabaseCreate
 			}
 		}
 
+		if err := rdsapi.ValidateClusterStorageType(aws.StringValue(snapshot.Engine), aws.StringValue(req.Cluster.StorageType)); err != nil {
+			return nil, apierror.New(apierror.ErrBadRequest, err.Error(), nil)
+		}
+
 		log.Printf("restoring database cluster from snapshot %s", snapshotId)
 
 		req.Cluster.Tags = normalizeTags(req.Cluster.Tags)
@@ -64,19 +115,11 @@ func (o *rdsOrchestrator) databaseRestore(c buffalo.Context, req *DatabaseCreate
 
 		// set default cluster parameter group
 		if req.Cluster.DBClusterParameterGroupName == nil {
-			pgFamily, pgErr := o.client.DetermineParameterGroupFamily(snapshot.Engine, snapshot.EngineVersion)
+			pg, pgErr := o.defaultParameterGroupName(true, snapshot.Engine, snapshot.EngineVersion)
 			if pgErr != nil {
-				log.Println(pgErr.Error())
 				return nil, pgErr
 			}
-			log.Println("determined ParameterGroupFamily based on Engine:", pgFamily)
-			cPg, ok := o.client.DefaultDBClusterParameterGroupName[pgFamily]
-			if !ok {
-				log.Println("no matching DefaultDBClusterParameterGroupName found in config, using AWS default PG")
-			} else {
-				log.Println("using DefaultDBClusterParameterGroupName:", cPg)
-				req.Cluster.DBClusterParameterGroupName = aws.String(cPg)
-			}
+			req.Cluster.DBClusterParameterGroupName = pg
 		}
 		engineVersion := req.Cluster.EngineVersion
 		if aws.StringValue(engineVersion) == "" {
@@ -84,18 +127,22 @@ func (o *rdsOrchestrator) databaseRestore(c buffalo.Context, req *DatabaseCreate
 		}
 
 		input := &rds.RestoreDBClusterFromSnapshotInput{
-			CopyTagsToSnapshot:          aws.Bool(true),
-			DBClusterIdentifier:         req.Cluster.DBClusterIdentifier,
-			DBClusterParameterGroupName: req.Cluster.DBClusterParameterGroupName,
-			DBSubnetGroupName:           req.Cluster.DBSubnetGroupName,
-			EnableCloudwatchLogsExports: req.Cluster.EnableCloudwatchLogsExports,
-			Engine:                      snapshot.Engine,
-			EngineMode:                  snapshot.EngineMode,
-			EngineVersion:               engineVersion,
-			Port:                        req.Cluster.Port,
-			SnapshotIdentifier:          aws.String(snapshotId),
-			Tags:                        toRDSTags(req.Cluster.Tags),
-			VpcSecurityGroupIds:         req.Cluster.VpcSecurityGroupIds,
+			CopyTagsToSnapshot:              aws.Bool(true),
+			DBClusterIdentifier:             req.Cluster.DBClusterIdentifier,
+			DBClusterParameterGroupName:     req.Cluster.DBClusterParameterGroupName,
+			DBSubnetGroupName:               req.Cluster.DBSubnetGroupName,
+			EnableCloudwatchLogsExports:     req.Cluster.EnableCloudwatchLogsExports,
+			DeletionProtection:              deletionProtection(req.Cluster.DeletionProtection, req.Cluster.Tags),
+			EnableIAMDatabaseAuthentication: req.Cluster.EnableIAMDatabaseAuthentication,
+			Engine:                          snapshot.Engine,
+			EngineMode:                      snapshot.EngineMode,
+			EngineVersion:                   engineVersion,
+			KmsKeyId:                        req.Cluster.KmsKeyId,
+			Port:                            req.Cluster.Port,
+			SnapshotIdentifier:              aws.String(snapshotId),
+			StorageType:                     req.Cluster.StorageType,
+			Tags:                            toRDSTags(req.Cluster.Tags),
+			VpcSecurityGroupIds:             req.Cluster.VpcSecurityGroupIds,
 		}
 
 		if req.Cluster.ScalingConfiguration != nil {
@@ -110,10 +157,14 @@ func (o *rdsOrchestrator) databaseRestore(c buffalo.Context, req *DatabaseCreate
 
 		log.Printf("restoring database cluster: %+v", *input)
 
+		o.operation.setStatus(operationCreatingCluster)
+		step = o.operation.startStep("restore cluster " + aws.StringValue(req.Cluster.DBClusterIdentifier) + " from snapshot")
 		output, err := o.client.Service.RestoreDBClusterFromSnapshotWithContext(c, input)
 		if err != nil {
+			step.fail(err)
 			return nil, ErrCode("failed to create database cluster from snapshot", err)
 		}
+		step.complete()
 
 		log.Printf("created RDS cluster from snapshot: %+v", output.DBCluster)
 
@@ -135,26 +186,43 @@ func (o *rdsOrchestrator) databaseRestore(c buffalo.Context, req *DatabaseCreate
 				Tags:                    toRDSTags(req.Cluster.Tags),
 			}
 
+			o.operation.setStatus(operationCreatingInstance)
+			step = o.operation.startStep("create instance " + aws.StringValue(req.Cluster.DBClusterIdentifier))
 			instanceOutput, err := o.client.Service.CreateDBInstanceWithContext(c, input)
 			if err != nil {
+				step.fail(err)
+
 				// delete the cluster to clean up
 				log.Println("error creating instance, deleting cluster", *req.Cluster.DBClusterIdentifier)
+				step = o.operation.startStep("delete cluster " + aws.StringValue(req.Cluster.DBClusterIdentifier))
 				clusterInput := &rds.DeleteDBClusterInput{
 					DBClusterIdentifier: req.Cluster.DBClusterIdentifier,
 					SkipFinalSnapshot:   aws.Bool(true),
 				}
 				if _, errc := o.client.Service.DeleteDBClusterWithContext(c, clusterInput); errc != nil {
 					log.Println("failed to delete cluster", errc.Error())
+					step.fail(errc)
 				} else {
 					log.Println("successfully requested deletion of cluster", *req.Cluster.DBClusterIdentifier)
+					step.complete()
+					o.operation.setStatus(operationRolledBack)
 				}
 
 				return nil, ErrCode("failed to create database instance", err)
 			}
+			step.complete()
 
 			log.Println("created RDS instance", instanceOutput)
 
 			resp.Instance = instanceOutput.DBInstance
+
+			// create the rest of the requested instances as readers
+			if n := aws.Int64Value(req.Cluster.InstanceCount); n > 1 {
+				if resp.Members, err = o.clusterReadersCreate(c, output.DBCluster, req.Instance.DBInstanceClass, req.Cluster.Tags, int(n-1), []*rds.DBInstance{resp.Instance}); err != nil {
+					// the cluster and the instances created so far are returned with the error, so they're still watched
+					return resp, err
+				}
+			}
 		}
 
 		return resp, nil
@@ -172,18 +240,28 @@ func (o *rdsOrchestrator) databaseRestore(c buffalo.Context, req *DatabaseCreate
 		}
 
 		// get information about the snapshot
+		step := o.operation.startStep("describe snapshot " + snapshotId)
 		snapshotsOutput, err := o.client.Service.DescribeDBSnapshotsWithContext(c, &rds.DescribeDBSnapshotsInput{
 			DBSnapshotIdentifier: aws.String(snapshotId),
 		})
 		if err != nil {
+			step.fail(err)
 			return nil, err
 		}
 		if len(snapshotsOutput.DBSnapshots) > 1 {
-			return nil, errors.New("unexpected number of snapshots")
+			err = errors.New("unexpected number of snapshots")
+			step.fail(err)
+			return nil, err
 		}
+		step.complete()
 
 		snapshot := snapshotsOutput.DBSnapshots[0]
 
+		// the restored storage can't be smaller than the snapshot, autoscaling is enabled once the instance is available
+		if err := rdsapi.ValidateStorageChange(aws.StringValue(snapshot.Engine), snapshotStorage(snapshot), req.Instance.storage()); err != nil {
+			return nil, apierror.New(apierror.ErrBadRequest, err.Error(), nil)
+		}
+
 		req.Instance.Tags = normalizeTags(req.Instance.Tags)
 
 		// set default subnet group
@@ -193,39 +271,44 @@ func (o *rdsOrchestrator) databaseRestore(c buffalo.Context, req *DatabaseCreate
 
 		// set default parameter group
 		if req.Instance.DBParameterGroupName == nil {
-			pgFamily, pgErr := o.client.DetermineParameterGroupFamily(snapshot.Engine, snapshot.EngineVersion)
+			pg, pgErr := o.defaultParameterGroupName(false, snapshot.Engine, snapshot.EngineVersion)
 			if pgErr != nil {
-				log.Println(pgErr.Error())
 				return nil, pgErr
 			}
-			log.Println("determined ParameterGroupFamily based on Engine:", pgFamily)
-			if pg, ok := o.client.DefaultDBParameterGroupName[pgFamily]; ok {
-				log.Println("using DefaultDBParameterGroupName:", pg)
-				req.Instance.DBParameterGroupName = aws.String(pg)
-			}
+			req.Instance.DBParameterGroupName = pg
 		}
 
 		input := &rds.RestoreDBInstanceFromDBSnapshotInput{
-			AutoMinorVersionUpgrade:     aws.Bool(true),
-			CopyTagsToSnapshot:          aws.Bool(true),
-			DBInstanceIdentifier:        req.Instance.DBInstanceIdentifier,
-			DBParameterGroupName:        req.Instance.DBParameterGroupName,
-			DBSnapshotIdentifier:        aws.String(snapshotId),
-			DBSubnetGroupName:           req.Instance.DBSubnetGroupName,
-			EnableCloudwatchLogsExports: req.Instance.EnableCloudwatchLogsExports,
-			MultiAZ:                     req.Instance.MultiAZ,
-			Port:                        req.Instance.Port,
-			PubliclyAccessible:          aws.Bool(false),
-			Tags:                        toRDSTags(req.Instance.Tags),
-			VpcSecurityGroupIds:         req.Instance.VpcSecurityGroupIds,
+			AllocatedStorage:                req.Instance.AllocatedStorage,
+			AutoMinorVersionUpgrade:         aws.Bool(true),
+			CopyTagsToSnapshot:              aws.Bool(true),
+			DBInstanceIdentifier:            req.Instance.DBInstanceIdentifier,
+			DBParameterGroupName:            req.Instance.DBParameterGroupName,
+			DBSnapshotIdentifier:            aws.String(snapshotId),
+			DBSubnetGroupName:               req.Instance.DBSubnetGroupName,
+			DeletionProtection:              deletionProtection(req.Instance.DeletionProtection, req.Instance.Tags),
+			EnableCloudwatchLogsExports:     req.Instance.EnableCloudwatchLogsExports,
+			EnableIAMDatabaseAuthentication: req.Instance.EnableIAMDatabaseAuthentication,
+			Iops:                            req.Instance.Iops,
+			MultiAZ:                         req.Instance.MultiAZ,
+			Port:                            req.Instance.Port,
+			PubliclyAccessible:              aws.Bool(false),
+			StorageThroughput:               req.Instance.StorageThroughput,
+			StorageType:                     req.Instance.StorageType,
+			Tags:                            toRDSTags(req.Instance.Tags),
+			VpcSecurityGroupIds:             req.Instance.VpcSecurityGroupIds,
 		}
 
 		log.Printf("restoring database instance: %+v", *input)
 
+		o.operation.setStatus(operationCreatingInstance)
+		step = o.operation.startStep("restore instance " + aws.StringValue(req.Instance.DBInstanceIdentifier) + " from snapshot")
 		output, err := o.client.Service.RestoreDBInstanceFromDBSnapshotWithContext(c, input)
 		if err != nil {
+			step.fail(err)
 			return nil, ErrCode("failed to create database instance from snapshot", err)
 		}
+		step.complete()
 
 		log.Printf("created RDS instance from snapshot: %+v", output.DBInstance)
 
@@ -236,6 +319,203 @@ func (o *rdsOrchestrator) databaseRestore(c buffalo.Context, req *DatabaseCreate
 	return nil, errors.New("invalid request")
 }
 
+// databasePointInTimeRestore orchestrates the restore of the given source database cluster or instance to a point in time.
+// A restored cluster gets its first instance created, like a cluster restored from a snapshot, and is deleted if that fails.
+func (o *rdsOrchestrator) databasePointInTimeRestore(c buffalo.Context, sourceCluster *rds.DBCluster, sourceInstance *rds.DBInstance, req *DatabaseRestoreRequest) (*DatabaseResponse, error) {
+	log.Printf("restoring database to point in time with request %+v", req)
+
+	if req.TargetIdentifier == nil {
+		return nil, errors.New("empty TargetIdentifier")
+	}
+
+	if aws.Int64Value(req.InstanceCount) > maxClusterMembers {
+		return nil, apierror.New(apierror.ErrBadRequest, fmt.Sprintf("InstanceCount can be at most %d", maxClusterMembers), nil)
+	}
+
+	resp := &DatabaseResponse{}
+
+	// restore a database cluster
+	if sourceCluster != nil {
+		if aws.StringValue(sourceCluster.EngineMode) != "serverless" && req.DBInstanceClass == nil {
+			return nil, errors.New("empty DBInstanceClass, required for engine mode " + aws.StringValue(sourceCluster.EngineMode))
+		}
+
+		tags := req.Tags
+		if tags == nil {
+			tags = fromRDSTags(sourceCluster.TagList)
+		}
+		tags = normalizeTags(tags)
+
+		// set default subnet group
+		if req.DBSubnetGroupName == nil {
+			req.DBSubnetGroupName = aws.String(o.client.DefaultSubnetGroup)
+		}
+
+		// set default cluster parameter group
+		if req.DBClusterParameterGroupName == nil {
+			pg, pgErr := o.defaultParameterGroupName(true, sourceCluster.Engine, sourceCluster.EngineVersion)
+			if pgErr != nil {
+				return nil, pgErr
+			}
+			req.DBClusterParameterGroupName = pg
+		}
+
+		input := &rds.RestoreDBClusterToPointInTimeInput{
+			CopyTagsToSnapshot:          aws.Bool(true),
+			DBClusterIdentifier:         req.TargetIdentifier,
+			DBClusterParameterGroupName: req.DBClusterParameterGroupName,
+			DBSubnetGroupName:           req.DBSubnetGroupName,
+			DeletionProtection:          deletionProtection(req.DeletionProtection, tags),
+			EnableCloudwatchLogsExports: req.EnableCloudwatchLogsExports,
+			KmsKeyId:                    req.KmsKeyId,
+			Port:                        req.Port,
+			RestoreToTime:               req.RestoreTime,
+			SourceDBClusterIdentifier:   sourceCluster.DBClusterIdentifier,
+			StorageType:                 req.StorageType,
+			Tags:                        toRDSTags(tags),
+			UseLatestRestorableTime:     req.UseLatestRestorableTime,
+			VpcSecurityGroupIds:         req.VpcSecurityGroupIds,
+		}
+
+		log.Printf("restoring database cluster to point in time: %+v", *input)
+
+		o.operation.setStatus(operationCreatingCluster)
+		step := o.operation.startStep("restore cluster " + aws.StringValue(req.TargetIdentifier) + " to point in time")
+		output, err := o.client.Service.RestoreDBClusterToPointInTimeWithContext(c, input)
+		if err != nil {
+			step.fail(err)
+			return nil, ErrCode("failed to restore database cluster to point in time", err)
+		}
+		step.complete()
+
+		log.Printf("restored RDS cluster to point in time: %+v", output.DBCluster)
+
+		resp.Cluster = output.DBCluster
+
+		// create instance in the cluster, if not serverless (e.g. provisioned)
+		if aws.StringValue(sourceCluster.EngineMode) != "serverless" {
+			log.Printf("cluster engine mode is %s, creating database instance ...", aws.StringValue(sourceCluster.EngineMode))
+
+			input := &rds.CreateDBInstanceInput{
+				AutoMinorVersionUpgrade: aws.Bool(true),
+				CopyTagsToSnapshot:      aws.Bool(true),
+				DBClusterIdentifier:     req.TargetIdentifier,
+				DBInstanceClass:         req.DBInstanceClass,
+				DBInstanceIdentifier:    req.TargetIdentifier,
+				Engine:                  sourceCluster.Engine,
+				PubliclyAccessible:      aws.Bool(false),
+				Tags:                    toRDSTags(tags),
+			}
+
+			o.operation.setStatus(operationCreatingInstance)
+			step = o.operation.startStep("create instance " + aws.StringValue(req.TargetIdentifier))
+			instanceOutput, err := o.client.Service.CreateDBInstanceWithContext(c, input)
+			if err != nil {
+				step.fail(err)
+
+				// delete the cluster to clean up
+				log.Println("error creating instance, deleting cluster", aws.StringValue(req.TargetIdentifier))
+				step = o.operation.startStep("delete cluster " + aws.StringValue(req.TargetIdentifier))
+				clusterInput := &rds.DeleteDBClusterInput{
+					DBClusterIdentifier: req.TargetIdentifier,
+					SkipFinalSnapshot:   aws.Bool(true),
+				}
+				if _, errc := o.client.Service.DeleteDBClusterWithContext(c, clusterInput); errc != nil {
+					log.Println("failed to delete cluster", errc.Error())
+					step.fail(errc)
+				} else {
+					log.Println("successfully requested deletion of cluster", aws.StringValue(req.TargetIdentifier))
+					step.complete()
+					o.operation.setStatus(operationRolledBack)
+				}
+
+				return nil, ErrCode("failed to create database instance", err)
+			}
+			step.complete()
+
+			log.Println("created RDS instance", instanceOutput)
+
+			resp.Instance = instanceOutput.DBInstance
+
+			// create the rest of the requested instances as readers
+			if n := aws.Int64Value(req.InstanceCount); n > 1 {
+				if resp.Members, err = o.clusterReadersCreate(c, output.DBCluster, req.DBInstanceClass, tags, int(n-1), []*rds.DBInstance{resp.Instance}); err != nil {
+					// the cluster and the instances created so far are returned with the error, so they're still watched
+					return resp, err
+				}
+			}
+		}
+
+		return resp, nil
+	}
+
+	// restore a database instance
+	if sourceInstance != nil {
+		tags := req.Tags
+		if tags == nil {
+			tags = fromRDSTags(sourceInstance.TagList)
+		}
+		tags = normalizeTags(tags)
+
+		// set default subnet group
+		if req.DBSubnetGroupName == nil {
+			req.DBSubnetGroupName = aws.String(o.client.DefaultSubnetGroup)
+		}
+
+		// set default parameter group
+		if req.DBParameterGroupName == nil {
+			pg, pgErr := o.defaultParameterGroupName(false, sourceInstance.Engine, sourceInstance.EngineVersion)
+			if pgErr != nil {
+				return nil, pgErr
+			}
+			req.DBParameterGroupName = pg
+		}
+
+		input := &rds.RestoreDBInstanceToPointInTimeInput{
+			AllocatedStorage:            req.AllocatedStorage,
+			AutoMinorVersionUpgrade:     aws.Bool(true),
+			CopyTagsToSnapshot:          aws.Bool(true),
+			DBInstanceClass:             req.DBInstanceClass,
+			DBParameterGroupName:        req.DBParameterGroupName,
+			DBSubnetGroupName:           req.DBSubnetGroupName,
+			DeletionProtection:          deletionProtection(req.DeletionProtection, tags),
+			EnableCloudwatchLogsExports: req.EnableCloudwatchLogsExports,
+			Iops:                        req.Iops,
+			MaxAllocatedStorage:         req.MaxAllocatedStorage,
+			MultiAZ:                     req.MultiAZ,
+			Port:                        req.Port,
+			PubliclyAccessible:          aws.Bool(false),
+			RestoreTime:                 req.RestoreTime,
+			SourceDBInstanceIdentifier:  sourceInstance.DBInstanceIdentifier,
+			StorageThroughput:           req.StorageThroughput,
+			StorageType:                 req.StorageType,
+			Tags:                        toRDSTags(tags),
+			TargetDBInstanceIdentifier:  req.TargetIdentifier,
+			UseLatestRestorableTime:     req.UseLatestRestorableTime,
+			VpcSecurityGroupIds:         req.VpcSecurityGroupIds,
+		}
+
+		log.Printf("restoring database instance to point in time: %+v", *input)
+
+		o.operation.setStatus(operationCreatingInstance)
+		step := o.operation.startStep("restore instance " + aws.StringValue(req.TargetIdentifier) + " to point in time")
+		output, err := o.client.Service.RestoreDBInstanceToPointInTimeWithContext(c, input)
+		if err != nil {
+			step.fail(err)
+			return nil, ErrCode("failed to restore database instance to point in time", err)
+		}
+		step.complete()
+
+		log.Printf("restored RDS instance to point in time: %+v", output.DBInstance)
+
+		resp.Instance = output.DBInstance
+
+		return resp, nil
+	}
+
+	return nil, errors.New("invalid request")
+}
+
 // databaseCreate orchestrates the creation of a database from the DatabaseCreateInput
 // It will create a database instance as specified by the `Instance` hash parameters.
 // If a `Cluster` hash is also given, it will first create an RDS cluster and the instance next.
@@ -265,37 +545,35 @@ func (o *rdsOrchestrator) databaseCreate(c buffalo.Context, req *DatabaseCreateR
 
 		// set default cluster parameter group
 		if req.Cluster.DBClusterParameterGroupName == nil {
-			pgFamily, pgErr := o.client.DetermineParameterGroupFamily(req.Cluster.Engine, req.Cluster.EngineVersion)
+			pg, pgErr := o.defaultParameterGroupName(true, req.Cluster.Engine, req.Cluster.EngineVersion)
 			if pgErr != nil {
-				log.Println(pgErr.Error())
 				return nil, pgErr
 			}
-			log.Println("determined ParameterGroupFamily based on Engine:", pgFamily)
-			cPg, ok := o.client.DefaultDBClusterParameterGroupName[pgFamily]
-			if !ok {
-				log.Println("no matching DefaultDBClusterParameterGroupName found in config, using AWS default PG")
-			} else {
-				log.Println("using DefaultDBClusterParameterGroupName:", cPg)
-				req.Cluster.DBClusterParameterGroupName = aws.String(cPg)
-			}
+			req.Cluster.DBClusterParameterGroupName = pg
 		}
 
 		input := &rds.CreateDBClusterInput{
-			BackupRetentionPeriod:       req.Cluster.BackupRetentionPeriod,
-			CopyTagsToSnapshot:          aws.Bool(true),
-			DBClusterIdentifier:         req.Cluster.DBClusterIdentifier,
-			DBClusterParameterGroupName: req.Cluster.DBClusterParameterGroupName,
-			DBSubnetGroupName:           req.Cluster.DBSubnetGroupName,
-			EnableCloudwatchLogsExports: req.Cluster.EnableCloudwatchLogsExports,
-			Engine:                      req.Cluster.Engine,
-			EngineMode:                  req.Cluster.EngineMode,
-			EngineVersion:               req.Cluster.EngineVersion,
-			MasterUserPassword:          req.Cluster.MasterUserPassword,
-			MasterUsername:              req.Cluster.MasterUsername,
-			Port:                        req.Cluster.Port,
-			StorageEncrypted:            req.Cluster.StorageEncrypted,
-			Tags:                        toRDSTags(req.Cluster.Tags),
-			VpcSecurityGroupIds:         req.Cluster.VpcSecurityGroupIds,
+			BackupRetentionPeriod:           req.Cluster.BackupRetentionPeriod,
+			CopyTagsToSnapshot:              aws.Bool(true),
+			DBClusterIdentifier:             req.Cluster.DBClusterIdentifier,
+			DBClusterParameterGroupName:     req.Cluster.DBClusterParameterGroupName,
+			DBSubnetGroupName:               req.Cluster.DBSubnetGroupName,
+			DeletionProtection:              deletionProtection(req.Cluster.DeletionProtection, req.Cluster.Tags),
+			EnableCloudwatchLogsExports:     req.Cluster.EnableCloudwatchLogsExports,
+			EnableIAMDatabaseAuthentication: req.Cluster.EnableIAMDatabaseAuthentication,
+			Engine:                          req.Cluster.Engine,
+			EngineMode:                      req.Cluster.EngineMode,
+			EngineVersion:                   req.Cluster.EngineVersion,
+			KmsKeyId:                        req.Cluster.KmsKeyId,
+			ManageMasterUserPassword:        req.Cluster.ManageMasterUserPassword,
+			MasterUserPassword:              req.Cluster.MasterUserPassword,
+			MasterUserSecretKmsKeyId:        req.Cluster.MasterUserSecretKmsKeyId,
+			MasterUsername:                  req.Cluster.MasterUsername,
+			Port:                            req.Cluster.Port,
+			StorageEncrypted:                req.Cluster.StorageEncrypted,
+			StorageType:                     req.Cluster.StorageType,
+			Tags:                            toRDSTags(req.Cluster.Tags),
+			VpcSecurityGroupIds:             req.Cluster.VpcSecurityGroupIds,
 		}
 
 		// Handle Serverless V1
@@ -317,9 +595,13 @@ func (o *rdsOrchestrator) databaseCreate(c buffalo.Context, req *DatabaseCreateR
 			}
 		}
 
+		o.operation.setStatus(operationCreatingCluster)
+		step := o.operation.startStep("create cluster " + aws.StringValue(req.Cluster.DBClusterIdentifier))
 		if clusterOutput, err = o.client.Service.CreateDBClusterWithContext(c, input); err != nil {
+			step.fail(err)
 			return nil, ErrCode("failed to create database cluster", err)
 		}
+		step.complete()
 
 		log.Println("created RDS cluster", clusterOutput)
 		cluster = clusterOutput.DBCluster
@@ -341,39 +623,42 @@ func (o *rdsOrchestrator) databaseCreate(c buffalo.Context, req *DatabaseCreateR
 
 		// set default parameter group
 		if req.Instance.DBParameterGroupName == nil {
-			pgFamily, pgErr := o.client.DetermineParameterGroupFamily(req.Instance.Engine, req.Instance.EngineVersion)
+			pg, pgErr := o.defaultParameterGroupName(false, req.Instance.Engine, req.Instance.EngineVersion)
 			if pgErr != nil {
-				log.Println(pgErr.Error())
 				return nil, pgErr
 			}
-			log.Println("determined ParameterGroupFamily based on Engine:", pgFamily)
-			if pg, ok := o.client.DefaultDBParameterGroupName[pgFamily]; ok {
-				log.Println("using DefaultDBParameterGroupName:", pg)
-				req.Instance.DBParameterGroupName = aws.String(pg)
-			}
+			req.Instance.DBParameterGroupName = pg
 		}
 
 		input := &rds.CreateDBInstanceInput{
-			AllocatedStorage:            req.Instance.AllocatedStorage,
-			AutoMinorVersionUpgrade:     aws.Bool(true),
-			BackupRetentionPeriod:       req.Instance.BackupRetentionPeriod,
-			CopyTagsToSnapshot:          aws.Bool(true),
-			DBClusterIdentifier:         req.Instance.DBClusterIdentifier,
-			DBInstanceClass:             req.Instance.DBInstanceClass,
-			DBInstanceIdentifier:        req.Instance.DBInstanceIdentifier,
-			DBParameterGroupName:        req.Instance.DBParameterGroupName,
-			DBSubnetGroupName:           req.Instance.DBSubnetGroupName,
-			EnableCloudwatchLogsExports: req.Instance.EnableCloudwatchLogsExports,
-			Engine:                      req.Instance.Engine,
-			EngineVersion:               req.Instance.EngineVersion,
-			MasterUserPassword:          req.Instance.MasterUserPassword,
-			MasterUsername:              req.Instance.MasterUsername,
-			MultiAZ:                     req.Instance.MultiAZ,
-			Port:                        req.Instance.Port,
-			PubliclyAccessible:          aws.Bool(false),
-			StorageEncrypted:            req.Instance.StorageEncrypted,
-			Tags:                        toRDSTags(req.Instance.Tags),
-			VpcSecurityGroupIds:         req.Instance.VpcSecurityGroupIds,
+			AllocatedStorage:                req.Instance.AllocatedStorage,
+			AutoMinorVersionUpgrade:         aws.Bool(true),
+			BackupRetentionPeriod:           req.Instance.BackupRetentionPeriod,
+			CopyTagsToSnapshot:              aws.Bool(true),
+			DBClusterIdentifier:             req.Instance.DBClusterIdentifier,
+			DBInstanceClass:                 req.Instance.DBInstanceClass,
+			DBInstanceIdentifier:            req.Instance.DBInstanceIdentifier,
+			DBParameterGroupName:            req.Instance.DBParameterGroupName,
+			DBSubnetGroupName:               req.Instance.DBSubnetGroupName,
+			EnableCloudwatchLogsExports:     req.Instance.EnableCloudwatchLogsExports,
+			EnableIAMDatabaseAuthentication: req.Instance.EnableIAMDatabaseAuthentication,
+			Engine:                          req.Instance.Engine,
+			EngineVersion:                   req.Instance.EngineVersion,
+			Iops:                            req.Instance.Iops,
+			KmsKeyId:                        req.Instance.KmsKeyId,
+			ManageMasterUserPassword:        req.Instance.ManageMasterUserPassword,
+			MasterUserPassword:              req.Instance.MasterUserPassword,
+			MasterUserSecretKmsKeyId:        req.Instance.MasterUserSecretKmsKeyId,
+			MasterUsername:                  req.Instance.MasterUsername,
+			MaxAllocatedStorage:             req.Instance.MaxAllocatedStorage,
+			MultiAZ:                         req.Instance.MultiAZ,
+			Port:                            req.Instance.Port,
+			PubliclyAccessible:              aws.Bool(false),
+			StorageEncrypted:                req.Instance.StorageEncrypted,
+			StorageThroughput:               req.Instance.StorageThroughput,
+			StorageType:                     req.Instance.StorageType,
+			Tags:                            toRDSTags(req.Instance.Tags),
+			VpcSecurityGroupIds:             req.Instance.VpcSecurityGroupIds,
 		}
 
 		// Check for LicenseModel - Required for Microsoft SQL Server Standard
@@ -381,22 +666,35 @@ func (o *rdsOrchestrator) databaseCreate(c buffalo.Context, req *DatabaseCreateR
 			input.LicenseModel = req.Instance.LicenseModel
 		}
 
+		// the deletion protection of cluster members is set on the cluster
+		if req.Instance.DBClusterIdentifier == nil {
+			input.DeletionProtection = deletionProtection(req.Instance.DeletionProtection, req.Instance.Tags)
+		}
+
+		o.operation.setStatus(operationCreatingInstance)
+		step := o.operation.startStep("create instance " + aws.StringValue(req.Instance.DBInstanceIdentifier))
 		if instanceOutput, err = o.client.Service.CreateDBInstanceWithContext(c, input); err != nil {
+			step.fail(err)
 			if req.Cluster != nil {
 				// if this instance was in a new cluster, delete the cluster
 				log.Println("deleting cluster", *req.Cluster.DBClusterIdentifier)
+				step = o.operation.startStep("delete cluster " + aws.StringValue(req.Cluster.DBClusterIdentifier))
 				clusterInput := &rds.DeleteDBClusterInput{
 					DBClusterIdentifier: req.Cluster.DBClusterIdentifier,
 					SkipFinalSnapshot:   aws.Bool(true),
 				}
 				if _, errc := o.client.Service.DeleteDBClusterWithContext(c, clusterInput); errc != nil {
 					log.Println("failed to delete cluster", errc.Error())
+					step.fail(errc)
 				} else {
 					log.Println("successfully requested deletion of cluster", *req.Cluster.DBClusterIdentifier)
+					step.complete()
+					o.operation.setStatus(operationRolledBack)
 				}
 			}
 			return nil, ErrCode("failed to create database instance", err)
 		}
+		step.complete()
 
 		log.Println("created RDS instance", instanceOutput)
 		instance = instanceOutput.DBInstance
@@ -408,6 +706,177 @@ func (o *rdsOrchestrator) databaseCreate(c buffalo.Context, req *DatabaseCreateR
 	}, nil
 }
 
+// databaseReplicaCreate orchestrates the creation of a read replica of the given source database instance
+func (o *rdsOrchestrator) databaseReplicaCreate(c buffalo.Context, source *rds.DBInstance, req *ReplicaCreateRequest) (*DatabaseResponse, error) {
+	log.Printf("creating read replica of %s with request %+v", aws.StringValue(source.DBInstanceIdentifier), req)
+
+	if req.DBInstanceIdentifier == nil {
+		return nil, errors.New("empty DBInstanceIdentifier")
+	}
+
+	tags := req.Tags
+	if tags == nil {
+		tags = fromRDSTags(source.TagList)
+	}
+	tags = normalizeTags(tags)
+
+	// set default instance class
+	if req.DBInstanceClass == nil {
+		req.DBInstanceClass = source.DBInstanceClass
+	}
+
+	// set default subnet group
+	if req.DBSubnetGroupName == nil {
+		req.DBSubnetGroupName = aws.String(o.client.DefaultSubnetGroup)
+	}
+
+	// set default parameter group
+	if req.DBParameterGroupName == nil {
+		pg, pgErr := o.defaultParameterGroupName(false, source.Engine, source.EngineVersion)
+		if pgErr != nil {
+			return nil, pgErr
+		}
+		req.DBParameterGroupName = pg
+	}
+
+	input := &rds.CreateDBInstanceReadReplicaInput{
+		AutoMinorVersionUpgrade:    aws.Bool(true),
+		AvailabilityZone:           req.AvailabilityZone,
+		CopyTagsToSnapshot:         aws.Bool(true),
+		DBInstanceClass:            req.DBInstanceClass,
+		DBInstanceIdentifier:       req.DBInstanceIdentifier,
+		DBParameterGroupName:       req.DBParameterGroupName,
+		DBSubnetGroupName:          req.DBSubnetGroupName,
+		MultiAZ:                    req.MultiAZ,
+		Port:                       req.Port,
+		PubliclyAccessible:         aws.Bool(false),
+		SourceDBInstanceIdentifier: source.DBInstanceIdentifier,
+		Tags:                       toRDSTags(tags),
+		VpcSecurityGroupIds:        req.VpcSecurityGroupIds,
+	}
+
+	o.operation.setStatus(operationCreatingInstance)
+	step := o.operation.startStep("create read replica " + aws.StringValue(req.DBInstanceIdentifier))
+	output, err := o.client.Service.CreateDBInstanceReadReplicaWithContext(c, input)
+	if err != nil {
+		step.fail(err)
+		return nil, ErrCode("failed to create read replica", err)
+	}
+	step.complete()
+
+	log.Println("created RDS read replica", output)
+
+	return &DatabaseResponse{Instance: output.DBInstance}, nil
+}
+
+// clusterReadersCreate creates count reader instances in the given cluster, spread across the availability zones
+// of the cluster subnet group.  New readers use the parameter group of the first existing member.
+func (o *rdsOrchestrator) clusterReadersCreate(c buffalo.Context, cluster *rds.DBCluster, instanceClass *string, tags []*Tag, count int, existing []*rds.DBInstance) ([]*rds.DBInstance, error) {
+	clusterId := aws.StringValue(cluster.DBClusterIdentifier)
+	log.Printf("creating %d reader instances in cluster %s", count, clusterId)
+
+	if count < 1 || len(existing)+count > maxClusterMembers {
+		msg := fmt.Sprintf("a cluster can have at most %d instances, it has %d", maxClusterMembers, len(existing))
+		return nil, apierror.New(apierror.ErrBadRequest, msg, nil)
+	}
+
+	if instanceClass == nil {
+		return nil, errors.New("empty DBInstanceClass")
+	}
+
+	existingIds := make([]string, 0, len(existing))
+	existingAzs := make([]string, 0, len(existing))
+	var parameterGroup *string
+	for _, m := range existing {
+		existingIds = append(existingIds, aws.StringValue(m.DBInstanceIdentifier))
+		existingAzs = append(existingAzs, aws.StringValue(m.AvailabilityZone))
+		if parameterGroup == nil && len(m.DBParameterGroups) > 0 {
+			parameterGroup = m.DBParameterGroups[0].DBParameterGroupName
+		}
+	}
+
+	step := o.operation.startStep("determine availability zones")
+	azs, err := o.client.SubnetGroupAvailabilityZones(c, aws.StringValue(cluster.DBSubnetGroup))
+	if err != nil {
+		step.fail(err)
+		return nil, ErrCode("failed to describe subnet group", err)
+	}
+	step.complete()
+
+	ids := rdsapi.MemberIdentifiers(clusterId, existingIds, count)
+	zones := rdsapi.SpreadAvailabilityZones(azs, existingAzs, count)
+
+	readers := []*rds.DBInstance{}
+	for i, id := range ids {
+		input := &rds.CreateDBInstanceInput{
+			AutoMinorVersionUpgrade: aws.Bool(true),
+			CopyTagsToSnapshot:      aws.Bool(true),
+			DBClusterIdentifier:     cluster.DBClusterIdentifier,
+			DBInstanceClass:         instanceClass,
+			DBInstanceIdentifier:    aws.String(id),
+			DBParameterGroupName:    parameterGroup,
+			Engine:                  cluster.Engine,
+			PubliclyAccessible:      aws.Bool(false),
+			Tags:                    toRDSTags(tags),
+		}
+		if zones[i] != "" {
+			input.AvailabilityZone = aws.String(zones[i])
+		}
+
+		step := o.operation.startStep("create reader instance " + id)
+		output, err := o.client.Service.CreateDBInstanceWithContext(c, input)
+		if err != nil {
+			step.fail(err)
+			return readers, ErrCode("failed to create reader instance", err)
+		}
+		step.complete()
+
+		log.Printf("created reader instance %s in %s", id, zones[i])
+		readers = append(readers, output.DBInstance)
+	}
+
+	return readers, nil
+}
+
+// clusterMemberDelete deletes the given member instance of a cluster, without deleting the cluster
+func (o *rdsOrchestrator) clusterMemberDelete(c buffalo.Context, id string) (*rds.DBInstance, error) {
+	log.Printf("deleting cluster member %s", id)
+
+	step := o.operation.startStep("delete instance " + id)
+	output, err := o.client.Service.DeleteDBInstanceWithContext(c, &rds.DeleteDBInstanceInput{
+		DBInstanceIdentifier: aws.String(id),
+		SkipFinalSnapshot:    aws.Bool(true),
+	})
+	if err != nil {
+		step.fail(err)
+		return nil, ErrCode("failed to delete cluster member", err)
+	}
+	step.complete()
+
+	return output.DBInstance, nil
+}
+
+// clusterMembersDelete orchestrates the deletion of all of the member instances of a database cluster.  The cluster
+// itself can only be deleted once its members are gone, which is left to the operation watcher.  If a member fails
+// to delete, the members deleted so far are returned with the error.
+func (o *rdsOrchestrator) clusterMembersDelete(c buffalo.Context, cluster *rds.DBCluster) (*DatabaseResponse, error) {
+	log.Printf("deleting all members of cluster %s", aws.StringValue(cluster.DBClusterIdentifier))
+
+	resp := &DatabaseResponse{
+		Cluster: cluster,
+		Members: []*rds.DBInstance{},
+	}
+	for _, m := range cluster.DBClusterMembers {
+		instance, err := o.clusterMemberDelete(c, aws.StringValue(m.DBInstanceIdentifier))
+		if err != nil {
+			return resp, err
+		}
+		resp.Members = append(resp.Members, instance)
+	}
+
+	return resp, nil
+}
+
 // databaseModify modifies database parameters and tags
 // Either Cluster or Instance input parameters can be specified for a request
 // Tags list can be given with any key/value tags to add/update
@@ -430,25 +899,20 @@ func (o *rdsOrchestrator) databaseModify(c buffalo.Context, id string, input *Da
 				DBClusterIdentifier: aws.String(id),
 			})
 			if err == nil && describeClusterOutput != nil {
-				pgFamily, pgErr := o.client.DetermineParameterGroupFamily(describeClusterOutput.DBClusters[0].Engine, input.Cluster.EngineVersion)
+				pg, pgErr := o.defaultParameterGroupName(true, describeClusterOutput.DBClusters[0].Engine, input.Cluster.EngineVersion)
 				if pgErr != nil {
-					log.Println(pgErr.Error())
 					return nil, pgErr
 				}
-				log.Println("determined ParameterGroupFamily based on Engine:", pgFamily)
-				cPg, ok := o.client.DefaultDBClusterParameterGroupName[pgFamily]
-				if !ok {
-					log.Println("no matching DefaultDBClusterParameterGroupName found in config, using AWS default PG")
-				} else {
-					log.Println("using DefaultDBClusterParameterGroupName:", cPg)
-					input.Cluster.DBClusterParameterGroupName = aws.String(cPg)
-				}
+				input.Cluster.DBClusterParameterGroupName = pg
 			}
 		}
 
+		step := o.operation.startStep("modify cluster " + id)
 		if clusterOutput, err = o.client.Service.ModifyDBClusterWithContext(c, input.Cluster); err != nil {
+			step.fail(err)
 			return nil, ErrCode("failed to modify database cluster", err)
 		}
+		step.complete()
 
 		log.Println("modified RDS cluster", clusterOutput)
 		cluster = clusterOutput.DBCluster
@@ -464,22 +928,20 @@ func (o *rdsOrchestrator) databaseModify(c buffalo.Context, id string, input *Da
 				DBInstanceIdentifier: aws.String(id),
 			})
 			if err == nil && describeInstanceOutput != nil {
-				pgFamily, pgErr := o.client.DetermineParameterGroupFamily(describeInstanceOutput.DBInstances[0].Engine, input.Instance.EngineVersion)
+				pg, pgErr := o.defaultParameterGroupName(false, describeInstanceOutput.DBInstances[0].Engine, input.Instance.EngineVersion)
 				if pgErr != nil {
-					log.Println(pgErr.Error())
 					return nil, pgErr
 				}
-				log.Println("determined ParameterGroupFamily based on Engine:", pgFamily)
-				if pg, ok := o.client.DefaultDBParameterGroupName[pgFamily]; ok {
-					log.Println("using DefaultDBParameterGroupName:", pg)
-					input.Instance.DBParameterGroupName = aws.String(pg)
-				}
+				input.Instance.DBParameterGroupName = pg
 			}
 		}
 
+		step := o.operation.startStep("modify instance " + id)
 		if instanceOutput, err = o.client.Service.ModifyDBInstanceWithContext(c, input.Instance); err != nil {
+			step.fail(err)
 			return nil, ErrCode("failed to modify database instance", err)
 		}
+		step.complete()
 
 		log.Println("modified RDS instance", instanceOutput)
 		instance = instanceOutput.DBInstance
@@ -488,10 +950,13 @@ func (o *rdsOrchestrator) databaseModify(c buffalo.Context, id string, input *Da
 	if input.Tags != nil {
 		log.Println("updating tags for "+id, input.Tags)
 
+		step := o.operation.startStep("update tags for " + id)
+
 		// determine ARN(s) for this RDS resource
 		arns, err := o.client.DetermineArn(id)
 		if err != nil {
 			log.Println(err)
+			step.fail(err)
 			return nil, err
 		}
 
@@ -503,10 +968,12 @@ func (o *rdsOrchestrator) databaseModify(c buffalo.Context, id string, input *Da
 				ResourceName: aws.String(arn),
 				Tags:         toRDSTags(normalizedTags),
 			}); err != nil {
+				step.fail(err)
 				return nil, ErrCode("failed to add tags to database", err)
 			}
 			log.Println("updated tags for RDS resource", arn)
 		}
+		step.complete()
 	}
 
 	return &DatabaseResponse{
@@ -521,16 +988,18 @@ func (o *rdsOrchestrator) databaseModify(c buffalo.Context, id string, input *Da
 //	if the instance belongs to a cluster and is the last remaining member.
 //
 // If snapshot is true, it will create a final snapshot of the instance/cluster.
-func (o *rdsOrchestrator) databaseDelete(c buffalo.Context, id string, snapshot bool) (*DatabaseResponse, error) {
-	log.Printf("deleting database %s (snapshot: %t)", id, snapshot)
+func (o *rdsOrchestrator) databaseDelete(c buffalo.Context, id string, snapshot, cascade bool) (*DatabaseResponse, error) {
+	log.Printf("deleting database %s (snapshot: %t, cascade: %t)", id, snapshot, cascade)
 
 	var clusterOutput *rds.DeleteDBClusterOutput
 	var instanceOutput *rds.DeleteDBInstanceOutput
 	var cluster *rds.DBCluster
 	var instance *rds.DBInstance
+	var replicas []*rds.DBInstance
 	var err error
 	var clusterName *string
 	var instanceNotFound bool
+	var finalSnapshot string
 
 	// first, let's determine if the given database instance belongs to a cluster
 	describeInstanceOutput, err := o.client.Service.DescribeDBInstancesWithContext(c, &rds.DescribeDBInstancesInput{
@@ -559,22 +1028,59 @@ func (o *rdsOrchestrator) databaseDelete(c buffalo.Context, id string, snapshot
 			clusterName = describeInstanceOutput.DBInstances[0].DBClusterIdentifier
 		}
 
+		// read replicas are only deleted along with their source if cascade is set
+		if replicaIds := describeInstanceOutput.DBInstances[0].ReadReplicaDBInstanceIdentifiers; len(replicaIds) > 0 {
+			if !cascade {
+				msg := fmt.Sprintf("database %s has read replicas %s, delete them first or pass cascade=true", id, strings.Join(aws.StringValueSlice(replicaIds), ", "))
+				return nil, apierror.New(apierror.ErrConflict, msg, nil)
+			}
+
+			for _, r := range replicaIds {
+				step := o.operation.startStep("delete read replica " + aws.StringValue(r))
+				replicaOutput, err := o.client.Service.DeleteDBInstanceWithContext(c, &rds.DeleteDBInstanceInput{
+					DBInstanceIdentifier: r,
+					SkipFinalSnapshot:    aws.Bool(true),
+				})
+				if err != nil {
+					step.fail(err)
+					return nil, ErrCode("failed to delete read replica", err)
+				}
+				step.complete()
+
+				log.Println("successfully requested deletion of read replica", aws.StringValue(r))
+				replicas = append(replicas, replicaOutput.DBInstance)
+			}
+		}
+
 		instanceInput := &rds.DeleteDBInstanceInput{
 			DBInstanceIdentifier: aws.String(id),
 			SkipFinalSnapshot:    aws.Bool(true),
 		}
 
-		if snapshot && clusterName == nil {
+		// the final snapshot is of the cluster if the instance is a member
+		instanceSnapshot := snapshot && clusterName == nil
+		if instanceSnapshot {
+			if err := o.markFinalSnapshot(c, id, false); err != nil {
+				return nil, err
+			}
+
 			log.Printf("deleting database %s and creating final snapshot", id)
-			instanceInput.FinalDBSnapshotIdentifier = aws.String("final-" + id)
+			finalSnapshot = finalSnapshotIdentifier(id, time.Now())
+			instanceInput.FinalDBSnapshotIdentifier = aws.String(finalSnapshot)
 			instanceInput.SkipFinalSnapshot = aws.Bool(false)
 		} else {
 			log.Printf("deleting database %s without creating final snapshot", id)
 		}
 
+		step := o.operation.startStep("delete instance " + id)
 		if instanceOutput, err = o.client.Service.DeleteDBInstanceWithContext(c, instanceInput); err != nil {
+			step.fail(err)
+			if instanceSnapshot {
+				o.unmarkFinalSnapshot(c, id, false)
+			}
 			return nil, ErrCode("failed to delete database instance", err)
 		}
+		step.complete()
 
 		log.Println("successfully requested deletion of database instance", id, instanceOutput)
 		instance = instanceOutput.DBInstance
@@ -589,17 +1095,28 @@ func (o *rdsOrchestrator) databaseDelete(c buffalo.Context, id string, snapshot
 		}
 
 		if snapshot {
+			if err := o.markFinalSnapshot(c, *clusterName, true); err != nil {
+				return nil, err
+			}
+
 			log.Printf("trying to delete associated database cluster %s with final snapshot", *clusterName)
-			clusterInput.FinalDBSnapshotIdentifier = aws.String("final-" + *clusterName)
+			finalSnapshot = finalSnapshotIdentifier(*clusterName, time.Now())
+			clusterInput.FinalDBSnapshotIdentifier = aws.String(finalSnapshot)
 			clusterInput.SkipFinalSnapshot = aws.Bool(false)
 		} else {
 			log.Printf("trying to delete associated database cluster %s", *clusterName)
 		}
 
 		// the cluster deletion will fail if there are still member instances in the cluster
+		step := o.operation.startStep("delete cluster " + *clusterName)
 		if clusterOutput, err = o.client.Service.DeleteDBClusterWithContext(c, clusterInput); err != nil {
+			step.fail(err)
+			if snapshot {
+				o.unmarkFinalSnapshot(c, *clusterName, true)
+			}
 			return nil, ErrCode("failed to delete database cluster", err)
 		}
+		step.complete()
 
 		log.Println("successfully requested deletion of database cluster", *clusterName, clusterOutput)
 		cluster = clusterOutput.DBCluster
@@ -614,27 +1131,71 @@ func (o *rdsOrchestrator) databaseDelete(c buffalo.Context, id string, snapshot
 		}
 
 		if snapshot {
+			if err := o.markFinalSnapshot(c, *clusterName, true); err != nil {
+				return nil, err
+			}
+
 			log.Printf("trying to delete database cluster %s with final snapshot", *clusterName)
-			clusterInput.FinalDBSnapshotIdentifier = aws.String("final-" + *clusterName)
+			finalSnapshot = finalSnapshotIdentifier(*clusterName, time.Now())
+			clusterInput.FinalDBSnapshotIdentifier = aws.String(finalSnapshot)
 			clusterInput.SkipFinalSnapshot = aws.Bool(false)
 		} else {
 			log.Printf("trying to delete database cluster %s", *clusterName)
 		}
 
+		step := o.operation.startStep("delete cluster " + *clusterName)
 		if clusterOutput, err = o.client.Service.DeleteDBClusterWithContext(c, clusterInput); err != nil {
+			step.fail(err)
+			if snapshot {
+				o.unmarkFinalSnapshot(c, *clusterName, true)
+			}
 			return nil, ErrCode("failed to delete database cluster", err)
 		}
+		step.complete()
 
 		log.Println("successfully requested deletion of database cluster", *clusterName, clusterOutput)
 		cluster = clusterOutput.DBCluster
 	}
 
 	return &DatabaseResponse{
-		Cluster:  cluster,
-		Instance: instance,
+		Cluster:                 cluster,
+		Instance:                instance,
+		Replicas:                replicas,
+		FinalSnapshotIdentifier: finalSnapshot,
 	}, nil
 }
 
+// markFinalSnapshot adds the final snapshot tag to the database instance or cluster right before it's deleted with a
+// final snapshot.  The database tags are copied to its snapshots, so the final snapshot is marked as soon as it
+// exists, even if the operation isn't watched until the database is gone.  Every other snapshot of the database
+// would get the tag as well, so it must be removed with unmarkFinalSnapshot if the delete fails.
+func (o *rdsOrchestrator) markFinalSnapshot(ctx context.Context, id string, cluster bool) error {
+	step := o.operation.startStep("tag database " + id + " for final snapshot")
+	tags := []*rds.Tag{{Key: aws.String(finalSnapshotTag), Value: aws.String(id)}}
+	if err := o.client.TagDatabase(ctx, id, cluster, tags); err != nil {
+		step.fail(err)
+		if _, ok := err.(apierror.Error); ok {
+			return err
+		}
+		return ErrCode("failed to tag database for final snapshot", err)
+	}
+	step.complete()
+
+	return nil
+}
+
+// unmarkFinalSnapshot removes the final snapshot tag from a database instance or cluster that failed to be deleted.
+// The delete already failed at this point, so a failure to remove the tag is only logged and recorded on the operation.
+func (o *rdsOrchestrator) unmarkFinalSnapshot(ctx context.Context, id string, cluster bool) {
+	step := o.operation.startStep("remove final snapshot tag from database " + id)
+	if err := o.client.UntagDatabase(ctx, id, cluster, []string{finalSnapshotTag}); err != nil {
+		log.Printf("failed to remove final snapshot tag from database %s: %s", id, err)
+		step.fail(err)
+		return
+	}
+	step.complete()
+}
+
 func (o *rdsOrchestrator) clusterSnapshotCreate(c buffalo.Context, cluster, snapshot string) (*rds.DBClusterSnapshot, error) {
 	clusterSnapshotOutput, err := o.client.Service.CreateDBClusterSnapshotWithContext(c, &rds.CreateDBClusterSnapshotInput{
 		DBClusterIdentifier:         aws.String(cluster),
@@ -0,0 +1,260 @@
+package actions
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/gobuffalo/buffalo"
+	"github.com/patrickmn/go-cache"
+)
+
+// mockOrchestrationClient is a fake rds client for testing the orchestration of databases
+type mockOrchestrationClient struct {
+	rdsiface.RDSAPI
+	instances        map[string]*rds.DBInstance
+	failDelete       map[string]bool
+	deletedInstances []string
+	deletedClusters  []string
+	calls            []string
+}
+
+func (m *mockOrchestrationClient) AddTagsToResourceWithContext(_ aws.Context, input *rds.AddTagsToResourceInput, _ ...request.Option) (*rds.AddTagsToResourceOutput, error) {
+	m.calls = append(m.calls, "tag "+aws.StringValue(input.ResourceName))
+	return &rds.AddTagsToResourceOutput{}, nil
+}
+
+func (m *mockOrchestrationClient) RemoveTagsFromResourceWithContext(_ aws.Context, input *rds.RemoveTagsFromResourceInput, _ ...request.Option) (*rds.RemoveTagsFromResourceOutput, error) {
+	m.calls = append(m.calls, "untag "+aws.StringValue(input.ResourceName))
+	return &rds.RemoveTagsFromResourceOutput{}, nil
+}
+
+func (m *mockOrchestrationClient) DescribeDBInstancesWithContext(_ aws.Context, input *rds.DescribeDBInstancesInput, _ ...request.Option) (*rds.DescribeDBInstancesOutput, error) {
+	instance, ok := m.instances[aws.StringValue(input.DBInstanceIdentifier)]
+	if !ok {
+		return nil, awserr.New(rds.ErrCodeDBInstanceNotFoundFault, "not found", nil)
+	}
+	instance.DBInstanceArn = aws.String("arn:aws:rds:us-east-1:0123456789:db:" + aws.StringValue(instance.DBInstanceIdentifier))
+	return &rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{instance}}, nil
+}
+
+func (m *mockOrchestrationClient) DeleteDBInstanceWithContext(_ aws.Context, input *rds.DeleteDBInstanceInput, _ ...request.Option) (*rds.DeleteDBInstanceOutput, error) {
+	if m.failDelete[aws.StringValue(input.DBInstanceIdentifier)] {
+		return nil, awserr.New(rds.ErrCodeInvalidDBInstanceStateFault, "invalid state", nil)
+	}
+	m.deletedInstances = append(m.deletedInstances, aws.StringValue(input.DBInstanceIdentifier))
+	m.calls = append(m.calls, "delete instance "+aws.StringValue(input.DBInstanceIdentifier))
+	return &rds.DeleteDBInstanceOutput{DBInstance: &rds.DBInstance{DBInstanceIdentifier: input.DBInstanceIdentifier}}, nil
+}
+
+func (m *mockOrchestrationClient) DescribeDBClustersWithContext(_ aws.Context, input *rds.DescribeDBClustersInput, _ ...request.Option) (*rds.DescribeDBClustersOutput, error) {
+	id := aws.StringValue(input.DBClusterIdentifier)
+	return &rds.DescribeDBClustersOutput{
+		DBClusters: []*rds.DBCluster{{DBClusterIdentifier: aws.String(id), DBClusterArn: aws.String("arn:aws:rds:us-east-1:0123456789:cluster:" + id)}},
+	}, nil
+}
+
+func (m *mockOrchestrationClient) DeleteDBClusterWithContext(_ aws.Context, input *rds.DeleteDBClusterInput, _ ...request.Option) (*rds.DeleteDBClusterOutput, error) {
+	if m.failDelete[aws.StringValue(input.DBClusterIdentifier)] {
+		return nil, awserr.New(rds.ErrCodeInvalidDBClusterStateFault, "invalid state", nil)
+	}
+	m.calls = append(m.calls, "delete cluster "+aws.StringValue(input.DBClusterIdentifier))
+	m.deletedClusters = append(m.deletedClusters, aws.StringValue(input.DBClusterIdentifier))
+	return &rds.DeleteDBClusterOutput{DBCluster: &rds.DBCluster{DBClusterIdentifier: input.DBClusterIdentifier}}, nil
+}
+
+func (m *mockOrchestrationClient) DescribeDBEngineVersions(input *rds.DescribeDBEngineVersionsInput) (*rds.DescribeDBEngineVersionsOutput, error) {
+	return &rds.DescribeDBEngineVersionsOutput{
+		DBEngineVersions: []*rds.DBEngineVersion{{DBParameterGroupFamily: aws.String(aws.StringValue(input.Engine) + "14")}},
+	}, nil
+}
+
+func TestDefaultParameterGroupName(t *testing.T) {
+	s := &server{operations: cache.New(cache.NoExpiration, cache.NoExpiration)}
+	op := s.newOperation("create", "0123456789", "us-east-1", "mydb")
+	orch := &rdsOrchestrator{
+		client: &rdsapi.Client{
+			Service:                            &mockOrchestrationClient{},
+			DefaultDBParameterGroupName:        map[string]string{"postgres14": "spinup-postgres14"},
+			DefaultDBClusterParameterGroupName: map[string]string{"aurora-postgresql14": "spinup-aurora-postgresql14"},
+		},
+		operation: op,
+	}
+
+	tests := []struct {
+		name    string
+		cluster bool
+		engine  string
+		want    *string
+	}{
+		{name: "instance default", engine: "postgres", want: aws.String("spinup-postgres14")},
+		{name: "cluster default", cluster: true, engine: "aurora-postgresql", want: aws.String("spinup-aurora-postgresql14")},
+		{name: "no default", engine: "mysql"},
+		{name: "no cluster default", cluster: true, engine: "postgres"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := orch.defaultParameterGroupName(tt.cluster, aws.String(tt.engine), aws.String("14.10"))
+			if err != nil {
+				t.Fatalf("expected nil error, got %s", err)
+			}
+			if aws.StringValue(got) != aws.StringValue(tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("expected %v, got %v", aws.StringValue(tt.want), aws.StringValue(got))
+			}
+		})
+	}
+
+	steps := op.response().Steps
+	if len(steps) != len(tests) || steps[1].Name != "determine cluster parameter group" || steps[1].Status != stepComplete {
+		t.Errorf("expected a complete step for each lookup, got %+v", steps)
+	}
+}
+
+func TestDatabaseDeleteClusterWriterWithClusterName(t *testing.T) {
+	m := &mockOrchestrationClient{
+		instances: map[string]*rds.DBInstance{
+			"mydb": {DBInstanceIdentifier: aws.String("mydb"), DBClusterIdentifier: aws.String("mydb")},
+		},
+	}
+	orch := &rdsOrchestrator{client: &rdsapi.Client{Service: m}}
+	c := &buffalo.DefaultContext{Context: context.Background()}
+
+	resp, err := orch.databaseDelete(c, "mydb", false, false)
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	if !reflect.DeepEqual(m.deletedInstances, []string{"mydb"}) || !reflect.DeepEqual(m.deletedClusters, []string{"mydb"}) {
+		t.Errorf("expected writer and cluster mydb to be deleted, got instances %v and clusters %v", m.deletedInstances, m.deletedClusters)
+	}
+	if resp.Instance == nil || resp.Cluster == nil {
+		t.Errorf("expected deleted instance and cluster in response, got %+v", resp)
+	}
+}
+
+func TestClusterMembersDeletePartialFailure(t *testing.T) {
+	m := &mockOrchestrationClient{failDelete: map[string]bool{"mydb-2": true}}
+	orch := &rdsOrchestrator{client: &rdsapi.Client{Service: m}}
+	c := &buffalo.DefaultContext{Context: context.Background()}
+
+	cluster := &rds.DBCluster{
+		DBClusterIdentifier: aws.String("mydb"),
+		DBClusterMembers: []*rds.DBClusterMember{
+			{DBInstanceIdentifier: aws.String("mydb")},
+			{DBInstanceIdentifier: aws.String("mydb-1")},
+			{DBInstanceIdentifier: aws.String("mydb-2")},
+			{DBInstanceIdentifier: aws.String("mydb-3")},
+		},
+	}
+
+	resp, err := orch.clusterMembersDelete(c, cluster)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if resp == nil || len(resp.Members) != 2 {
+		t.Fatalf("expected the 2 members deleted so far, got %+v", resp)
+	}
+	if !reflect.DeepEqual(m.deletedInstances, []string{"mydb", "mydb-1"}) || len(m.deletedClusters) != 0 {
+		t.Errorf("expected only mydb and mydb-1 to be deleted, got instances %v and clusters %v", m.deletedInstances, m.deletedClusters)
+	}
+}
+
+func TestDatabaseDeleteMarksFinalSnapshot(t *testing.T) {
+	m := &mockOrchestrationClient{
+		instances: map[string]*rds.DBInstance{
+			"mydb": {DBInstanceIdentifier: aws.String("mydb")},
+		},
+	}
+	orch := &rdsOrchestrator{client: &rdsapi.Client{Service: m}}
+	c := &buffalo.DefaultContext{Context: context.Background()}
+
+	resp, err := orch.databaseDelete(c, "mydb", true, false)
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if resp.FinalSnapshotIdentifier == "" {
+		t.Error("expected a final snapshot identifier")
+	}
+
+	// the database is tagged before it's deleted, so its final snapshot gets the tag along with the other tags
+	expected := []string{"tag arn:aws:rds:us-east-1:0123456789:db:mydb", "delete instance mydb"}
+	if !reflect.DeepEqual(m.calls, expected) {
+		t.Errorf("expected calls %v, got %v", expected, m.calls)
+	}
+}
+
+func TestDatabaseDeleteFinalSnapshotFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		instance   *rds.DBInstance
+		failDelete bool
+		wantCode   string
+		want       []string
+	}{
+		{
+			// the database isn't tagged when it can't be deleted, since its later snapshots would get the tag
+			name:     "read replicas without cascade",
+			instance: &rds.DBInstance{DBInstanceIdentifier: aws.String("mydb"), ReadReplicaDBInstanceIdentifiers: aws.StringSlice([]string{"mydb-replica"})},
+			wantCode: apierror.ErrConflict,
+		},
+		{
+			name:       "delete fails",
+			instance:   &rds.DBInstance{DBInstanceIdentifier: aws.String("mydb")},
+			failDelete: true,
+			want:       []string{"tag arn:aws:rds:us-east-1:0123456789:db:mydb", "untag arn:aws:rds:us-east-1:0123456789:db:mydb"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockOrchestrationClient{
+				instances:  map[string]*rds.DBInstance{"mydb": tt.instance},
+				failDelete: map[string]bool{"mydb": tt.failDelete},
+			}
+			orch := &rdsOrchestrator{client: &rdsapi.Client{Service: m}}
+			c := &buffalo.DefaultContext{Context: context.Background()}
+
+			_, err := orch.databaseDelete(c, "mydb", true, false)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if tt.wantCode != "" {
+				if aerr, ok := err.(apierror.Error); !ok || aerr.Code != tt.wantCode {
+					t.Errorf("expected code %s, got %v", tt.wantCode, err)
+				}
+			}
+			if !reflect.DeepEqual(m.calls, tt.want) {
+				t.Errorf("expected calls %v, got %v", tt.want, m.calls)
+			}
+		})
+	}
+}
+
+func TestDeleteClusterFinalSnapshot(t *testing.T) {
+	for _, fail := range []bool{false, true} {
+		m := &mockOrchestrationClient{failDelete: map[string]bool{"mydb": fail}}
+		w := deleteCluster("mydb", "final-mydb-20240101")
+
+		err := w.wait(context.Background(), &rdsapi.Client{Service: m})
+		if (err != nil) != fail {
+			t.Errorf("expected error %t, got %v", fail, err)
+		}
+
+		// the cluster is tagged right before it's deleted, and untagged again if the delete fails
+		expected := []string{"tag arn:aws:rds:us-east-1:0123456789:cluster:mydb", "delete cluster mydb"}
+		if fail {
+			expected = []string{"tag arn:aws:rds:us-east-1:0123456789:cluster:mydb", "untag arn:aws:rds:us-east-1:0123456789:cluster:mydb"}
+		}
+		if !reflect.DeepEqual(m.calls, expected) {
+			t.Errorf("expected calls %v, got %v", expected, m.calls)
+		}
+	}
+}
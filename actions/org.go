@@ -0,0 +1,93 @@
+package actions
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/gobuffalo/buffalo"
+)
+
+// orgFromTags returns the org from the `spinup:org` tag in the given resource tags
+func orgFromTags(tags []*rds.Tag) string {
+	for _, t := range tags {
+		if aws.StringValue(t.Key) == "spinup:org" {
+			return aws.StringValue(t.Value)
+		}
+	}
+	return ""
+}
+
+// ownedByOrg returns true if the resource with the given tags belongs to the org of this API
+func (s *server) ownedByOrg(tags []*rds.Tag) bool {
+	return orgFromTags(tags) == s.org
+}
+
+// ensureDatabaseOrg returns a forbidden error if the database cluster or instance with the given name
+// doesn't belong to the org of this API, or a not found error if it doesn't exist
+func (s *server) ensureDatabaseOrg(c buffalo.Context, client *rdsapi.Client, id string) error {
+	tags, err := client.DatabaseTags(c, id)
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return err
+		}
+		return ErrCode("failed to get database tags", err)
+	}
+
+	for arn, t := range tags {
+		if !s.ownedByOrg(t) {
+			log.Printf("database %s belongs to org '%s', not %s", arn, orgFromTags(t), s.org)
+			msg := fmt.Sprintf("database %s doesn't belong to org %s", id, s.org)
+			return apierror.New(apierror.ErrForbidden, msg, nil)
+		}
+	}
+
+	return nil
+}
+
+// ensureSnapshotOrg returns a forbidden error if the cluster or instance snapshot with the given identifier
+// doesn't belong to the org of this API, or a not found error if it doesn't exist
+func (s *server) ensureSnapshotOrg(c buffalo.Context, client *rdsapi.Client, id string) error {
+	tags, err := client.SnapshotTags(c, id)
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return err
+		}
+		return ErrCode("failed to get snapshot tags", err)
+	}
+
+	for arn, t := range tags {
+		if !s.ownedByOrg(t) {
+			log.Printf("snapshot %s belongs to org '%s', not %s", arn, orgFromTags(t), s.org)
+			msg := fmt.Sprintf("snapshot %s doesn't belong to org %s", id, s.org)
+			return apierror.New(apierror.ErrForbidden, msg, nil)
+		}
+	}
+
+	return nil
+}
+
+// orgClusterSnapshots returns the cluster snapshots belonging to the org of this API
+func (s *server) orgClusterSnapshots(snapshots []*rds.DBClusterSnapshot) []*rds.DBClusterSnapshot {
+	owned := []*rds.DBClusterSnapshot{}
+	for _, snap := range snapshots {
+		if s.ownedByOrg(snap.TagList) {
+			owned = append(owned, snap)
+		}
+	}
+	return owned
+}
+
+// orgSnapshots returns the instance snapshots belonging to the org of this API
+func (s *server) orgSnapshots(snapshots []*rds.DBSnapshot) []*rds.DBSnapshot {
+	owned := []*rds.DBSnapshot{}
+	for _, snap := range snapshots {
+		if s.ownedByOrg(snap.TagList) {
+			owned = append(owned, snap)
+		}
+	}
+	return owned
+}
@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+func TestOwnedByOrg(t *testing.T) {
+	s := &server{org: "localdev"}
+
+	tests := []struct {
+		name string
+		tags []*rds.Tag
+		want bool
+	}{
+		{
+			name: "matching org",
+			tags: []*rds.Tag{{Key: aws.String("spinup:org"), Value: aws.String("localdev")}},
+			want: true,
+		},
+		{
+			name: "other org",
+			tags: []*rds.Tag{{Key: aws.String("spinup:org"), Value: aws.String("otherorg")}},
+			want: false,
+		},
+		{
+			// resources tagged by other systems don't belong to the org
+			name: "legacy org tag",
+			tags: []*rds.Tag{{Key: aws.String("yale:org"), Value: aws.String("localdev")}},
+			want: false,
+		},
+		{
+			name: "spinup org tag wins over legacy tag",
+			tags: []*rds.Tag{
+				{Key: aws.String("yale:org"), Value: aws.String("localdev")},
+				{Key: aws.String("spinup:org"), Value: aws.String("otherorg")},
+			},
+			want: false,
+		},
+		{
+			name: "no org tag",
+			tags: []*rds.Tag{{Key: aws.String("Name"), Value: aws.String("mydb")}},
+			want: false,
+		},
+		{
+			name: "no tags",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.ownedByOrg(tt.tags); got != tt.want {
+				t.Errorf("ownedByOrg() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package actions
+
+import (
+	"encoding/base64"
+	"sort"
+	"strconv"
+
+	"github.com/YaleSpinup/apierror"
+	"github.com/gobuffalo/buffalo"
+)
+
+// pageParams parses the `limit` and `cursor` query parameters.  A limit of 0 means no limit.
+// The cursor is an opaque value returned in the X-Next-Cursor header of the previous page.
+func pageParams(c buffalo.Context) (int, string, error) {
+	limit := 0
+	if l := c.Param("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 {
+			return 0, "", apierror.New(apierror.ErrBadRequest, "limit must be a positive integer", err)
+		}
+		limit = n
+	}
+
+	after := ""
+	if cursor := c.Param("cursor"); cursor != "" {
+		key, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return 0, "", apierror.New(apierror.ErrBadRequest, "invalid cursor", err)
+		}
+		after = string(key)
+	}
+
+	return limit, after, nil
+}
+
+// paginate returns the start and end index of the page in the given sorted list of keys.
+// The page starts with the first key sorting after the given key, so removing items
+// between requests doesn't cause the next page to skip or repeat items.  If there are
+// more items, the key to pass to the next page is returned as well.
+func paginate(keys []string, limit int, after string) (int, int, string) {
+	start := 0
+	if after != "" {
+		start = sort.SearchStrings(keys, after)
+		if start < len(keys) && keys[start] == after {
+			start++
+		}
+	}
+
+	end := len(keys)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+
+	next := ""
+	if end < len(keys) {
+		next = keys[end-1]
+	}
+
+	return start, end, next
+}
+
+// setPageHeaders sets the X-Items header with the number of items in the response, the X-Total-Items
+// header with the number of items across all pages and, if there are more pages, the X-Next-Cursor header
+func setPageHeaders(c buffalo.Context, items, total int, next string) {
+	c.Response().Header().Set("X-Items", strconv.Itoa(items))
+	c.Response().Header().Set("X-Total-Items", strconv.Itoa(total))
+	if next != "" {
+		c.Response().Header().Set("X-Next-Cursor", base64.RawURLEncoding.EncodeToString([]byte(next)))
+	}
+}
@@ -0,0 +1,31 @@
+package actions
+
+import "testing"
+
+func TestPaginate(t *testing.T) {
+	keys := []string{"cluster/a", "cluster/b", "instance/a", "instance/c", "instance/d"}
+
+	tests := []struct {
+		name      string
+		limit     int
+		after     string
+		wantStart int
+		wantEnd   int
+		wantNext  string
+	}{
+		{name: "no limit", wantStart: 0, wantEnd: 5},
+		{name: "first page", limit: 2, wantStart: 0, wantEnd: 2, wantNext: "cluster/b"},
+		{name: "second page", limit: 2, after: "cluster/b", wantStart: 2, wantEnd: 4, wantNext: "instance/c"},
+		{name: "last page", limit: 2, after: "instance/c", wantStart: 4, wantEnd: 5},
+		{name: "cursor item was removed", limit: 2, after: "instance/b", wantStart: 3, wantEnd: 5},
+		{name: "past the end", limit: 2, after: "instance/z", wantStart: 5, wantEnd: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, next := paginate(keys, tt.limit, tt.after)
+			if start != tt.wantStart || end != tt.wantEnd || next != tt.wantNext {
+				t.Errorf("paginate() = (%d, %d, %q), want (%d, %d, %q)", start, end, next, tt.wantStart, tt.wantEnd, tt.wantNext)
+			}
+		})
+	}
+}
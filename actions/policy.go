@@ -2,23 +2,36 @@ package actions
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/YaleSpinup/aws-go/services/iam"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
 	log "github.com/sirupsen/logrus"
 )
 
+// generatePolicy generates a policy allowing the given actions on all resources.  It's meant for read only
+// sessions, which are already limited by the AmazonRDSReadOnlyAccess managed policy, and since the policy
+// is the same for every resource the session can be shared between requests.
 func generatePolicy(actions ...string) (string, error) {
 	log.Debugf("generating %v policy document", actions)
 
+	return generateResourcePolicy(allowStatement([]string{"*"}, actions...))
+}
+
+// generateResourcePolicy generates a policy document from the given statements.  Actions and resources are
+// sorted, so equivalent statements always produce the same document (and the same session cache key).
+func generateResourcePolicy(statements ...iam.StatementEntry) (string, error) {
+	for _, s := range statements {
+		sort.Strings(s.Action)
+		sort.Strings(s.Resource)
+	}
+
 	policy := iam.PolicyDocument{
-		Version: "2012-10-17",
-		Statement: []iam.StatementEntry{
-			{
-				Effect:   "Allow",
-				Action:   actions,
-				Resource: []string{"*"},
-			},
-		},
+		Version:   "2012-10-17",
+		Statement: statements,
 	}
 
 	j, err := json.Marshal(policy)
@@ -28,3 +41,484 @@ func generatePolicy(actions ...string) (string, error) {
 
 	return string(j), nil
 }
+
+// allowStatement returns a statement allowing the given actions on the given resources
+func allowStatement(resources []string, actions ...string) iam.StatementEntry {
+	return iam.StatementEntry{
+		Effect:   "Allow",
+		Action:   dedupe(actions),
+		Resource: dedupe(resources),
+	}
+}
+
+// orgStatement returns a statement allowing the given actions on the given resources, only
+// if they are tagged with the org.  It should be used for all destructive actions.
+func (s *server) orgStatement(resources []string, actions ...string) iam.StatementEntry {
+	statement := allowStatement(resources, actions...)
+	statement.Condition = iam.Condition{
+		"StringEquals": iam.ConditionStatement{
+			"aws:ResourceTag/spinup:org": iam.Value{s.org},
+		},
+	}
+	return statement
+}
+
+// rdsArn returns the ARN for the RDS resource of the given type and name in the given account, e.g.
+// rdsArn("0123456789", "db", "mydb") returns "arn:aws:rds:*:0123456789:db:mydb".  If the name is
+// already an ARN (like for shared snapshots), it's returned as is.
+func rdsArn(account, resourceType, name string) string {
+	if strings.HasPrefix(name, "arn:") {
+		return name
+	}
+	return fmt.Sprintf("arn:aws:rds:*:%s:%s:%s", account, resourceType, name)
+}
+
+// parameterGroupArns returns the ARNs for the given parameter group, or if it's empty all of the parameter
+// groups the orchestrator could pick as a default: the ones in the config and the AWS default ones
+func parameterGroupArns(account, resourceType, name string, defaults map[string]string) []string {
+	if name != "" {
+		return []string{rdsArn(account, resourceType, name)}
+	}
+
+	arns := []string{rdsArn(account, resourceType, "default.*")}
+	for _, pg := range defaults {
+		arns = append(arns, rdsArn(account, resourceType, pg))
+	}
+	return arns
+}
+
+// kmsArn returns the ARN for the given KMS key id or alias in the given account.  If it's already an ARN, it's returned as is.
+func kmsArn(account, key string) string {
+	if strings.HasPrefix(key, "arn:") {
+		return key
+	}
+	if strings.HasPrefix(key, "alias/") {
+		return fmt.Sprintf("arn:aws:kms:*:%s:%s", account, key)
+	}
+	return fmt.Sprintf("arn:aws:kms:*:%s:key/%s", account, key)
+}
+
+// masterUserSecretStatements returns the statements needed for RDS to create the master user secret
+// in Secrets Manager on behalf of the caller, and to encrypt it with the given customer managed KMS key
+func masterUserSecretStatements(account string, kmsKeyId *string) []iam.StatementEntry {
+	statements := []iam.StatementEntry{
+		allowStatement(
+			[]string{fmt.Sprintf("arn:aws:secretsmanager:*:%s:secret:rds!*", account)},
+			"secretsmanager:CreateSecret", "secretsmanager:TagResource",
+		),
+	}
+
+	if kmsKeyId != nil {
+		statements = append(statements, kmsKeyStatement(account, aws.StringValue(kmsKeyId)))
+	}
+
+	return statements
+}
+
+// kmsKeyStatement returns a statement allowing RDS to use the given customer managed KMS key on behalf of the caller
+func kmsKeyStatement(account, kmsKeyId string) iam.StatementEntry {
+	return allowStatement(
+		[]string{kmsArn(account, kmsKeyId)},
+		"kms:CreateGrant", "kms:Decrypt", "kms:DescribeKey", "kms:GenerateDataKey",
+	)
+}
+
+// dedupe returns the given list with duplicate and empty values removed
+func dedupe(list []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, v := range list {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+// databaseCreatePolicy generates the policy for creating or restoring the database in the given request
+func (s *server) databaseCreatePolicy(account string, req *DatabaseCreateRequest) (string, error) {
+	resources := []string{rdsArn(account, "og", "default:*")}
+	clusterArns := []string{}
+
+	if req.Cluster != nil {
+		clusterArns = append(clusterArns, rdsArn(account, "cluster", aws.StringValue(req.Cluster.DBClusterIdentifier)))
+
+		subnetGroup := s.defaultConfig.DefaultSubnetGroup
+		if req.Cluster.DBSubnetGroupName != nil {
+			subnetGroup = aws.StringValue(req.Cluster.DBSubnetGroupName)
+		}
+		resources = append(resources, rdsArn(account, "subgrp", subnetGroup))
+		resources = append(resources, parameterGroupArns(account, "cluster-pg", aws.StringValue(req.Cluster.DBClusterParameterGroupName), s.defaultConfig.DefaultDBClusterParameterGroupName)...)
+
+		if req.Cluster.SnapshotIdentifier != nil {
+			resources = append(resources, rdsArn(account, "cluster-snapshot", aws.StringValue(req.Cluster.SnapshotIdentifier)))
+
+			// the instance in a restored cluster is named after the cluster, additional readers get generated names
+			resources = append(resources, rdsArn(account, "db", aws.StringValue(req.Cluster.DBClusterIdentifier)))
+			if aws.Int64Value(req.Cluster.InstanceCount) > 1 {
+				resources = append(resources, rdsArn(account, "db", aws.StringValue(req.Cluster.DBClusterIdentifier)+"-*"))
+			}
+		}
+	}
+
+	if req.Instance != nil {
+		resources = append(resources, rdsArn(account, "db", aws.StringValue(req.Instance.DBInstanceIdentifier)))
+
+		if req.Instance.DBClusterIdentifier != nil {
+			clusterArns = append(clusterArns, rdsArn(account, "cluster", aws.StringValue(req.Instance.DBClusterIdentifier)))
+		}
+
+		subnetGroup := s.defaultConfig.DefaultSubnetGroup
+		if req.Instance.DBSubnetGroupName != nil {
+			subnetGroup = aws.StringValue(req.Instance.DBSubnetGroupName)
+		}
+		resources = append(resources, rdsArn(account, "subgrp", subnetGroup))
+		resources = append(resources, parameterGroupArns(account, "pg", aws.StringValue(req.Instance.DBParameterGroupName), s.defaultConfig.DefaultDBParameterGroupName)...)
+
+		if req.Instance.SnapshotIdentifier != nil {
+			resources = append(resources, rdsArn(account, "snapshot", aws.StringValue(req.Instance.SnapshotIdentifier)))
+		}
+	}
+
+	statements := []iam.StatementEntry{
+		allowStatement(append(resources, clusterArns...),
+			"rds:AddTagsToResource",
+			"rds:CreateDBCluster",
+			"rds:CreateDBInstance",
+			"rds:RestoreDBClusterFromSnapshot",
+			"rds:RestoreDBInstanceFromDBSnapshot",
+		),
+	}
+
+	// a new cluster is deleted if its instance fails to create
+	if len(clusterArns) > 0 {
+		statements = append(statements, s.orgStatement(clusterArns, "rds:DeleteDBCluster"))
+	}
+
+	if req.Cluster != nil && aws.BoolValue(req.Cluster.ManageMasterUserPassword) {
+		statements = append(statements, masterUserSecretStatements(account, req.Cluster.MasterUserSecretKmsKeyId)...)
+	}
+	if req.Instance != nil && aws.BoolValue(req.Instance.ManageMasterUserPassword) {
+		statements = append(statements, masterUserSecretStatements(account, req.Instance.MasterUserSecretKmsKeyId)...)
+	}
+
+	if req.Cluster != nil && req.Cluster.KmsKeyId != nil {
+		statements = append(statements, kmsKeyStatement(account, aws.StringValue(req.Cluster.KmsKeyId)))
+	}
+	if req.Instance != nil && req.Instance.KmsKeyId != nil {
+		statements = append(statements, kmsKeyStatement(account, aws.StringValue(req.Instance.KmsKeyId)))
+	}
+
+	// storage autoscaling of an instance restored from a snapshot is enabled once it's available
+	if req.Instance != nil && req.Instance.SnapshotIdentifier != nil && req.Instance.MaxAllocatedStorage != nil {
+		statements = append(statements, s.orgStatement([]string{rdsArn(account, "db", aws.StringValue(req.Instance.DBInstanceIdentifier))}, "rds:ModifyDBInstance"))
+	}
+
+	return generateResourcePolicy(statements...)
+}
+
+// databaseRestorePolicy generates the policy for restoring the given source database cluster or instance to a point in time
+func (s *server) databaseRestorePolicy(account string, sourceCluster *rds.DBCluster, sourceInstance *rds.DBInstance, req *DatabaseRestoreRequest) (string, error) {
+	target := aws.StringValue(req.TargetIdentifier)
+
+	subnetGroup := s.defaultConfig.DefaultSubnetGroup
+	if req.DBSubnetGroupName != nil {
+		subnetGroup = aws.StringValue(req.DBSubnetGroupName)
+	}
+
+	resources := []string{
+		rdsArn(account, "og", "default:*"),
+		rdsArn(account, "subgrp", subnetGroup),
+		rdsArn(account, "db", target),
+	}
+
+	statements := []iam.StatementEntry{}
+	if sourceCluster != nil {
+		targetArn := rdsArn(account, "cluster", target)
+		resources = append(resources, targetArn, rdsArn(account, "cluster", aws.StringValue(sourceCluster.DBClusterIdentifier)))
+		resources = append(resources, parameterGroupArns(account, "cluster-pg", aws.StringValue(req.DBClusterParameterGroupName), s.defaultConfig.DefaultDBClusterParameterGroupName)...)
+		if aws.Int64Value(req.InstanceCount) > 1 {
+			resources = append(resources, rdsArn(account, "db", target+"-*"))
+		}
+
+		// the restored cluster is deleted if its instance fails to create
+		statements = append(statements, s.orgStatement([]string{targetArn}, "rds:DeleteDBCluster"))
+	} else if sourceInstance != nil {
+		resources = append(resources, rdsArn(account, "db", aws.StringValue(sourceInstance.DBInstanceIdentifier)))
+		resources = append(resources, parameterGroupArns(account, "pg", aws.StringValue(req.DBParameterGroupName), s.defaultConfig.DefaultDBParameterGroupName)...)
+	}
+
+	statements = append(statements, allowStatement(resources,
+		"rds:AddTagsToResource",
+		"rds:CreateDBInstance",
+		"rds:RestoreDBClusterToPointInTime",
+		"rds:RestoreDBInstanceToPointInTime",
+	))
+
+	if req.KmsKeyId != nil {
+		statements = append(statements, kmsKeyStatement(account, aws.StringValue(req.KmsKeyId)))
+	}
+
+	return generateResourcePolicy(statements...)
+}
+
+// replicaCreatePolicy generates the policy for creating a read replica of the given source database instance
+func (s *server) replicaCreatePolicy(account, source string, req *ReplicaCreateRequest) (string, error) {
+	subnetGroup := s.defaultConfig.DefaultSubnetGroup
+	if req.DBSubnetGroupName != nil {
+		subnetGroup = aws.StringValue(req.DBSubnetGroupName)
+	}
+
+	resources := []string{
+		rdsArn(account, "db", source),
+		rdsArn(account, "db", aws.StringValue(req.DBInstanceIdentifier)),
+		rdsArn(account, "og", "default:*"),
+		rdsArn(account, "subgrp", subnetGroup),
+	}
+	resources = append(resources, parameterGroupArns(account, "pg", aws.StringValue(req.DBParameterGroupName), s.defaultConfig.DefaultDBParameterGroupName)...)
+
+	return generateResourcePolicy(
+		allowStatement(resources, "rds:AddTagsToResource", "rds:CreateDBInstanceReadReplica"),
+	)
+}
+
+// clusterMembersPolicy generates the policy for adding reader instances to the given cluster
+func (s *server) clusterMembersPolicy(account, cluster, subnetGroup, parameterGroup string) (string, error) {
+	resources := []string{
+		rdsArn(account, "cluster", cluster),
+		rdsArn(account, "db", cluster+"-*"),
+		rdsArn(account, "og", "default:*"),
+		rdsArn(account, "subgrp", subnetGroup),
+	}
+	resources = append(resources, parameterGroupArns(account, "pg", parameterGroup, s.defaultConfig.DefaultDBParameterGroupName)...)
+
+	return generateResourcePolicy(
+		allowStatement(resources, "rds:AddTagsToResource", "rds:CreateDBInstance"),
+	)
+}
+
+// clusterMemberDeletePolicy generates the policy for deleting the given member instance of a cluster
+func (s *server) clusterMemberDeletePolicy(account, member string) (string, error) {
+	return generateResourcePolicy(
+		s.orgStatement([]string{rdsArn(account, "db", member)}, "rds:DeleteDBInstance"),
+	)
+}
+
+// databaseModifyPolicy generates the policy for modifying the database with the given name
+func (s *server) databaseModifyPolicy(account, id string, input *DatabaseModifyInput) (string, error) {
+	databaseArns := []string{rdsArn(account, "db", id), rdsArn(account, "cluster", id)}
+
+	// the parameter, option and subnet groups associated with the database are also authorized
+	resources := []string{rdsArn(account, "og", "default:*")}
+	if input.Cluster != nil {
+		resources = append(resources, parameterGroupArns(account, "cluster-pg", aws.StringValue(input.Cluster.DBClusterParameterGroupName), s.defaultConfig.DefaultDBClusterParameterGroupName)...)
+	}
+	if input.Instance != nil {
+		resources = append(resources, parameterGroupArns(account, "pg", aws.StringValue(input.Instance.DBParameterGroupName), s.defaultConfig.DefaultDBParameterGroupName)...)
+		if input.Instance.DBSubnetGroupName != nil {
+			resources = append(resources, rdsArn(account, "subgrp", aws.StringValue(input.Instance.DBSubnetGroupName)))
+		}
+		if input.Instance.OptionGroupName != nil {
+			resources = append(resources, rdsArn(account, "og", aws.StringValue(input.Instance.OptionGroupName)))
+		}
+	}
+
+	statements := []iam.StatementEntry{
+		s.orgStatement(databaseArns, "rds:AddTagsToResource", "rds:ModifyDBCluster", "rds:ModifyDBInstance"),
+		allowStatement(resources, "rds:ModifyDBCluster", "rds:ModifyDBInstance"),
+	}
+
+	// switching an existing database to a managed master password creates the secret
+	if input.Cluster != nil && aws.BoolValue(input.Cluster.ManageMasterUserPassword) {
+		statements = append(statements, masterUserSecretStatements(account, input.Cluster.MasterUserSecretKmsKeyId)...)
+	}
+	if input.Instance != nil && aws.BoolValue(input.Instance.ManageMasterUserPassword) {
+		statements = append(statements, masterUserSecretStatements(account, input.Instance.MasterUserSecretKmsKeyId)...)
+	}
+
+	return generateResourcePolicy(statements...)
+}
+
+// credentialsRotatePolicy generates the policy for rotating the master user credentials of the database with the
+// given name.  If the password is managed in Secrets Manager, only the rotation of the given secret is allowed.
+func (s *server) credentialsRotatePolicy(account, id, secretArn string) (string, error) {
+	if secretArn != "" {
+		return generateResourcePolicy(allowStatement([]string{secretArn}, "secretsmanager:RotateSecret"))
+	}
+
+	return generateResourcePolicy(
+		s.orgStatement([]string{rdsArn(account, "db", id), rdsArn(account, "cluster", id)}, "rds:ModifyDBCluster", "rds:ModifyDBInstance"),
+	)
+}
+
+// databaseUpgradePolicy generates the policy for upgrading the database with the given name, switching to the given
+// parameter groups and optionally taking a snapshot with the given identifier first
+func (s *server) databaseUpgradePolicy(account, id, clusterParameterGroup, parameterGroup, snapshot string) (string, error) {
+	databaseArns := []string{rdsArn(account, "db", id), rdsArn(account, "cluster", id)}
+
+	resources := []string{rdsArn(account, "og", "default:*")}
+	if clusterParameterGroup != "" {
+		resources = append(resources, rdsArn(account, "cluster-pg", clusterParameterGroup))
+	}
+	if parameterGroup != "" {
+		resources = append(resources, rdsArn(account, "pg", parameterGroup))
+	}
+
+	statements := []iam.StatementEntry{
+		s.orgStatement(databaseArns, "rds:ModifyDBCluster", "rds:ModifyDBInstance"),
+		allowStatement(resources, "rds:ModifyDBCluster", "rds:ModifyDBInstance"),
+	}
+
+	if snapshot != "" {
+		snapshotArns := []string{rdsArn(account, "snapshot", snapshot), rdsArn(account, "cluster-snapshot", snapshot)}
+		statements = append(statements,
+			allowStatement(append(snapshotArns, databaseArns...), "rds:CreateDBClusterSnapshot", "rds:CreateDBSnapshot"),
+			allowStatement(snapshotArns, "rds:AddTagsToResource"),
+		)
+	}
+
+	return generateResourcePolicy(statements...)
+}
+
+// databaseUnprotectPolicy generates the policy for removing the deletion protection of the database with the given name
+func (s *server) databaseUnprotectPolicy(account, id string) (string, error) {
+	return generateResourcePolicy(
+		s.orgStatement([]string{rdsArn(account, "db", id), rdsArn(account, "cluster", id)}, "rds:ModifyDBCluster", "rds:ModifyDBInstance"),
+	)
+}
+
+// maintenanceApplyPolicy generates the policy for applying pending maintenance actions to the database with the given name
+func (s *server) maintenanceApplyPolicy(account, id string) (string, error) {
+	return generateResourcePolicy(
+		s.orgStatement([]string{rdsArn(account, "db", id), rdsArn(account, "cluster", id)}, "rds:ApplyPendingMaintenanceAction"),
+	)
+}
+
+// logsPolicy generates the policy for listing and downloading the log files of the database instance with the given name
+func (s *server) logsPolicy(account, id string) (string, error) {
+	return generateResourcePolicy(
+		s.orgStatement([]string{rdsArn(account, "db", id)}, "rds:DescribeDBLogFiles", "rds:DownloadDBLogFilePortion"),
+	)
+}
+
+// authTokenPolicy generates the policy for connecting to the database with the given resource id (the
+// DbiResourceId of an instance or the DbClusterResourceId of a cluster) as the given database user
+func (s *server) authTokenPolicy(account, resourceId, dbUser string) (string, error) {
+	return generateResourcePolicy(
+		allowStatement([]string{fmt.Sprintf("arn:aws:rds-db:*:%s:dbuser:%s/%s", account, resourceId, dbUser)}, "rds-db:connect"),
+	)
+}
+
+// databaseStatePolicy generates the policy for starting or stopping the database with the given name
+func (s *server) databaseStatePolicy(account, id string) (string, error) {
+	return generateResourcePolicy(
+		s.orgStatement(
+			[]string{rdsArn(account, "db", id), rdsArn(account, "cluster", id)},
+			"rds:StartDBCluster", "rds:StartDBInstance", "rds:StopDBCluster", "rds:StopDBInstance",
+		),
+	)
+}
+
+// databaseRebootPolicy generates the policy for rebooting the database instances with the given names
+func (s *server) databaseRebootPolicy(account string, instances ...string) (string, error) {
+	arns := []string{}
+	for _, i := range instances {
+		arns = append(arns, rdsArn(account, "db", i))
+	}
+
+	return generateResourcePolicy(
+		s.orgStatement(arns, "rds:RebootDBInstance"),
+	)
+}
+
+// databaseFailoverPolicy generates the policy for failing over the database cluster with the given name
+func (s *server) databaseFailoverPolicy(account, id string) (string, error) {
+	return generateResourcePolicy(
+		s.orgStatement([]string{rdsArn(account, "cluster", id)}, "rds:FailoverDBCluster"),
+	)
+}
+
+// databaseDeletePolicy generates the policy for deleting the database instance and/or cluster with the given names,
+// and the given other instances deleted along with it: the read replicas of the instance or the cluster members
+func (s *server) databaseDeletePolicy(account, id, cluster string, snapshot bool, instances ...string) (string, error) {
+	databaseArns := []string{rdsArn(account, "db", id), rdsArn(account, "cluster", cluster)}
+
+	deleteArns := append([]string{}, databaseArns...)
+	for _, i := range instances {
+		deleteArns = append(deleteArns, rdsArn(account, "db", i))
+	}
+
+	statements := []iam.StatementEntry{
+		s.orgStatement(deleteArns, "rds:DeleteDBCluster", "rds:DeleteDBInstance"),
+	}
+
+	if snapshot {
+		snapshotArns := []string{rdsArn(account, "snapshot", "final-"+id+"-*"), rdsArn(account, "cluster-snapshot", "final-"+cluster+"-*")}
+		statements = append(statements,
+			allowStatement(append(snapshotArns, databaseArns...), "rds:CreateDBClusterSnapshot", "rds:CreateDBSnapshot"),
+			allowStatement(append(snapshotArns, databaseArns...), "rds:AddTagsToResource"),
+			allowStatement(databaseArns, "rds:RemoveTagsFromResource"),
+		)
+	}
+
+	return generateResourcePolicy(statements...)
+}
+
+// replicaPromotePolicy generates the policy for promoting the read replica with the given name
+func (s *server) replicaPromotePolicy(account, id string) (string, error) {
+	return generateResourcePolicy(
+		s.orgStatement([]string{rdsArn(account, "db", id)}, "rds:PromoteReadReplica"),
+	)
+}
+
+// snapshotCreatePolicy generates the policy for creating a snapshot with the given identifier of the given database
+func (s *server) snapshotCreatePolicy(account, db, snap string) (string, error) {
+	return generateResourcePolicy(
+		allowStatement(
+			[]string{rdsArn(account, "db", db), rdsArn(account, "cluster", db), rdsArn(account, "snapshot", snap), rdsArn(account, "cluster-snapshot", snap)},
+			"rds:AddTagsToResource", "rds:CreateDBClusterSnapshot", "rds:CreateDBSnapshot",
+		),
+	)
+}
+
+// snapshotPolicy generates a policy for the given destructive actions on the snapshot with the given identifier
+func (s *server) snapshotPolicy(account, snap string, actions ...string) (string, error) {
+	return generateResourcePolicy(
+		s.orgStatement([]string{rdsArn(account, "snapshot", snap), rdsArn(account, "cluster-snapshot", snap)}, actions...),
+	)
+}
+
+// snapshotCopyPolicy generates the policy for copying the source snapshot with the given ARN to the target snapshot
+// in the given account.  RDS reads an encrypted source with its KMS key, and encrypts the copy with the given key.
+func (s *server) snapshotCopyPolicy(account, sourceArn, target, sourceKmsKeyId, kmsKeyId string) (string, error) {
+	statements := []iam.StatementEntry{
+		allowStatement(
+			[]string{sourceArn, rdsArn(account, "snapshot", target), rdsArn(account, "cluster-snapshot", target)},
+			"rds:AddTagsToResource", "rds:CopyDBClusterSnapshot", "rds:CopyDBSnapshot",
+		),
+	}
+
+	if sourceKmsKeyId != "" {
+		statements = append(statements, kmsKeyStatement(account, sourceKmsKeyId))
+	}
+	if kmsKeyId != "" {
+		statements = append(statements, kmsKeyStatement(account, kmsKeyId))
+	}
+
+	return generateResourcePolicy(statements...)
+}
+
+// databaseValidatePolicy generates the policy for validating a database create request without creating anything
+func databaseValidatePolicy() (string, error) {
+	return generatePolicy(
+		"rds:DescribeDBEngineVersions",
+		"rds:DescribeOrderableDBInstanceOptions",
+		"rds:DescribeDBSubnetGroups",
+		"rds:DescribeDBParameterGroups",
+		"rds:DescribeDBClusterParameterGroups",
+		"rds:DescribeDBSnapshots",
+		"rds:DescribeDBClusterSnapshots",
+	)
+}
@@ -0,0 +1,260 @@
+package actions
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/YaleSpinup/aws-go/services/iam"
+	"github.com/YaleSpinup/rds-api/pkg/common"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestGenerateResourcePolicy(t *testing.T) {
+	p1, err := generateResourcePolicy(allowStatement([]string{"b", "a"}, "rds:StopDBInstance", "rds:DeleteDBInstance"))
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	p2, err := generateResourcePolicy(allowStatement([]string{"a", "b", "a"}, "rds:DeleteDBInstance", "rds:StopDBInstance"))
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	if p1 != p2 {
+		t.Errorf("expected equivalent policies to be the same, got %s and %s", p1, p2)
+	}
+
+	expected := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["rds:DeleteDBInstance","rds:StopDBInstance"],"Resource":["a","b"]}]}`
+	if p1 != expected {
+		t.Errorf("expected %s, got %s", expected, p1)
+	}
+}
+
+func TestOrgStatement(t *testing.T) {
+	s := &server{org: "localdev"}
+
+	statement := s.orgStatement([]string{"arn:aws:rds:*:0123456789:db:mydb"}, "rds:DeleteDBInstance")
+	expected := iam.Condition{
+		"StringEquals": iam.ConditionStatement{
+			"aws:ResourceTag/spinup:org": iam.Value{"localdev"},
+		},
+	}
+	if !reflect.DeepEqual(statement.Condition, expected) {
+		t.Errorf("expected condition %+v, got %+v", expected, statement.Condition)
+	}
+}
+
+func TestRdsArn(t *testing.T) {
+	tests := []struct {
+		resourceType string
+		name         string
+		want         string
+	}{
+		{"db", "mydb", "arn:aws:rds:*:0123456789:db:mydb"},
+		{"cluster-snapshot", "final-mydb", "arn:aws:rds:*:0123456789:cluster-snapshot:final-mydb"},
+		{"snapshot", "arn:aws:rds:us-east-1:9876543210:snapshot:shared", "arn:aws:rds:us-east-1:9876543210:snapshot:shared"},
+	}
+
+	for _, test := range tests {
+		if got := rdsArn("0123456789", test.resourceType, test.name); got != test.want {
+			t.Errorf("expected %s, got %s", test.want, got)
+		}
+	}
+}
+
+func TestKmsArn(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"1234abcd-12ab-34cd-56ef-1234567890ab", "arn:aws:kms:*:0123456789:key/1234abcd-12ab-34cd-56ef-1234567890ab"},
+		{"alias/mykey", "arn:aws:kms:*:0123456789:alias/mykey"},
+		{"arn:aws:kms:us-east-1:0123456789:key/abcd", "arn:aws:kms:us-east-1:0123456789:key/abcd"},
+	}
+
+	for _, test := range tests {
+		if got := kmsArn("0123456789", test.key); got != test.want {
+			t.Errorf("expected %s, got %s", test.want, got)
+		}
+	}
+}
+
+func TestParameterGroupArns(t *testing.T) {
+	got := parameterGroupArns("0123456789", "pg", "mypg", map[string]string{"postgres": "custom-postgres"})
+	if !reflect.DeepEqual(got, []string{"arn:aws:rds:*:0123456789:pg:mypg"}) {
+		t.Errorf("unexpected parameter group arns %v", got)
+	}
+
+	got = parameterGroupArns("0123456789", "pg", "", map[string]string{"postgres": "custom-postgres"})
+	expected := []string{"arn:aws:rds:*:0123456789:pg:default.*", "arn:aws:rds:*:0123456789:pg:custom-postgres"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestDatabaseCreatePolicy(t *testing.T) {
+	s := &server{
+		org: "localdev",
+		defaultConfig: common.CommonConfig{
+			DefaultSubnetGroup: "default-subnets",
+		},
+	}
+
+	policy, err := s.databaseCreatePolicy("0123456789", &DatabaseCreateRequest{
+		Cluster: &CreateDBClusterInput{
+			DBClusterIdentifier: aws.String("mycluster"),
+		},
+		Instance: &CreateDBInstanceInput{
+			DBInstanceIdentifier: aws.String("myinstance"),
+			DBClusterIdentifier:  aws.String("mycluster"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	doc := iam.PolicyDocument{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		t.Fatalf("failed to unmarshal policy: %s", err)
+	}
+
+	if len(doc.Statement) != 2 {
+		t.Fatalf("expected 2 statements, got %d", len(doc.Statement))
+	}
+
+	for _, r := range doc.Statement[0].Resource {
+		if r == "*" {
+			t.Errorf("expected create statement to be scoped, got wildcard resource")
+		}
+	}
+
+	rollback := doc.Statement[1]
+	if !reflect.DeepEqual(rollback.Action, iam.Value{"rds:DeleteDBCluster"}) {
+		t.Errorf("expected rollback statement to only allow DeleteDBCluster, got %v", rollback.Action)
+	}
+	if !reflect.DeepEqual(rollback.Resource, iam.Value{"arn:aws:rds:*:0123456789:cluster:mycluster"}) {
+		t.Errorf("unexpected rollback resources %v", rollback.Resource)
+	}
+	if rollback.Condition == nil {
+		t.Errorf("expected rollback statement to be conditioned on the org tag")
+	}
+}
+
+func TestDatabaseDeletePolicy(t *testing.T) {
+	s := &server{org: "localdev"}
+
+	policy, err := s.databaseDeletePolicy("0123456789", "mydb", "mydb", false, "replica1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	doc := iam.PolicyDocument{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		t.Fatalf("failed to unmarshal policy: %s", err)
+	}
+
+	if len(doc.Statement) != 1 {
+		t.Fatalf("expected 1 statement, got %d", len(doc.Statement))
+	}
+
+	expected := iam.Value{
+		"arn:aws:rds:*:0123456789:cluster:mydb",
+		"arn:aws:rds:*:0123456789:db:mydb",
+		"arn:aws:rds:*:0123456789:db:replica1",
+	}
+	if !reflect.DeepEqual(doc.Statement[0].Resource, expected) {
+		t.Errorf("expected resources %v, got %v", expected, doc.Statement[0].Resource)
+	}
+	if doc.Statement[0].Condition == nil {
+		t.Errorf("expected delete statement to be conditioned on the org tag")
+	}
+
+	policy, err = s.databaseDeletePolicy("0123456789", "mydb", "mydb", true)
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	doc = iam.PolicyDocument{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		t.Fatalf("failed to unmarshal policy: %s", err)
+	}
+
+	if len(doc.Statement) != 4 {
+		t.Fatalf("expected 4 statements, got %d", len(doc.Statement))
+	}
+
+	// final snapshots are timestamped, and marked by tagging the database before it's deleted
+	expected = iam.Value{
+		"arn:aws:rds:*:0123456789:cluster-snapshot:final-mydb-*",
+		"arn:aws:rds:*:0123456789:cluster:mydb",
+		"arn:aws:rds:*:0123456789:db:mydb",
+		"arn:aws:rds:*:0123456789:snapshot:final-mydb-*",
+	}
+	found := false
+	for _, st := range doc.Statement {
+		if reflect.DeepEqual(st.Action, iam.Value{"rds:AddTagsToResource"}) {
+			found = true
+			if !reflect.DeepEqual(st.Resource, expected) {
+				t.Errorf("expected resources %v, got %v", expected, st.Resource)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected a statement to tag the final snapshot, got %s", policy)
+	}
+
+	// the tag is removed from the database again if the delete fails
+	found = false
+	for _, st := range doc.Statement {
+		if reflect.DeepEqual(st.Action, iam.Value{"rds:RemoveTagsFromResource"}) {
+			found = true
+			expected = iam.Value{"arn:aws:rds:*:0123456789:cluster:mydb", "arn:aws:rds:*:0123456789:db:mydb"}
+			if !reflect.DeepEqual(st.Resource, expected) {
+				t.Errorf("expected resources %v, got %v", expected, st.Resource)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("expected a statement to untag the database, got %s", policy)
+	}
+}
+
+func TestClusterMembersPolicy(t *testing.T) {
+	s := &server{org: "localdev"}
+
+	policy, err := s.clusterMembersPolicy("0123456789", "mycluster", "subnets", "mypg")
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	doc := iam.PolicyDocument{}
+	if err := json.Unmarshal([]byte(policy), &doc); err != nil {
+		t.Fatalf("failed to unmarshal policy: %s", err)
+	}
+
+	expected := iam.Value{
+		"arn:aws:rds:*:0123456789:cluster:mycluster",
+		"arn:aws:rds:*:0123456789:db:mycluster-*",
+		"arn:aws:rds:*:0123456789:og:default:*",
+		"arn:aws:rds:*:0123456789:pg:mypg",
+		"arn:aws:rds:*:0123456789:subgrp:subnets",
+	}
+	if !reflect.DeepEqual(doc.Statement[0].Resource, expected) {
+		t.Errorf("expected resources %v, got %v", expected, doc.Statement[0].Resource)
+	}
+}
+
+func TestAuthTokenPolicy(t *testing.T) {
+	s := &server{org: "localdev"}
+
+	policy, err := s.authTokenPolicy("0123456789", "db-ABCDEFGHIJKL", "myuser")
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	expected := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["rds-db:connect"],"Resource":["arn:aws:rds-db:*:0123456789:dbuser:db-ABCDEFGHIJKL/myuser"]}]}`
+	if policy != expected {
+		t.Errorf("expected %s, got %s", expected, policy)
+	}
+}
@@ -0,0 +1,157 @@
+package actions
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/gobuffalo/buffalo"
+	"github.com/pkg/errors"
+)
+
+// DatabasesUnprotect removes the deletion protection of a database cluster or instance in a given account, so it
+// can be deleted.  A reason has to be given, which is logged along with the request so the removal can be audited.
+func (s *server) DatabasesUnprotect(c buffalo.Context) error {
+	req := DatabaseUnprotectRequest{}
+	if err := c.Bind(&req); err != nil {
+		log.Println(err)
+		return c.Error(400, err)
+	}
+
+	if strings.TrimSpace(req.Reason) == "" {
+		return c.Error(400, errors.New("Bad request: specify the Reason for removing the deletion protection in request"))
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.databaseUnprotectPolicy(accountId, c.Param("db"))
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	if err := s.ensureDatabaseOrg(c, rdsClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	cluster, instance, err := rdsClient.DescribeDatabase(c, c.Param("db"))
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to describe database", err))
+	}
+
+	id, protected, err := unprotectTarget(cluster, instance, c.Param("db"))
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	if !protected {
+		msg := fmt.Sprintf("database %s doesn't have deletion protection enabled", id)
+		return handleError(c, apierror.New(apierror.ErrConflict, msg, nil))
+	}
+
+	op := s.newOperation("unprotect", accountId, region, id)
+	c.Response().Header().Set("X-Operation-Id", op.id())
+	op.setStatus(operationModifying)
+
+	c.Logger().WithFields(map[string]interface{}{
+		"account":   accountId,
+		"database":  id,
+		"operation": op.id(),
+		"reason":    req.Reason,
+	}).Warn("removing deletion protection")
+
+	step := op.startStep("remove deletion protection from " + id)
+	if err := rdsClient.SetDeletionProtection(c, id, false); err != nil {
+		step.fail(err)
+		op.fail(err)
+		return handleError(c, ErrCode("failed to remove deletion protection", err))
+	}
+	step.complete()
+
+	s.watchOperation(op, s.readOnlyClient(accountId, region), operationAvailable, waitDatabaseAvailable(id, operationModifying))
+
+	return c.Render(200, r.JSON(op.response()))
+}
+
+// unprotectTarget returns the identifier of the database to remove the deletion protection from, and whether it's
+// protected.  Cluster members are protected by their cluster, which has to be named in the request since the session
+// policy only allows modifying the database with the requested name.
+func unprotectTarget(cluster *rds.DBCluster, instance *rds.DBInstance, name string) (string, bool, error) {
+	if cluster == nil {
+		return name, instance != nil && aws.BoolValue(instance.DeletionProtection), nil
+	}
+
+	id := aws.StringValue(cluster.DBClusterIdentifier)
+	if id != name {
+		msg := fmt.Sprintf("database %s is a member of cluster %s, remove the deletion protection of the cluster with PUT /%s/unprotect", name, id, id)
+		return "", false, apierror.New(apierror.ErrBadRequest, msg, nil)
+	}
+
+	return id, aws.BoolValue(cluster.DeletionProtection), nil
+}
+
+// ensureUnprotected returns a conflict error if the given database cluster or instance has deletion protection enabled
+func ensureUnprotected(cluster *rds.DBCluster, instance *rds.DBInstance) error {
+	protected := ""
+	if cluster != nil && aws.BoolValue(cluster.DeletionProtection) {
+		protected = aws.StringValue(cluster.DBClusterIdentifier)
+	} else if instance != nil && aws.BoolValue(instance.DeletionProtection) {
+		protected = aws.StringValue(instance.DBInstanceIdentifier)
+	}
+
+	if protected != "" {
+		msg := fmt.Sprintf("database %s has deletion protection enabled, remove it first with PUT /%s/unprotect", protected, protected)
+		return apierror.New(apierror.ErrConflict, msg, nil)
+	}
+
+	return nil
+}
+
+// deletionProtection returns the deletion protection for a new database: the requested one, or
+// enabled by default for databases tagged as production
+func deletionProtection(protect *bool, tags []*Tag) *bool {
+	if protect != nil {
+		return protect
+	}
+	if isProduction(tags) {
+		return aws.Bool(true)
+	}
+	return nil
+}
+
+// isProduction returns true if the given tags mark a production database, with a spinup:environment
+// tag of "prod" or "production"
+func isProduction(tags []*Tag) bool {
+	for _, t := range tags {
+		if aws.StringValue(t.Key) != "spinup:environment" {
+			continue
+		}
+		switch strings.ToLower(aws.StringValue(t.Value)) {
+		case "prod", "production":
+			return true
+		}
+	}
+	return false
+}
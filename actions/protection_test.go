@@ -0,0 +1,105 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/YaleSpinup/apierror"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+func TestDeletionProtection(t *testing.T) {
+	production := []*Tag{{Key: aws.String("spinup:environment"), Value: aws.String("Production")}}
+	development := []*Tag{{Key: aws.String("spinup:environment"), Value: aws.String("dev")}}
+
+	tests := []struct {
+		name    string
+		protect *bool
+		tags    []*Tag
+		want    *bool
+	}{
+		{name: "production default", tags: production, want: aws.Bool(true)},
+		{name: "production opt out", protect: aws.Bool(false), tags: production, want: aws.Bool(false)},
+		{name: "development default", tags: development},
+		{name: "development opt in", protect: aws.Bool(true), tags: development, want: aws.Bool(true)},
+		{name: "prod short value", tags: []*Tag{{Key: aws.String("spinup:environment"), Value: aws.String("prod")}}, want: aws.Bool(true)},
+		{name: "no tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := deletionProtection(tt.protect, tt.tags)
+			if (got == nil) != (tt.want == nil) || aws.BoolValue(got) != aws.BoolValue(tt.want) {
+				t.Errorf("expected %v, got %v", aws.BoolValue(tt.want), aws.BoolValue(got))
+			}
+		})
+	}
+}
+
+func TestUnprotectTarget(t *testing.T) {
+	cluster := &rds.DBCluster{DBClusterIdentifier: aws.String("myaurora"), DeletionProtection: aws.Bool(true)}
+
+	tests := []struct {
+		name          string
+		cluster       *rds.DBCluster
+		instance      *rds.DBInstance
+		db            string
+		wantId        string
+		wantProtected bool
+		wantErr       bool
+	}{
+		{
+			name:          "protected instance",
+			instance:      &rds.DBInstance{DBInstanceIdentifier: aws.String("mypostgres"), DeletionProtection: aws.Bool(true)},
+			db:            "mypostgres",
+			wantId:        "mypostgres",
+			wantProtected: true,
+		},
+		{
+			name:     "unprotected instance",
+			instance: &rds.DBInstance{DBInstanceIdentifier: aws.String("mypostgres")},
+			db:       "mypostgres",
+			wantId:   "mypostgres",
+		},
+		{
+			name:          "cluster",
+			cluster:       cluster,
+			db:            "myaurora",
+			wantId:        "myaurora",
+			wantProtected: true,
+		},
+		{
+			name:          "writer with the cluster name",
+			cluster:       cluster,
+			instance:      &rds.DBInstance{DBInstanceIdentifier: aws.String("myaurora"), DBClusterIdentifier: aws.String("myaurora")},
+			db:            "myaurora",
+			wantId:        "myaurora",
+			wantProtected: true,
+		},
+		{
+			name:     "cluster member",
+			cluster:  cluster,
+			instance: &rds.DBInstance{DBInstanceIdentifier: aws.String("myaurora-1"), DBClusterIdentifier: aws.String("myaurora")},
+			db:       "myaurora-1",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, protected, err := unprotectTarget(tt.cluster, tt.instance, tt.db)
+			if tt.wantErr {
+				if aerr, ok := err.(apierror.Error); !ok || aerr.Code != apierror.ErrBadRequest {
+					t.Errorf("expected bad request, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil error, got %s", err)
+			}
+			if id != tt.wantId || protected != tt.wantProtected {
+				t.Errorf("expected %s (protected %t), got %s (protected %t)", tt.wantId, tt.wantProtected, id, protected)
+			}
+		})
+	}
+}
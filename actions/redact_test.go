@@ -0,0 +1,141 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/YaleSpinup/rds-api/pkg/redact"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/gobuffalo/buffalo"
+)
+
+const testPassword = "n0t-a-real-passw0rd"
+
+// mockLoggingClient is a fake rds client that echoes the password it's given in its outputs
+type mockLoggingClient struct {
+	rdsiface.RDSAPI
+}
+
+func (m *mockLoggingClient) CreateDBInstanceWithContext(_ aws.Context, input *rds.CreateDBInstanceInput, _ ...request.Option) (*rds.CreateDBInstanceOutput, error) {
+	return &rds.CreateDBInstanceOutput{
+		DBInstance: &rds.DBInstance{
+			DBInstanceIdentifier: input.DBInstanceIdentifier,
+			MasterUsername:       input.MasterUsername,
+		},
+	}, nil
+}
+
+func (m *mockLoggingClient) ModifyDBInstanceWithContext(_ aws.Context, input *rds.ModifyDBInstanceInput, _ ...request.Option) (*rds.ModifyDBInstanceOutput, error) {
+	return &rds.ModifyDBInstanceOutput{
+		DBInstance: &rds.DBInstance{DBInstanceIdentifier: input.DBInstanceIdentifier},
+	}, nil
+}
+
+func (m *mockLoggingClient) DescribeDBSnapshotsWithContext(_ aws.Context, input *rds.DescribeDBSnapshotsInput, _ ...request.Option) (*rds.DescribeDBSnapshotsOutput, error) {
+	return &rds.DescribeDBSnapshotsOutput{
+		DBSnapshots: []*rds.DBSnapshot{
+			{DBSnapshotIdentifier: input.DBSnapshotIdentifier, Engine: aws.String("postgres")},
+		},
+	}, nil
+}
+
+func (m *mockLoggingClient) RestoreDBInstanceFromDBSnapshotWithContext(_ aws.Context, input *rds.RestoreDBInstanceFromDBSnapshotInput, _ ...request.Option) (*rds.RestoreDBInstanceFromDBSnapshotOutput, error) {
+	return &rds.RestoreDBInstanceFromDBSnapshotOutput{
+		DBInstance: &rds.DBInstance{DBInstanceIdentifier: input.DBInstanceIdentifier},
+	}, nil
+}
+
+func TestDatabaseCreateRequestString(t *testing.T) {
+	req := DatabaseCreateRequest{
+		Instance: &CreateDBInstanceInput{
+			DBInstanceIdentifier: aws.String("mydb"),
+			MasterUserPassword:   aws.String(testPassword),
+		},
+	}
+
+	if s := req.String(); strings.Contains(s, testPassword) || !strings.Contains(s, redact.Mask) {
+		t.Errorf("expected masked password, got %s", s)
+	}
+}
+
+func TestOrchestratorLogsAreRedacted(t *testing.T) {
+	buf := bytes.Buffer{}
+	log.SetOutput(redact.NewWriter(&buf))
+	defer log.SetOutput(os.Stderr)
+
+	c := &buffalo.DefaultContext{Context: context.Background()}
+	orch := &rdsOrchestrator{
+		client: &rdsapi.Client{Service: &mockLoggingClient{}},
+	}
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{
+			name: "create",
+			run: func() error {
+				_, err := orch.databaseCreate(c, &DatabaseCreateRequest{
+					Instance: &CreateDBInstanceInput{
+						DBInstanceIdentifier: aws.String("mydb"),
+						DBParameterGroupName: aws.String("mypg"),
+						Engine:               aws.String("postgres"),
+						MasterUserPassword:   aws.String(testPassword),
+						MasterUsername:       aws.String("admin"),
+					},
+				})
+				return err
+			},
+		},
+		{
+			name: "modify",
+			run: func() error {
+				_, err := orch.databaseModify(c, "mydb", &DatabaseModifyInput{
+					Instance: &rds.ModifyDBInstanceInput{
+						DBParameterGroupName: aws.String("mypg"),
+						MasterUserPassword:   aws.String(testPassword),
+					},
+				})
+				return err
+			},
+		},
+		{
+			name: "restore",
+			run: func() error {
+				_, err := orch.databaseRestore(c, &DatabaseCreateRequest{
+					Instance: &CreateDBInstanceInput{
+						DBInstanceIdentifier: aws.String("mydb"),
+						DBParameterGroupName: aws.String("mypg"),
+						MasterUserPassword:   aws.String(testPassword),
+						SnapshotIdentifier:   aws.String("mysnap"),
+					},
+				})
+				return err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		buf.Reset()
+
+		if err := test.run(); err != nil {
+			t.Fatalf("%s: expected nil error, got %s", test.name, err)
+		}
+
+		if buf.Len() == 0 {
+			t.Errorf("%s: expected log output, got none", test.name)
+		}
+
+		if strings.Contains(buf.String(), testPassword) {
+			t.Errorf("%s: expected password to be masked in the logs, got %s", test.name, buf.String())
+		}
+	}
+}
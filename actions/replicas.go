@@ -0,0 +1,228 @@
+package actions
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/gobuffalo/buffalo"
+	"github.com/pkg/errors"
+)
+
+// ReplicasPost creates a read replica of a database instance in a given account
+func (s *server) ReplicasPost(c buffalo.Context) error {
+	req := ReplicaCreateRequest{}
+	if err := c.Bind(&req); err != nil {
+		log.Println(err)
+		return c.Error(400, err)
+	}
+
+	if aws.StringValue(req.DBInstanceIdentifier) == "" {
+		return c.Error(400, errors.New("Bad request: specify DBInstanceIdentifier in request"))
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	// the source is checked with a read only session, before a session scoped to the replica is requested
+	readClient, err := s.readOnlyClient(accountId, region)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	if err := s.ensureDatabaseOrg(c, readClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	source, err := s.replicaSource(c, readClient, c.Param("db"))
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.replicaCreatePolicy(accountId, c.Param("db"), &req)
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	op := s.newOperation("create replica", accountId, region, aws.StringValue(req.DBInstanceIdentifier))
+	c.Response().Header().Set("X-Operation-Id", op.id())
+
+	orch := &rdsOrchestrator{
+		client:    rdsClient,
+		operation: op,
+	}
+
+	resp, err := orch.databaseReplicaCreate(c, source, &req)
+	if err != nil {
+		op.fail(err)
+		return handleError(c, err)
+	}
+	resp.OperationID = op.id()
+	resp.Region = region
+
+	s.watchOperation(op, s.readOnlyClient(accountId, region), operationAvailable, waitInstanceAvailable(aws.StringValue(req.DBInstanceIdentifier), operationCreatingInstance))
+
+	return c.Render(200, r.JSON(resp))
+}
+
+// ReplicasList lists the read replicas of a database instance in a given account
+func (s *server) ReplicasList(c buffalo.Context) error {
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := generatePolicy("rds:DescribeDBInstances")
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	if err := s.ensureDatabaseOrg(c, rdsClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	replicasOutput, err := rdsClient.ListReadReplicas(c, c.Param("db"))
+	if err != nil {
+		return handleError(c, ErrCode("failed to list read replicas", err))
+	}
+
+	replicas := []*rds.DBInstance{}
+	for _, r := range replicasOutput {
+		if s.ownedByOrg(r.TagList) {
+			replicas = append(replicas, r)
+		}
+	}
+
+	output := struct {
+		DBInstances []*rds.DBInstance
+	}{
+		DBInstances: replicas,
+	}
+
+	return c.Render(200, r.JSON(output))
+}
+
+// ReplicasPromote promotes a read replica in a given account to a standalone database instance
+func (s *server) ReplicasPromote(c buffalo.Context) error {
+	// the request body is optional
+	req := ReplicaPromoteRequest{}
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&req); err != nil {
+			log.Println(err)
+			return c.Error(400, err)
+		}
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.replicaPromotePolicy(accountId, c.Param("db"))
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	if err := s.ensureDatabaseOrg(c, rdsClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	_, replica, err := rdsClient.DescribeDatabase(c, c.Param("db"))
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to describe database", err))
+	}
+
+	if replica == nil || replica.ReadReplicaSourceDBInstanceIdentifier == nil {
+		msg := fmt.Sprintf("database %s is not a read replica", c.Param("db"))
+		return handleError(c, apierror.New(apierror.ErrBadRequest, msg, nil))
+	}
+
+	op := s.newOperation("promote", accountId, region, c.Param("db"))
+	c.Response().Header().Set("X-Operation-Id", op.id())
+	op.setStatus(operationPromoting)
+
+	step := op.startStep("promote read replica " + c.Param("db"))
+	instance, err := rdsClient.PromoteReadReplica(c, c.Param("db"), req.BackupRetentionPeriod)
+	if err != nil {
+		step.fail(err)
+		op.fail(err)
+		return handleError(c, ErrCode("failed to promote read replica", err))
+	}
+	step.complete()
+
+	resp := &DatabaseResponse{
+		Instance:    instance,
+		OperationID: op.id(),
+		Region:      region,
+	}
+
+	s.watchOperation(op, s.readOnlyClient(accountId, region), operationAvailable, waitInstanceAvailable(c.Param("db"), operationPromoting))
+
+	return c.Render(200, r.JSON(resp))
+}
+
+// replicaSource returns the database instance with the given name, if it can be the source of a read replica.
+// Aurora clusters scale reads with cluster members instead of read replicas.
+func (s *server) replicaSource(c buffalo.Context, client *rdsapi.Client, id string) (*rds.DBInstance, error) {
+	cluster, instance, err := client.DescribeDatabase(c, id)
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return nil, err
+		}
+		return nil, ErrCode("failed to describe database", err)
+	}
+
+	if cluster != nil || instance == nil {
+		msg := fmt.Sprintf("database %s is a cluster or cluster member, read replicas can only be created for instances", id)
+		return nil, apierror.New(apierror.ErrBadRequest, msg, nil)
+	}
+
+	return instance, nil
+}
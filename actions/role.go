@@ -1,7 +1,10 @@
 package actions
 
 import (
+	"context"
+	"crypto/sha256"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -9,7 +12,6 @@ import (
 	stsSvc "github.com/YaleSpinup/rds-api/pkg/sts"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/sts"
-	"github.com/gobuffalo/buffalo"
 	"github.com/google/uuid"
 	"github.com/patrickmn/go-cache"
 	log "github.com/sirupsen/logrus"
@@ -18,7 +20,9 @@ import (
 // assumeRole assumes the passed role arn.  if an externalId is set in the account to be accessed, it can be passed with the request. inline
 // policy can be passed to limit the access for the session.  policy arns can also be passed to limit access for the session.
 // Note: sessions live for 900s and will be cached for 600 seconds, giving a 300s buffer to avoid terminated sessions inside of orchestration
-func (s *server) assumeRole(ctx buffalo.Context, externalId, roleArn, inlinePolicy string, policyArns ...string) (*session.Session, error) {
+// Sessions are cached by region and a hash of the inline policy, so requests generating the same policy (e.g. for the same resource) in the
+// same region share a session.
+func (s *server) assumeRole(ctx context.Context, region, externalId, roleArn, inlinePolicy string, policyArns ...string) (*session.Session, error) {
 	start := time.Now()
 	defer func() {
 		totalTime := time.Since(start)
@@ -41,7 +45,7 @@ func (s *server) assumeRole(ctx buffalo.Context, externalId, roleArn, inlinePoli
 		},
 	}
 
-	cacheKey := fmt.Sprintf("spinup_%s_%s", s.org, roleArn)
+	cacheKey := fmt.Sprintf("spinup_%s_%s_%s", s.org, region, roleArn)
 
 	if externalId != "" {
 		input.SetExternalId(externalId)
@@ -50,7 +54,7 @@ func (s *server) assumeRole(ctx buffalo.Context, externalId, roleArn, inlinePoli
 
 	if inlinePolicy != "" {
 		input.SetPolicy(inlinePolicy)
-		cacheKey = cacheKey + "_" + inlinePolicy
+		cacheKey = cacheKey + "_" + fmt.Sprintf("%x", sha256.Sum256([]byte(inlinePolicy)))
 	}
 
 	if policyArns != nil {
@@ -62,7 +66,9 @@ func (s *server) assumeRole(ctx buffalo.Context, externalId, roleArn, inlinePoli
 		}
 		input.SetPolicyArns(arns)
 
-		cacheKey = cacheKey + "_" + strings.Join(policyArns, "_")
+		sorted := append([]string{}, policyArns...)
+		sort.Strings(sorted)
+		cacheKey = cacheKey + "_" + strings.Join(sorted, "_")
 	}
 
 	log.Debugf("checking for item with cache key: '%s'", cacheKey)
@@ -93,7 +99,8 @@ func (s *server) assumeRole(ctx buffalo.Context, externalId, roleArn, inlinePoli
 			aws.StringValue(out.Credentials.SecretAccessKey),
 			aws.StringValue(out.Credentials.SessionToken),
 		),
-		session.WithRegion("us-east-1"),
+		session.WithExpiration(aws.TimeValue(out.Credentials.Expiration)),
+		session.WithRegion(region),
 	)
 
 	log.Debugf("caching session with cache key: '%s'", cacheKey)
@@ -5,16 +5,20 @@ import (
 
 	"github.com/YaleSpinup/rds-api/pkg/common"
 	"github.com/YaleSpinup/rds-api/pkg/session"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/gobuffalo/buffalo"
 	"github.com/patrickmn/go-cache"
 )
 
 type server struct {
-	accountsMap   map[string]string
+	accountsMap   map[string]common.MappedAccount
 	defaultConfig common.CommonConfig
 	org           string
 	token         []byte
 	session       *session.Session
 	sessionCache  *cache.Cache
+	operations    *cache.Cache
+	catalog       *cache.Cache
 }
 
 func newServer(config common.Config) *server {
@@ -31,13 +35,37 @@ func newServer(config common.Config) *server {
 		token:         []byte(config.Token),
 		session:       &sess,
 		sessionCache:  cache.New(600*time.Second, 900*time.Second),
+		operations:    cache.New(operationRetention, time.Hour),
+		catalog:       cache.New(time.Hour, 2*time.Hour),
 	}
 }
 
 // if we have an entry for the account name, return the associated account number
 func (s *server) mapAccountNumber(name string) string {
 	if a, ok := s.accountsMap[name]; ok {
-		return a
+		return a.Id
 	}
 	return name
 }
+
+// accountRegion returns the region of the request, from the region query parameter or the account
+func (s *server) accountRegion(c buffalo.Context) string {
+	return s.mapAccountRegion(c.Param("account"), c.Param("region"))
+}
+
+// mapAccountRegion returns the given region if it's set, otherwise the region of the mapped account
+// and finally the region of the API account
+func (s *server) mapAccountRegion(name, region string) string {
+	if region != "" {
+		return region
+	}
+	if a, ok := s.accountsMap[name]; ok && a.Region != "" {
+		return a.Region
+	}
+	if s.session != nil && s.session.Session != nil {
+		if region := aws.StringValue(s.session.Session.Config.Region); region != "" {
+			return region
+		}
+	}
+	return "us-east-1"
+}
@@ -0,0 +1,58 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/YaleSpinup/rds-api/pkg/common"
+	"github.com/YaleSpinup/rds-api/pkg/session"
+)
+
+func TestMapAccountRegion(t *testing.T) {
+	sess := session.New(session.WithRegion("us-east-1"))
+	s := &server{
+		accountsMap: map[string]common.MappedAccount{
+			"spinup": {Id: "0123456789"},
+			"dr":     {Id: "9876543210", Region: "us-west-2"},
+		},
+		session: &sess,
+	}
+
+	tests := []struct {
+		name    string
+		account string
+		region  string
+		want    string
+	}{
+		{name: "api account region", account: "spinup", want: "us-east-1"},
+		{name: "mapped account region", account: "dr", want: "us-west-2"},
+		{name: "override", account: "dr", region: "us-east-2", want: "us-east-2"},
+		{name: "unmapped account", account: "1111111111", want: "us-east-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.mapAccountRegion(tt.account, tt.region); got != tt.want {
+				t.Errorf("mapAccountRegion() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if got := s.mapAccountNumber("dr"); got != "9876543210" {
+		t.Errorf("mapAccountNumber() = %s, want 9876543210", got)
+	}
+}
+
+func TestRegionPattern(t *testing.T) {
+	for region, valid := range map[string]bool{
+		"us-east-1":      true,
+		"eu-central-2":   true,
+		"us-gov-west-1":  true,
+		"US-EAST-1":      false,
+		"us-east":        false,
+		"us-east-1/../x": false,
+		"":               false,
+	} {
+		if got := regionPattern.MatchString(region); got != valid {
+			t.Errorf("expected %q valid to be %t, got %t", region, valid, got)
+		}
+	}
+}
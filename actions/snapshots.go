@@ -5,8 +5,10 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/YaleSpinup/apierror"
+	"github.com/YaleSpinup/rds-api/pkg/kms"
 	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
 	"github.com/pkg/errors"
 
@@ -28,14 +30,16 @@ func (s *server) SnapshotsPost(c buffalo.Context) error {
 	}
 
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
 
 	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
-	policy, err := generatePolicy("rds:CreateDBSnapshot", "rds:CreateDBClusterSnapshot")
+	policy, err := s.snapshotCreatePolicy(accountId, c.Param("db"), req.SnapshotIdentifier)
 	if err != nil {
 		return handleError(c, err)
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -48,6 +52,10 @@ func (s *server) SnapshotsPost(c buffalo.Context) error {
 
 	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
 
+	if err := s.ensureDatabaseOrg(c, rdsClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
 	orch := &rdsOrchestrator{
 		client: rdsClient,
 	}
@@ -84,14 +92,16 @@ func (s *server) SnapshotsPost(c buffalo.Context) error {
 // SnapshotsList gets a list of snapshots for a given database instance or cluster
 func (s *server) SnapshotsList(c buffalo.Context) error {
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
 
 	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
-	policy, err := generatePolicy("rds:DescribeDBClusterSnapshots", "rds:DescribeDBSnapshots")
+	policy, err := generatePolicy("rds:DescribeDBClusterSnapshots", "rds:DescribeDBSnapshots", "kms:DescribeKey", "kms:ListAliases")
 	if err != nil {
 		return handleError(c, err)
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -103,6 +113,7 @@ func (s *server) SnapshotsList(c buffalo.Context) error {
 	}
 
 	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+	kmsClient := kms.New(kms.WithSession(session.Session))
 
 	log.Printf("getting snapshots for %s", c.Param("db"))
 
@@ -120,19 +131,34 @@ func (s *server) SnapshotsList(c buffalo.Context) error {
 		return handleError(c, err)
 	}
 
+	// only snapshots belonging to the org are listed
+	clusterSnapshots := s.orgClusterSnapshots(clusterSnapshotsOutput.DBClusterSnapshots)
+	instanceSnapshots := s.orgSnapshots(instanceSnapshotsOutput.DBSnapshots)
+
 	var items int
-	if clusterSnapshotsOutput.DBClusterSnapshots != nil {
-		items = len(clusterSnapshotsOutput.DBClusterSnapshots)
+	if len(clusterSnapshots) > 0 {
+		items = len(clusterSnapshots)
 	} else {
-		items = len(instanceSnapshotsOutput.DBSnapshots)
+		items = len(instanceSnapshots)
+	}
+
+	// the keys protecting encrypted snapshots are described by their ARN
+	keyIds := []string{}
+	for _, snap := range clusterSnapshots {
+		keyIds = append(keyIds, aws.StringValue(snap.KmsKeyId))
+	}
+	for _, snap := range instanceSnapshots {
+		keyIds = append(keyIds, aws.StringValue(snap.KmsKeyId))
 	}
 
 	output := struct {
-		DBClusterSnapshots []*rds.DBClusterSnapshot `json:"DBClusterSnapshots,omitempty"`
-		DBSnapshots        []*rds.DBSnapshot        `json:"DBSnapshots,omitempty"`
+		DBClusterSnapshots []*rds.DBClusterSnapshot   `json:"DBClusterSnapshots,omitempty"`
+		DBSnapshots        []*rds.DBSnapshot          `json:"DBSnapshots,omitempty"`
+		KmsKeys            map[string]*KmsKeyResponse `json:"KmsKeys,omitempty"`
 	}{
-		clusterSnapshotsOutput.DBClusterSnapshots,
-		instanceSnapshotsOutput.DBSnapshots,
+		clusterSnapshots,
+		instanceSnapshots,
+		kmsKeys(c, &kmsClient, keyIds...),
 	}
 
 	c.Response().Header().Set("X-Items", strconv.Itoa(items))
@@ -142,14 +168,16 @@ func (s *server) SnapshotsList(c buffalo.Context) error {
 // SnapshotsGet returns information about a specific database snapshot
 func (s *server) SnapshotsGet(c buffalo.Context) error {
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
 	snapshotId := c.Param("snap")
 	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
-	policy, err := generatePolicy("rds:DescribeDBClusterSnapshots", "rds:DescribeDBSnapshots")
+	policy, err := generatePolicy("rds:DescribeDBClusterSnapshots", "rds:DescribeDBSnapshots", "kms:DescribeKey", "kms:ListAliases")
 	if err != nil {
 		return handleError(c, err)
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -161,6 +189,11 @@ func (s *server) SnapshotsGet(c buffalo.Context) error {
 	}
 
 	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+	kmsClient := kms.New(kms.WithSession(session.Session))
+
+	if err := s.ensureSnapshotOrg(c, rdsClient, c.Param("snap")); err != nil {
+		return handleError(c, err)
+	}
 
 	log.Printf("getting information about snapshot %s", snapshotId)
 
@@ -178,12 +211,22 @@ func (s *server) SnapshotsGet(c buffalo.Context) error {
 		return c.Error(404, errors.New("snapshot not found"))
 	}
 
+	keyIds := []string{}
+	if clusterSnapshot != nil {
+		keyIds = append(keyIds, aws.StringValue(clusterSnapshot.KmsKeyId))
+	}
+	if instanceSnapshot != nil {
+		keyIds = append(keyIds, aws.StringValue(instanceSnapshot.KmsKeyId))
+	}
+
 	output := struct {
-		DBClusterSnapshot *rds.DBClusterSnapshot `json:"DBClusterSnapshot,omitempty"`
-		DBSnapshot        *rds.DBSnapshot        `json:"DBSnapshot,omitempty"`
+		DBClusterSnapshot *rds.DBClusterSnapshot     `json:"DBClusterSnapshot,omitempty"`
+		DBSnapshot        *rds.DBSnapshot            `json:"DBSnapshot,omitempty"`
+		KmsKeys           map[string]*KmsKeyResponse `json:"KmsKeys,omitempty"`
 	}{
 		clusterSnapshot,
 		instanceSnapshot,
+		kmsKeys(c, &kmsClient, keyIds...),
 	}
 
 	return c.Render(200, r.JSON(output))
@@ -192,14 +235,16 @@ func (s *server) SnapshotsGet(c buffalo.Context) error {
 // SnapshotsDelete deletes a specific database snapshot
 func (s *server) SnapshotsDelete(c buffalo.Context) error {
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
 
 	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
-	policy, err := generatePolicy("rds:DeleteDBClusterSnapshot", "rds:DeleteDBSnapshot")
+	policy, err := s.snapshotPolicy(accountId, c.Param("snap"), "rds:DeleteDBClusterSnapshot", "rds:DeleteDBSnapshot")
 	if err != nil {
 		return handleError(c, err)
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -212,6 +257,10 @@ func (s *server) SnapshotsDelete(c buffalo.Context) error {
 
 	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
 
+	if err := s.ensureSnapshotOrg(c, rdsClient, c.Param("snap")); err != nil {
+		return handleError(c, err)
+	}
+
 	orch := &rdsOrchestrator{
 		client: rdsClient,
 	}
@@ -247,6 +296,7 @@ func (s *server) SnapshotsDelete(c buffalo.Context) error {
 
 func (s *server) SnapshotsVersionList(c buffalo.Context) error {
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
 	snapshotId := c.Param("snap")
 
 	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
@@ -256,6 +306,7 @@ func (s *server) SnapshotsVersionList(c buffalo.Context) error {
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -268,6 +319,10 @@ func (s *server) SnapshotsVersionList(c buffalo.Context) error {
 
 	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
 
+	if err := s.ensureSnapshotOrg(c, rdsClient, c.Param("snap")); err != nil {
+		return handleError(c, err)
+	}
+
 	sinfo, err := rdsClient.GetSnapshotInfo(c, snapshotId)
 	if err != nil {
 		return handleError(c, err)
@@ -281,6 +336,49 @@ func (s *server) SnapshotsVersionList(c buffalo.Context) error {
 	return c.Render(200, r.JSON(dbVersions))
 }
 
+// SnapshotsUpgradePath returns the shortest path of valid upgrades from the engine version of a snapshot in a given
+// account to the target version in the query, with the parameter group family required at each step
+func (s *server) SnapshotsUpgradePath(c buffalo.Context) error {
+	target := c.Param("target")
+	if target == "" {
+		return c.Error(400, errors.New("Bad request: specify the target engine version"))
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := generatePolicy("rds:DescribeDBClusterSnapshots", "rds:DescribeDBEngineVersions", "rds:DescribeDBSnapshots")
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	if err := s.ensureSnapshotOrg(c, rdsClient, c.Param("snap")); err != nil {
+		return handleError(c, err)
+	}
+
+	sinfo, err := rdsClient.GetSnapshotInfo(c, c.Param("snap"))
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	return s.renderUpgradePath(c, rdsClient, sinfo.Engine, sinfo.EngineVersion, target)
+}
+
 func (s *server) SnapshotModify(c buffalo.Context) error {
 	req := SnapshotModifyRequest{}
 	if err := c.Bind(&req); err != nil {
@@ -288,14 +386,16 @@ func (s *server) SnapshotModify(c buffalo.Context) error {
 	}
 
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
 
 	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
-	policy, err := generatePolicy("rds:ModifyDBSnapshot")
+	policy, err := s.snapshotPolicy(accountId, c.Param("snap"), "rds:ModifyDBSnapshot")
 	if err != nil {
 		return handleError(c, err)
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -308,6 +408,10 @@ func (s *server) SnapshotModify(c buffalo.Context) error {
 
 	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
 
+	if err := s.ensureSnapshotOrg(c, rdsClient, c.Param("snap")); err != nil {
+		return handleError(c, err)
+	}
+
 	resp, err := rdsClient.ModifyDBSnapshot(c, c.Param("snap"), req.EngineVersion)
 	if err != nil {
 		return handleError(c, err)
@@ -316,17 +420,19 @@ func (s *server) SnapshotModify(c buffalo.Context) error {
 	return c.Render(200, r.JSON(resp))
 }
 
-// SnapshotsDeleteNonProd deletes all the non production snapshot i.e(anything not labeled final-spin-foo).
+// SnapshotsDeleteNonProd deletes all the non production snapshots, i.e. anything but the final snapshots of deleted databases.
 func (s *server) SnapshotsDeleteNonProd(c buffalo.Context) error {
 	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
 
 	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
-	policy, err := generatePolicy("rds:DeleteDBClusterSnapshot", "rds:DeleteDBSnapshot")
+	policy, err := s.snapshotPolicy(accountId, "*", "rds:DeleteDBClusterSnapshot", "rds:DeleteDBSnapshot")
 	if err != nil {
 		return handleError(c, err)
 	}
 	session, err := s.assumeRole(
 		c,
+		region,
 		s.session.ExternalID,
 		role,
 		policy,
@@ -358,9 +464,10 @@ func (s *server) SnapshotsDeleteNonProd(c buffalo.Context) error {
 		DBSnapshot        []*rds.DBSnapshot        `json:"DBSnapshot,omitempty"`
 	}{}
 
+	// only snapshots belonging to the org are deleted
 	if clusterSnapshotsOutput.DBClusterSnapshots != nil {
-		for _, DBClusclusterSnapshot := range clusterSnapshotsOutput.DBClusterSnapshots {
-			if !strings.Contains(*DBClusclusterSnapshot.DBClusterSnapshotIdentifier, "final-spin") {
+		for _, DBClusclusterSnapshot := range s.orgClusterSnapshots(clusterSnapshotsOutput.DBClusterSnapshots) {
+			if !isFinalSnapshot(aws.StringValue(DBClusclusterSnapshot.DBClusterSnapshotIdentifier), DBClusclusterSnapshot.TagList) {
 				clusterSnapshot, err := orch.clusterSnapshotDelete(c, *DBClusclusterSnapshot.DBClusterSnapshotIdentifier)
 				if err != nil {
 					return err
@@ -372,8 +479,8 @@ func (s *server) SnapshotsDeleteNonProd(c buffalo.Context) error {
 	}
 
 	if instanceSnapshotsOutput.DBSnapshots != nil {
-		for _, DBSnapshot := range instanceSnapshotsOutput.DBSnapshots {
-			if !strings.Contains(*DBSnapshot.DBSnapshotIdentifier, "final-spin") {
+		for _, DBSnapshot := range s.orgSnapshots(instanceSnapshotsOutput.DBSnapshots) {
+			if !isFinalSnapshot(aws.StringValue(DBSnapshot.DBSnapshotIdentifier), DBSnapshot.TagList) {
 				instanceSnapshot, err := orch.instanceSnapshotDelete(c, *DBSnapshot.DBSnapshotIdentifier)
 				if err != nil {
 					return err
@@ -391,6 +498,26 @@ func (s *server) SnapshotsDeleteNonProd(c buffalo.Context) error {
 	return c.Render(200, r.JSON(output))
 }
 
+// finalSnapshotTag marks the final snapshot of a deleted database, its value is the name of the database
+const finalSnapshotTag = "spinup:final-snapshot"
+
+// finalSnapshotIdentifier returns the identifier for the final snapshot of the given database, timestamped so
+// deleting a database that was recreated with the same name doesn't collide with an earlier final snapshot
+func finalSnapshotIdentifier(id string, now time.Time) string {
+	return fmt.Sprintf("final-%s-%s", id, now.UTC().Format("20060102150405"))
+}
+
+// isFinalSnapshot returns true if the snapshot with the given identifier and tags is the final snapshot
+// of a deleted database.  Final snapshots from before they were tagged are recognized by their name.
+func isFinalSnapshot(id string, tags []*rds.Tag) bool {
+	for _, t := range tags {
+		if aws.StringValue(t.Key) == finalSnapshotTag {
+			return true
+		}
+	}
+	return strings.Contains(id, "final-spin")
+}
+
 func isNotFoundError(err error) bool {
 	if rerr, ok := err.(apierror.Error); ok {
 		return rerr.Code == apierror.ErrNotFound
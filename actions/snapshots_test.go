@@ -0,0 +1,54 @@
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+func TestFinalSnapshotIdentifier(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("EST", -5*60*60))
+	if got, want := finalSnapshotIdentifier("mydb", now), "final-mydb-20240102080405"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestIsFinalSnapshot(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		tags []*rds.Tag
+		want bool
+	}{
+		{
+			name: "tagged",
+			id:   "final-mydb-20240102080405",
+			tags: []*rds.Tag{{Key: aws.String(finalSnapshotTag), Value: aws.String("mydb")}},
+			want: true,
+		},
+		{
+			name: "legacy name",
+			id:   "final-spindb",
+			want: true,
+		},
+		{
+			name: "untagged final name",
+			id:   "final-mydb",
+		},
+		{
+			name: "manual snapshot",
+			id:   "mydb-manual",
+			tags: []*rds.Tag{{Key: aws.String("spinup:org"), Value: aws.String("localdev")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFinalSnapshot(tt.id, tt.tags); got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package actions
+
+import (
+	"errors"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/gobuffalo/buffalo"
+)
+
+// storage returns the storage configuration in the instance input
+func (i *CreateDBInstanceInput) storage() rdsapi.Storage {
+	return rdsapi.Storage{
+		StorageType:         aws.StringValue(i.StorageType),
+		AllocatedStorage:    aws.Int64Value(i.AllocatedStorage),
+		Iops:                aws.Int64Value(i.Iops),
+		StorageThroughput:   aws.Int64Value(i.StorageThroughput),
+		MaxAllocatedStorage: aws.Int64Value(i.MaxAllocatedStorage),
+	}
+}
+
+// storage returns the storage configuration in the restore request
+func (r *DatabaseRestoreRequest) storage() rdsapi.Storage {
+	return rdsapi.Storage{
+		StorageType:         aws.StringValue(r.StorageType),
+		AllocatedStorage:    aws.Int64Value(r.AllocatedStorage),
+		Iops:                aws.Int64Value(r.Iops),
+		StorageThroughput:   aws.Int64Value(r.StorageThroughput),
+		MaxAllocatedStorage: aws.Int64Value(r.MaxAllocatedStorage),
+	}
+}
+
+// modifyStorage returns the storage configuration changed by the given instance modification
+func modifyStorage(input *rds.ModifyDBInstanceInput) rdsapi.Storage {
+	return rdsapi.Storage{
+		StorageType:         aws.StringValue(input.StorageType),
+		AllocatedStorage:    aws.Int64Value(input.AllocatedStorage),
+		Iops:                aws.Int64Value(input.Iops),
+		StorageThroughput:   aws.Int64Value(input.StorageThroughput),
+		MaxAllocatedStorage: aws.Int64Value(input.MaxAllocatedStorage),
+	}
+}
+
+// snapshotStorage returns the storage configuration of the given instance snapshot
+func snapshotStorage(snapshot *rds.DBSnapshot) rdsapi.Storage {
+	return rdsapi.Storage{
+		StorageType:       aws.StringValue(snapshot.StorageType),
+		AllocatedStorage:  aws.Int64Value(snapshot.AllocatedStorage),
+		Iops:              aws.Int64Value(snapshot.Iops),
+		StorageThroughput: aws.Int64Value(snapshot.StorageThroughput),
+	}
+}
+
+// validateCreateStorage checks the storage configuration in a database create request.  The storage of a database
+// restored from a snapshot is checked against the snapshot once it's known.
+func validateCreateStorage(req *DatabaseCreateRequest) error {
+	if req.Cluster != nil {
+		if err := rdsapi.ValidateClusterStorageType(aws.StringValue(req.Cluster.Engine), aws.StringValue(req.Cluster.StorageType)); err != nil {
+			return err
+		}
+	}
+
+	if req.Instance != nil {
+		storage := req.Instance.storage()
+		if req.Cluster != nil && !storage.IsZero() {
+			return errors.New("the storage of cluster instances is configured on the cluster")
+		}
+
+		if err := rdsapi.ValidateStorage(aws.StringValue(req.Instance.Engine), storage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRestoreStorage checks the storage configuration in a point in time restore request of the given source
+// database cluster or instance
+func validateRestoreStorage(sourceCluster *rds.DBCluster, sourceInstance *rds.DBInstance, req *DatabaseRestoreRequest) error {
+	var err error
+	if sourceCluster != nil {
+		if storage := req.storage(); storage != (rdsapi.Storage{StorageType: storage.StorageType}) {
+			err = errors.New("only StorageType can be given when restoring a cluster")
+		} else {
+			err = rdsapi.ValidateClusterStorageType(aws.StringValue(sourceCluster.Engine), storage.StorageType)
+		}
+	} else if sourceInstance != nil {
+		err = rdsapi.ValidateStorageChange(aws.StringValue(sourceInstance.Engine), rdsapi.InstanceStorage(sourceInstance), req.storage())
+	}
+
+	if err != nil {
+		return apierror.New(apierror.ErrBadRequest, err.Error(), nil)
+	}
+	return nil
+}
+
+// validateModifyStorage checks the storage changes in a modification of the database with the given name against its
+// current storage configuration
+func (s *server) validateModifyStorage(c buffalo.Context, client *rdsapi.Client, id string, input *DatabaseModifyInput) error {
+	clusterChange := input.Cluster != nil && input.Cluster.StorageType != nil
+	instanceChange := input.Instance != nil && !modifyStorage(input.Instance).IsZero()
+	if !clusterChange && !instanceChange {
+		return nil
+	}
+
+	cluster, instance, err := client.DescribeDatabase(c, id)
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return err
+		}
+		return ErrCode("failed to describe database", err)
+	}
+
+	if clusterChange {
+		if cluster == nil {
+			err = errors.New("database " + id + " is not a cluster")
+		} else {
+			err = rdsapi.ValidateClusterStorageType(aws.StringValue(cluster.Engine), aws.StringValue(input.Cluster.StorageType))
+		}
+	}
+
+	if err == nil && instanceChange {
+		if instance == nil {
+			err = errors.New("database " + id + " is not an instance")
+		} else {
+			err = rdsapi.ValidateStorageChange(aws.StringValue(instance.Engine), rdsapi.InstanceStorage(instance), modifyStorage(input.Instance))
+		}
+	}
+
+	if err != nil {
+		return apierror.New(apierror.ErrBadRequest, err.Error(), nil)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+func TestValidateCreateStorage(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *DatabaseCreateRequest
+		wantErr bool
+	}{
+		{
+			name: "gp3 instance",
+			req: &DatabaseCreateRequest{
+				Instance: &CreateDBInstanceInput{
+					Engine:              aws.String("postgres"),
+					AllocatedStorage:    aws.Int64(500),
+					StorageType:         aws.String("gp3"),
+					Iops:                aws.Int64(12000),
+					MaxAllocatedStorage: aws.Int64(2000),
+				},
+			},
+		},
+		{
+			name: "io1 instance without iops",
+			req: &DatabaseCreateRequest{
+				Instance: &CreateDBInstanceInput{
+					Engine:           aws.String("postgres"),
+					AllocatedStorage: aws.Int64(500),
+					StorageType:      aws.String("io1"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "aurora i/o optimized cluster",
+			req: &DatabaseCreateRequest{
+				Cluster: &CreateDBClusterInput{
+					Engine:      aws.String("aurora-postgresql"),
+					StorageType: aws.String("aurora-iopt1"),
+				},
+				Instance: &CreateDBInstanceInput{
+					DBClusterIdentifier: aws.String("mycluster"),
+					Engine:              aws.String("aurora-postgresql"),
+				},
+			},
+		},
+		{
+			name: "aurora cluster with gp3",
+			req: &DatabaseCreateRequest{
+				Cluster: &CreateDBClusterInput{
+					Engine:      aws.String("aurora-postgresql"),
+					StorageType: aws.String("gp3"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "storage on cluster instance",
+			req: &DatabaseCreateRequest{
+				Cluster: &CreateDBClusterInput{
+					Engine: aws.String("aurora-postgresql"),
+				},
+				Instance: &CreateDBInstanceInput{
+					DBClusterIdentifier: aws.String("mycluster"),
+					StorageType:         aws.String("gp3"),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateCreateStorage(tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("validateCreateStorage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRestoreStorage(t *testing.T) {
+	cluster := &rds.DBCluster{Engine: aws.String("aurora-mysql")}
+	instance := &rds.DBInstance{Engine: aws.String("postgres"), StorageType: aws.String("gp2"), AllocatedStorage: aws.Int64(100)}
+
+	tests := []struct {
+		name     string
+		cluster  *rds.DBCluster
+		instance *rds.DBInstance
+		req      *DatabaseRestoreRequest
+		wantErr  bool
+	}{
+		{name: "cluster storage type", cluster: cluster, req: &DatabaseRestoreRequest{StorageType: aws.String("aurora-iopt1")}},
+		{name: "cluster allocated storage", cluster: cluster, req: &DatabaseRestoreRequest{AllocatedStorage: aws.Int64(100)}, wantErr: true},
+		{name: "instance to gp3", instance: instance, req: &DatabaseRestoreRequest{StorageType: aws.String("gp3")}},
+		{name: "instance grow", instance: instance, req: &DatabaseRestoreRequest{AllocatedStorage: aws.Int64(200)}},
+		{name: "instance shrink", instance: instance, req: &DatabaseRestoreRequest{AllocatedStorage: aws.Int64(50)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRestoreStorage(tt.cluster, tt.instance, tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("validateRestoreStorage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,7 +2,12 @@ package actions
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/YaleSpinup/rds-api/pkg/redact"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/rds"
 )
@@ -12,9 +17,10 @@ type DatabaseCreateRequest struct {
 	Instance *CreateDBInstanceInput
 }
 
+// String returns the request as indented JSON, with the master user password masked
 func (dcr DatabaseCreateRequest) String() string {
 	s, _ := json.MarshalIndent(dcr, "", "\t")
-	return string(s)
+	return redact.String(string(s))
 }
 
 type SnapshotCreateRequest struct {
@@ -25,48 +31,116 @@ type SnapshotModifyRequest struct {
 	EngineVersion string
 }
 
-// CreateDBInstanceInput is the input for creating a new database instance
-// based on https://docs.aws.amazon.com/sdk-for-go/api/service/rds/#CreateDBInstanceInput
-type CreateDBInstanceInput struct {
+// SnapshotCopyRequest is the input for copying a database instance or cluster snapshot.  TargetAccount is the name
+// of a mapped account and defaults to the account of the snapshot, TargetRegion defaults to the region of the target
+// account.  KmsKeyId is the key in the target account and region to encrypt the copy with.  With CopyTags the tags
+// of the snapshot are copied, Tags are added to them.
+type SnapshotCopyRequest struct {
+	TargetSnapshotIdentifier string
+	TargetAccount            string
+	TargetRegion             string
+	KmsKeyId                 *string
+	CopyTags                 bool
+	Tags                     []*Tag
+}
+
+// SnapshotCopyResponse is the output from copying a snapshot, with the account and region of the copy
+type SnapshotCopyResponse struct {
+	DBClusterSnapshot *rds.DBClusterSnapshot `json:",omitempty"`
+	DBSnapshot        *rds.DBSnapshot        `json:",omitempty"`
+	Account           string
+	Region            string
+	OperationID       string
+}
+
+// DatabaseRestoreRequest is the input for restoring a database cluster or instance to a point in time.
+// Either RestoreTime or UseLatestRestorableTime must be given.  DBInstanceClass is required for the
+// instance created in a restored (non-serverless) cluster, for an instance it defaults to the source class.
+// InstanceCount is the number of instances to create in a restored cluster (default 1).
+// StorageType is the storage type of the restored cluster or instance, the other storage
+// parameters only apply to a restored instance.  KmsKeyId only applies to a restored cluster.
+type DatabaseRestoreRequest struct {
+	TargetIdentifier            *string
+	InstanceCount               *int64
+	RestoreTime                 *time.Time
+	UseLatestRestorableTime     *bool
 	AllocatedStorage            *int64
-	BackupRetentionPeriod       *int64
-	DBClusterIdentifier         *string
+	DBClusterParameterGroupName *string
 	DBInstanceClass             *string
-	DBInstanceIdentifier        *string
 	DBParameterGroupName        *string
 	DBSubnetGroupName           *string
+	DeletionProtection          *bool
 	EnableCloudwatchLogsExports []*string
-	Engine                      *string
-	EngineVersion               *string
-	LicenseModel                *string
-	MasterUserPassword          *string
-	MasterUsername              *string
+	Iops                        *int64
+	KmsKeyId                    *string
+	MaxAllocatedStorage         *int64
 	MultiAZ                     *bool
 	Port                        *int64
-	SnapshotIdentifier          *string
-	StorageEncrypted            *bool
+	StorageThroughput           *int64
+	StorageType                 *string
 	Tags                        []*Tag
 	VpcSecurityGroupIds         []*string
 }
 
+// CreateDBInstanceInput is the input for creating a new database instance
+// based on https://docs.aws.amazon.com/sdk-for-go/api/service/rds/#CreateDBInstanceInput
+type CreateDBInstanceInput struct {
+	AllocatedStorage                *int64
+	BackupRetentionPeriod           *int64
+	DBClusterIdentifier             *string
+	DBInstanceClass                 *string
+	DBInstanceIdentifier            *string
+	DBParameterGroupName            *string
+	DBSubnetGroupName               *string
+	DeletionProtection              *bool
+	EnableCloudwatchLogsExports     []*string
+	EnableIAMDatabaseAuthentication *bool
+	Engine                          *string
+	EngineVersion                   *string
+	Iops                            *int64
+	KmsKeyId                        *string
+	LicenseModel                    *string
+	ManageMasterUserPassword        *bool
+	MasterUserPassword              *string
+	MasterUserSecretKmsKeyId        *string
+	MasterUsername                  *string
+	MaxAllocatedStorage             *int64
+	MultiAZ                         *bool
+	Port                            *int64
+	SnapshotIdentifier              *string
+	StorageEncrypted                *bool
+	StorageThroughput               *int64
+	StorageType                     *string
+	Tags                            []*Tag
+	VpcSecurityGroupIds             []*string
+}
+
 // CreateDBClusterInput is the input for creating a new database cluster
 // based on https://docs.aws.amazon.com/sdk-for-go/api/service/rds/#CreateDBClusterInput
+// InstanceCount is the number of instances to create in a cluster restored from a snapshot (default 1)
 type CreateDBClusterInput struct {
 	BackupRetentionPeriod            *int64
 	DBClusterIdentifier              *string
 	DBClusterParameterGroupName      *string
 	DBSubnetGroupName                *string
+	DeletionProtection               *bool
 	EnableCloudwatchLogsExports      []*string
+	EnableIAMDatabaseAuthentication  *bool
 	Engine                           *string
 	EngineMode                       *string
 	EngineVersion                    *string
+	InstanceCount                    *int64
+	KmsKeyId                         *string
+	ManageMasterUserPassword         *bool
 	MasterUserPassword               *string
+	MasterUserSecretKmsKeyId         *string
 	MasterUsername                   *string
 	Port                             *int64
 	ScalingConfiguration             *ScalingConfiguration
 	ServerlessV2ScalingConfiguration *ServerlessV2ScalingConfiguration
 	SnapshotIdentifier               *string
 	StorageEncrypted                 *bool
+	StorageType                      *string
 	Tags                             []*Tag
 	VpcSecurityGroupIds              []*string
 }
@@ -89,12 +163,130 @@ type Tag struct {
 	Value *string
 }
 
+// ReplicaCreateRequest is the input for creating a read replica of a database instance
+// DBInstanceClass defaults to the class of the source instance.
+type ReplicaCreateRequest struct {
+	DBInstanceIdentifier *string
+	AvailabilityZone     *string
+	DBInstanceClass      *string
+	DBParameterGroupName *string
+	DBSubnetGroupName    *string
+	MultiAZ              *bool
+	Port                 *int64
+	Tags                 []*Tag
+	VpcSecurityGroupIds  []*string
+}
+
+// ClusterMembersRequest is the input for adding reader instances to a database cluster
+// DBInstanceClass defaults to the class of the existing cluster members.
+type ClusterMembersRequest struct {
+	Count           int64
+	DBInstanceClass *string
+}
+
+// ReplicaPromoteRequest is the input for promoting a read replica to a standalone database instance
+type ReplicaPromoteRequest struct {
+	BackupRetentionPeriod *int64
+}
+
 // DatabaseResponse is the output from database operations
 type DatabaseResponse struct {
 	// https://docs.aws.amazon.com/sdk-for-go/api/service/rds/#DBCluster
 	Cluster *rds.DBCluster
 	// https://docs.aws.amazon.com/sdk-for-go/api/service/rds/#DBInstance
 	Instance *rds.DBInstance
+	// Replicas are the read replicas deleted along with the instance
+	Replicas []*rds.DBInstance `json:",omitempty"`
+	// Members are the reader instances created in the cluster, or the members deleted along with it
+	Members []*rds.DBInstance `json:",omitempty"`
+	// FinalSnapshotIdentifier is the final snapshot created when deleting the database
+	FinalSnapshotIdentifier string `json:",omitempty"`
+	// OperationID can be used to follow the progress of the operation
+	OperationID string `json:",omitempty"`
+	// Region is the region the database is in
+	Region string `json:",omitempty"`
+}
+
+// DatabaseDryRunResponse is the output from validating a database create request with dryRun
+type DatabaseDryRunResponse struct {
+	Valid    bool
+	Problems []string
+	Region   string
+}
+
+// CatalogEngineVersion is a database engine version in the catalog
+type CatalogEngineVersion struct {
+	Engine                 string
+	EngineVersion          string
+	DBParameterGroupFamily string
+	Status                 string
+	Deprecated             bool
+}
+
+// key returns the key used for sorting and paging engine versions
+func (v *CatalogEngineVersion) key() string {
+	return v.Engine + "/" + versionKey(v.EngineVersion)
+}
+
+// CatalogInstanceClass is a database instance class that can be ordered for an engine version
+type CatalogInstanceClass struct {
+	DBInstanceClass   string
+	Engine            string
+	EngineVersion     string
+	StorageTypes      []string
+	AvailabilityZones []string
+	MultiAZCapable    bool
+	SupportsClusters  bool
+}
+
+// key returns the key used for sorting and paging instance classes
+func (cl *CatalogInstanceClass) key() string {
+	return cl.DBInstanceClass + "/" + versionKey(cl.EngineVersion)
+}
+
+// versionKey returns a key for the given engine version that sorts by version when compared as a string,
+// by zero padding the numeric parts (e.g. 9.6 sorts before 16.1).  Other parts are left as they are.
+func versionKey(version string) string {
+	parts := strings.Split(version, ".")
+	for i, p := range parts {
+		if _, err := strconv.ParseUint(p, 10, 64); err == nil {
+			parts[i] = fmt.Sprintf("%020s", p)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// KmsKeyResponse describes the KMS key protecting an encrypted resource.  KeyManager is AWS
+// for AWS managed keys and CUSTOMER for customer managed keys.
+type KmsKeyResponse struct {
+	KeyId      string
+	Arn        string
+	KeyManager string
+	KeyState   string
+	Aliases    []string
+}
+
+// OperationResponse is the output from the operations endpoint
+type OperationResponse struct {
+	ID        string
+	Type      string
+	Account   string
+	Region    string
+	Database  string
+	Status    string
+	Error     string `json:",omitempty"`
+	Steps     []*OperationStep
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// OperationStep is a single step recorded during an operation
+type OperationStep struct {
+	Name       string
+	Status     string
+	Error      string `json:",omitempty"`
+	StartedAt  time.Time
+	FinishedAt *time.Time `json:",omitempty"`
 }
 
 // DatabaseModifyInput is the input for modifying an existing database
@@ -106,11 +298,87 @@ type DatabaseModifyInput struct {
 	Tags     []*rds.Tag
 }
 
+// String returns the input as JSON, with the master user password masked
+func (dmi DatabaseModifyInput) String() string {
+	s, _ := json.Marshal(dmi)
+	return redact.String(string(s))
+}
+
 // DatabaseStateInput is the input for changing the database state
 type DatabaseStateInput struct {
 	State string
 }
 
+// DatabaseRebootInput is the input for rebooting a database
+type DatabaseRebootInput struct {
+	ForceFailover bool
+}
+
+// DatabaseFailoverInput is the input for failing over a database cluster
+type DatabaseFailoverInput struct {
+	TargetDBInstanceIdentifier string
+}
+
+// DatabaseUpgradeRequest is the input for upgrading the engine version of a database.  The parameter groups
+// default to the ones in the config for the parameter group family of the new version on major upgrades.
+type DatabaseUpgradeRequest struct {
+	DBClusterParameterGroupName *string
+	DBParameterGroupName        *string
+	EngineVersion               string
+	SnapshotBeforeUpgrade       bool
+}
+
+// DatabaseUpgradeResponse is the output from starting a database upgrade
+type DatabaseUpgradeResponse struct {
+	DBClusterIdentifier         string `json:",omitempty"`
+	DBInstanceIdentifier        string `json:",omitempty"`
+	EngineVersion               string
+	IsMajorVersionUpgrade       bool
+	DBParameterGroupFamily      string
+	DBClusterParameterGroupName string `json:",omitempty"`
+	DBParameterGroupName        string `json:",omitempty"`
+	SnapshotIdentifier          string `json:",omitempty"`
+	OperationID                 string
+	Region                      string
+}
+
+// DatabaseUnprotectRequest is the input for removing the deletion protection of a database.
+// The reason is required and logged, so the removal can be audited.
+type DatabaseUnprotectRequest struct {
+	Reason string
+}
+
+// MaintenanceApplyRequest is the input for applying a pending maintenance action to a database.
+// OptInType is one of "immediate", "next-maintenance" or "undo-opt-in".
+type MaintenanceApplyRequest struct {
+	ApplyAction string
+	OptInType   string
+}
+
+// AuthTokenRequest is the input for issuing an IAM database authentication token
+type AuthTokenRequest struct {
+	DBUser string
+}
+
+// AuthTokenResponse is the output from issuing an IAM database authentication token, used as
+// the password to connect to the database at the given endpoint as the given user
+type AuthTokenResponse struct {
+	AuthToken string
+	DBUser    string
+	Endpoint  string
+	Port      int64
+	ExpiresAt time.Time
+}
+
+// CredentialsRotateResponse is the output from rotating the master user credentials of a database.
+// MasterUserPassword is only set when the password isn't managed in Secrets Manager, and it's only returned once.
+type CredentialsRotateResponse struct {
+	MasterUserSecretArn string `json:",omitempty"`
+	MasterUserPassword  string `json:",omitempty"`
+	OperationID         string `json:",omitempty"`
+	Region              string `json:",omitempty"`
+}
+
 // normalizeTags strips the org from the given tags and ensures it is set to the API org
 func normalizeTags(tags []*Tag) []*Tag {
 	normalizedTags := []*Tag{}
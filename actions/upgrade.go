@@ -0,0 +1,249 @@
+package actions
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/gobuffalo/buffalo"
+	"github.com/pkg/errors"
+)
+
+// DatabasesUpgrade upgrades the engine version of a database in a given account.  The target version is validated
+// against the valid upgrade targets of the current version, and on major upgrades the cluster and instance parameter
+// groups are switched to the defaults for the new parameter group family.  If a snapshot is requested, the upgrade
+// is applied in the background once the snapshot is available.  Progress is reported through the operation.
+func (s *server) DatabasesUpgrade(c buffalo.Context) error {
+	req := DatabaseUpgradeRequest{}
+	if err := c.Bind(&req); err != nil {
+		log.Println(err)
+		return c.Error(400, err)
+	}
+
+	if req.EngineVersion == "" {
+		return c.Error(400, errors.New("Bad request: specify EngineVersion in request"))
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	// the upgrade is planned with a read only session, before a session scoped to the database is requested
+	readClient, err := s.readOnlyClient(accountId, region)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	if err := s.ensureDatabaseOrg(c, readClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	cluster, instance, err := readClient.DescribeDatabase(c, c.Param("db"))
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to describe database", err))
+	}
+
+	// cluster members are upgraded with their cluster
+	input := &rdsapi.UpgradeInput{EngineVersion: req.EngineVersion}
+	var engine, currentVersion string
+	if cluster != nil {
+		input.Identifier, input.Cluster = aws.StringValue(cluster.DBClusterIdentifier), true
+		engine, currentVersion = aws.StringValue(cluster.Engine), aws.StringValue(cluster.EngineVersion)
+	} else {
+		input.Identifier = aws.StringValue(instance.DBInstanceIdentifier)
+		engine, currentVersion = aws.StringValue(instance.Engine), aws.StringValue(instance.EngineVersion)
+	}
+
+	target, err := readClient.ValidUpgradeTarget(c, engine, currentVersion, req.EngineVersion)
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to describe engine versions", err))
+	}
+	input.AllowMajorVersionUpgrade = aws.BoolValue(target.IsMajorVersionUpgrade)
+
+	family, err := readClient.DetermineParameterGroupFamily(aws.String(engine), aws.String(req.EngineVersion))
+	if err != nil {
+		return handleError(c, ErrCode("failed to determine parameter group family", err))
+	}
+
+	input.DBClusterParameterGroupName, input.DBParameterGroupName = s.upgradeParameterGroups(family, input.Cluster, input.AllowMajorVersionUpgrade, &req)
+
+	snapshot := ""
+	if req.SnapshotBeforeUpgrade {
+		snapshot = fmt.Sprintf("preupgrade-%s-%s", input.Identifier, time.Now().UTC().Format("20060102150405"))
+	}
+
+	role := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.session.RoleName)
+	policy, err := s.databaseUpgradePolicy(accountId, input.Identifier, input.DBClusterParameterGroupName, input.DBParameterGroupName, snapshot)
+	if err != nil {
+		return handleError(c, err)
+	}
+	session, err := s.assumeRole(
+		c,
+		region,
+		s.session.ExternalID,
+		role,
+		policy,
+		"arn:aws:iam::aws:policy/AmazonRDSReadOnlyAccess",
+	)
+	if err != nil {
+		msg := fmt.Sprintf("failed to assume role in account: %s", accountId)
+		return handleError(c, apierror.New(apierror.ErrForbidden, msg, err))
+	}
+
+	rdsClient := rdsapi.NewSession(session.Session, s.defaultConfig)
+
+	op := s.newOperation("upgrade", accountId, region, input.Identifier)
+	c.Response().Header().Set("X-Operation-Id", op.id())
+
+	resp := &DatabaseUpgradeResponse{
+		EngineVersion:               req.EngineVersion,
+		IsMajorVersionUpgrade:       input.AllowMajorVersionUpgrade,
+		DBParameterGroupFamily:      family,
+		DBClusterParameterGroupName: input.DBClusterParameterGroupName,
+		DBParameterGroupName:        input.DBParameterGroupName,
+		SnapshotIdentifier:          snapshot,
+		OperationID:                 op.id(),
+		Region:                      region,
+	}
+	if input.Cluster {
+		resp.DBClusterIdentifier = input.Identifier
+	} else {
+		resp.DBInstanceIdentifier = input.Identifier
+	}
+
+	waits := []operationWait{}
+	if snapshot != "" {
+		op.setStatus(operationBackingUp)
+
+		step := op.startStep("create snapshot " + snapshot)
+		if input.Cluster {
+			_, err = rdsClient.Service.CreateDBClusterSnapshotWithContext(c, &rds.CreateDBClusterSnapshotInput{
+				DBClusterIdentifier:         aws.String(input.Identifier),
+				DBClusterSnapshotIdentifier: aws.String(snapshot),
+			})
+		} else {
+			_, err = rdsClient.Service.CreateDBSnapshotWithContext(c, &rds.CreateDBSnapshotInput{
+				DBInstanceIdentifier: aws.String(input.Identifier),
+				DBSnapshotIdentifier: aws.String(snapshot),
+			})
+		}
+		if err != nil {
+			step.fail(err)
+			op.fail(err)
+			return handleError(c, ErrCode("failed to create pre-upgrade snapshot", err))
+		}
+		step.complete()
+
+		// the database can't be modified while it's backing up, so the upgrade is applied by the watcher
+		waits = append(waits, waitSnapshotAvailable(snapshot, input.Cluster, operationBackingUp), upgradeDatabase(input))
+	} else {
+		op.setStatus(operationUpgrading)
+
+		step := op.startStep("upgrade database " + input.Identifier + " to engine version " + input.EngineVersion)
+		if err := rdsClient.UpgradeDatabase(c, input); err != nil {
+			step.fail(err)
+			op.fail(err)
+			return handleError(c, ErrCode("failed to upgrade database", err))
+		}
+		step.complete()
+	}
+
+	// the database can still be available before the upgrade starts, so it's only done once it runs the new version
+	waits = append(waits, waitEngineVersion(input), waitDatabaseAvailable(input.Identifier, operationUpgrading))
+
+	s.watchOperation(op, s.scopedClient(accountId, region, policy), operationAvailable, waits...)
+
+	return c.Render(200, r.JSON(resp))
+}
+
+// upgradeParameterGroups returns the cluster and instance parameter groups to switch to in an upgrade to the given
+// parameter group family.  The ones in the request take precedence, then on major upgrades the defaults for the family
+// from the config, or the AWS default parameter groups.  Minor upgrades keep the current parameter groups.
+func (s *server) upgradeParameterGroups(family string, cluster, major bool, req *DatabaseUpgradeRequest) (string, string) {
+	var clusterParameterGroup, parameterGroup string
+
+	if major {
+		parameterGroup = "default." + family
+		if pg, ok := s.defaultConfig.DefaultDBParameterGroupName[family]; ok {
+			parameterGroup = pg
+		}
+
+		if cluster {
+			clusterParameterGroup = "default." + family
+			if pg, ok := s.defaultConfig.DefaultDBClusterParameterGroupName[family]; ok {
+				clusterParameterGroup = pg
+			}
+		}
+	}
+
+	if req.DBParameterGroupName != nil {
+		parameterGroup = aws.StringValue(req.DBParameterGroupName)
+	}
+
+	if cluster && req.DBClusterParameterGroupName != nil {
+		clusterParameterGroup = aws.StringValue(req.DBClusterParameterGroupName)
+	}
+
+	return clusterParameterGroup, parameterGroup
+}
+
+// DatabasesUpgradePath returns the shortest path of valid upgrades from the engine version of a database in a given
+// account to the target version in the query, with the parameter group family required at each step
+func (s *server) DatabasesUpgradePath(c buffalo.Context) error {
+	target := c.Param("target")
+	if target == "" {
+		return c.Error(400, errors.New("Bad request: specify the target engine version"))
+	}
+
+	accountId := s.mapAccountNumber(c.Param("account"))
+	region := s.accountRegion(c)
+
+	rdsClient, err := s.readOnlyClient(accountId, region)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	if err := s.ensureDatabaseOrg(c, rdsClient, c.Param("db")); err != nil {
+		return handleError(c, err)
+	}
+
+	cluster, instance, err := rdsClient.DescribeDatabase(c, c.Param("db"))
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to describe database", err))
+	}
+
+	// cluster members are upgraded with their cluster
+	var engine, version string
+	if cluster != nil {
+		engine, version = aws.StringValue(cluster.Engine), aws.StringValue(cluster.EngineVersion)
+	} else {
+		engine, version = aws.StringValue(instance.Engine), aws.StringValue(instance.EngineVersion)
+	}
+
+	return s.renderUpgradePath(c, rdsClient, engine, version, target)
+}
+
+// renderUpgradePath plans and renders the upgrade path from the given engine version to the target version
+func (s *server) renderUpgradePath(c buffalo.Context, client *rdsapi.Client, engine, version, target string) error {
+	path, err := client.PlanUpgradePath(c, engine, version, target)
+	if err != nil {
+		if _, ok := err.(apierror.Error); ok {
+			return handleError(c, err)
+		}
+		return handleError(c, ErrCode("failed to describe engine versions", err))
+	}
+
+	return c.Render(200, r.JSON(path))
+}
@@ -0,0 +1,60 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/YaleSpinup/rds-api/pkg/common"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestUpgradeParameterGroups(t *testing.T) {
+	s := &server{
+		defaultConfig: common.CommonConfig{
+			DefaultDBParameterGroupName:        map[string]string{"postgres14": "spinup-postgres14"},
+			DefaultDBClusterParameterGroupName: map[string]string{"aurora-postgresql14": "spinup-aurora-postgresql14"},
+		},
+	}
+
+	tests := []struct {
+		name                  string
+		family                string
+		cluster               bool
+		major                 bool
+		req                   DatabaseUpgradeRequest
+		clusterParameterGroup string
+		parameterGroup        string
+	}{
+		{
+			name:           "major instance upgrade with configured default",
+			family:         "postgres14",
+			major:          true,
+			parameterGroup: "spinup-postgres14",
+		},
+		{
+			name:                  "major cluster upgrade with configured and aws defaults",
+			family:                "aurora-postgresql14",
+			cluster:               true,
+			major:                 true,
+			clusterParameterGroup: "spinup-aurora-postgresql14",
+			parameterGroup:        "default.aurora-postgresql14",
+		},
+		{
+			name:   "minor upgrade keeps parameter groups",
+			family: "postgres14",
+		},
+		{
+			name:           "parameter group in request",
+			family:         "postgres14",
+			major:          true,
+			req:            DatabaseUpgradeRequest{DBParameterGroupName: aws.String("mypg"), DBClusterParameterGroupName: aws.String("ignored")},
+			parameterGroup: "mypg",
+		},
+	}
+
+	for _, test := range tests {
+		clusterParameterGroup, parameterGroup := s.upgradeParameterGroups(test.family, test.cluster, test.major, &test.req)
+		if clusterParameterGroup != test.clusterParameterGroup || parameterGroup != test.parameterGroup {
+			t.Errorf("%s: expected %q and %q, got %q and %q", test.name, test.clusterParameterGroup, test.parameterGroup, clusterParameterGroup, parameterGroup)
+		}
+	}
+}
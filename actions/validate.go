@@ -0,0 +1,264 @@
+package actions
+
+import (
+	"fmt"
+
+	"github.com/YaleSpinup/apierror"
+	rdsapi "github.com/YaleSpinup/rds-api/pkg/rds"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/gobuffalo/buffalo"
+)
+
+// databaseCreateDryRun validates a database create request against the account without creating anything,
+// and renders every problem found
+func (s *server) databaseCreateDryRun(c buffalo.Context, accountId, region string, req *DatabaseCreateRequest) error {
+	policy, err := databaseValidatePolicy()
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	rdsClient, err := s.scopedClient(accountId, region, policy)(c)
+	if err != nil {
+		return handleError(c, err)
+	}
+
+	problems := databaseCreateProblems(req)
+	if err := validateCreateStorage(req); err != nil {
+		problems = append(problems, "storage: "+err.Error())
+	}
+
+	if err := validateCreateKmsKeys(req); err != nil {
+		problems = append(problems, err.Error())
+	} else {
+		s.setDefaultKmsKeys(c.Param("account"), region, req)
+		if err := s.ensureCreateKmsKeys(c, accountId, region, req); err != nil {
+			aerr, ok := err.(apierror.Error)
+			if !ok || (aerr.Code != apierror.ErrBadRequest && aerr.Code != apierror.ErrNotFound) {
+				return handleError(c, err)
+			}
+			problems = append(problems, "KmsKeyId: "+aerr.Message)
+		}
+	}
+
+	remote, err := s.databaseCreateRemoteProblems(c, rdsClient, req)
+	if err != nil {
+		return handleError(c, err)
+	}
+	problems = append(problems, remote...)
+
+	return c.Render(200, r.JSON(&DatabaseDryRunResponse{
+		Valid:    len(problems) == 0,
+		Problems: problems,
+		Region:   region,
+	}))
+}
+
+// databaseCreateProblems returns the problems with a database create request that can be found without
+// looking at the account: invalid identifiers and missing required fields
+func databaseCreateProblems(req *DatabaseCreateRequest) []string {
+	problems := []string{}
+
+	if req.Cluster != nil {
+		if err := rdsapi.ValidateIdentifier(aws.StringValue(req.Cluster.DBClusterIdentifier)); err != nil {
+			problems = append(problems, "Cluster.DBClusterIdentifier: "+err.Error())
+		}
+
+		// restored clusters take the engine and master username from the snapshot
+		if req.Cluster.SnapshotIdentifier == nil {
+			if aws.StringValue(req.Cluster.Engine) == "" {
+				problems = append(problems, "Cluster.Engine is required")
+			}
+			if aws.StringValue(req.Cluster.MasterUsername) == "" {
+				problems = append(problems, "Cluster.MasterUsername is required")
+			}
+		}
+	}
+
+	if req.Instance != nil {
+		if err := rdsapi.ValidateIdentifier(aws.StringValue(req.Instance.DBInstanceIdentifier)); err != nil {
+			problems = append(problems, "Instance.DBInstanceIdentifier: "+err.Error())
+		}
+
+		if aws.StringValue(req.Instance.DBInstanceClass) == "" {
+			problems = append(problems, "Instance.DBInstanceClass is required")
+		}
+
+		if req.Instance.SnapshotIdentifier == nil {
+			if aws.StringValue(req.Instance.Engine) == "" {
+				problems = append(problems, "Instance.Engine is required")
+			}
+
+			// cluster members use the master username of the cluster
+			if req.Instance.DBClusterIdentifier == nil && aws.StringValue(req.Instance.MasterUsername) == "" {
+				problems = append(problems, "Instance.MasterUsername is required")
+			}
+		}
+
+		if req.Cluster != nil && req.Instance.DBClusterIdentifier != nil &&
+			aws.StringValue(req.Instance.DBClusterIdentifier) != aws.StringValue(req.Cluster.DBClusterIdentifier) {
+			problems = append(problems, fmt.Sprintf("Instance.DBClusterIdentifier %s doesn't match Cluster.DBClusterIdentifier %s",
+				aws.StringValue(req.Instance.DBClusterIdentifier), aws.StringValue(req.Cluster.DBClusterIdentifier)))
+		}
+	}
+
+	return problems
+}
+
+// databaseCreateRemoteProblems returns the problems with a database create request found by looking at the
+// account: unknown engine versions, instance classes that can't be ordered for the engine, missing subnet and
+// parameter groups and missing or foreign snapshots
+func (s *server) databaseCreateRemoteProblems(c buffalo.Context, client *rdsapi.Client, req *DatabaseCreateRequest) ([]string, error) {
+	problems := []string{}
+	subnetGroups := map[string]bool{}
+
+	var clusterEngine, clusterVersion string
+
+	if req.Cluster != nil {
+		clusterEngine, clusterVersion = aws.StringValue(req.Cluster.Engine), aws.StringValue(req.Cluster.EngineVersion)
+
+		if req.Cluster.SnapshotIdentifier != nil {
+			p, err := s.snapshotProblems(c, client, "Cluster", aws.StringValue(req.Cluster.SnapshotIdentifier))
+			if err != nil {
+				return nil, err
+			}
+			problems = append(problems, p...)
+		}
+
+		family := ""
+		if clusterEngine != "" {
+			version, err := client.DescribeEngineVersion(c, clusterEngine, clusterVersion)
+			if err != nil {
+				return nil, ErrCode("failed to describe engine version", err)
+			}
+			if version == nil {
+				problems = append(problems, fmt.Sprintf("Cluster.EngineVersion: engine %s version %s is not available", clusterEngine, clusterVersion))
+			} else {
+				family = aws.StringValue(version.DBParameterGroupFamily)
+			}
+		}
+
+		subnetGroups[subnetGroupOrDefault(client, req.Cluster.DBSubnetGroupName)] = true
+
+		group := aws.StringValue(req.Cluster.DBClusterParameterGroupName)
+		if group == "" && family != "" {
+			group = client.DefaultDBClusterParameterGroupName[family]
+		}
+		if group != "" {
+			exists, err := client.ClusterParameterGroupExists(c, group)
+			if err != nil {
+				return nil, ErrCode("failed to describe cluster parameter group", err)
+			}
+			if !exists {
+				problems = append(problems, fmt.Sprintf("Cluster.DBClusterParameterGroupName: cluster parameter group %s doesn't exist", group))
+			}
+		}
+	}
+
+	if req.Instance != nil {
+		engine, engineVersion := aws.StringValue(req.Instance.Engine), aws.StringValue(req.Instance.EngineVersion)
+
+		// cluster members run the engine version of the cluster
+		if req.Instance.DBClusterIdentifier != nil && engineVersion == "" {
+			engineVersion = clusterVersion
+		}
+
+		if req.Instance.SnapshotIdentifier != nil {
+			p, err := s.snapshotProblems(c, client, "Instance", aws.StringValue(req.Instance.SnapshotIdentifier))
+			if err != nil {
+				return nil, err
+			}
+			problems = append(problems, p...)
+		}
+
+		family := ""
+		versionExists := true
+		if engine != "" {
+			version, err := client.DescribeEngineVersion(c, engine, engineVersion)
+			if err != nil {
+				return nil, ErrCode("failed to describe engine version", err)
+			}
+			if version == nil {
+				versionExists = false
+				problems = append(problems, fmt.Sprintf("Instance.EngineVersion: engine %s version %s is not available", engine, engineVersion))
+			} else {
+				family = aws.StringValue(version.DBParameterGroupFamily)
+			}
+		}
+
+		if class := aws.StringValue(req.Instance.DBInstanceClass); engine != "" && class != "" && versionExists {
+			orderable, err := client.OrderableInstanceClass(c, engine, engineVersion, class)
+			if err != nil {
+				return nil, ErrCode("failed to describe orderable instance options", err)
+			}
+			if !orderable {
+				problems = append(problems, fmt.Sprintf("Instance.DBInstanceClass: instance class %s is not available for engine %s %s", class, engine, engineVersion))
+			}
+		}
+
+		// cluster members are created in the subnet group of the cluster
+		if req.Instance.DBClusterIdentifier == nil || req.Cluster == nil {
+			subnetGroups[subnetGroupOrDefault(client, req.Instance.DBSubnetGroupName)] = true
+		}
+
+		group := aws.StringValue(req.Instance.DBParameterGroupName)
+		if group == "" && family != "" {
+			group = client.DefaultDBParameterGroupName[family]
+		}
+		if group != "" {
+			exists, err := client.ParameterGroupExists(c, group)
+			if err != nil {
+				return nil, ErrCode("failed to describe parameter group", err)
+			}
+			if !exists {
+				problems = append(problems, fmt.Sprintf("Instance.DBParameterGroupName: parameter group %s doesn't exist", group))
+			}
+		}
+	}
+
+	for group := range subnetGroups {
+		if group == "" {
+			continue
+		}
+
+		exists, err := client.SubnetGroupExists(c, group)
+		if err != nil {
+			return nil, ErrCode("failed to describe subnet group", err)
+		}
+		if !exists {
+			problems = append(problems, fmt.Sprintf("DBSubnetGroupName: subnet group %s doesn't exist", group))
+		}
+	}
+
+	return problems, nil
+}
+
+// snapshotProblems returns the problems with restoring from the snapshot with the given identifier: it must exist
+// and belong to the org
+func (s *server) snapshotProblems(c buffalo.Context, client *rdsapi.Client, field, id string) ([]string, error) {
+	tags, err := client.SnapshotTags(c, id)
+	if err != nil {
+		if aerr, ok := err.(apierror.Error); ok {
+			if aerr.Code == apierror.ErrNotFound {
+				return []string{fmt.Sprintf("%s.SnapshotIdentifier: %s", field, aerr.Message)}, nil
+			}
+			return nil, err
+		}
+		return nil, ErrCode("failed to get snapshot tags", err)
+	}
+
+	for _, t := range tags {
+		if !s.ownedByOrg(t) {
+			return []string{fmt.Sprintf("%s.SnapshotIdentifier: snapshot %s doesn't belong to org %s", field, id, s.org)}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// subnetGroupOrDefault returns the given subnet group name, or the default subnet group of the client
+func subnetGroupOrDefault(client *rdsapi.Client, name *string) string {
+	if name != nil {
+		return aws.StringValue(name)
+	}
+	return client.DefaultSubnetGroup
+}
@@ -0,0 +1,82 @@
+package actions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestDatabaseCreateProblems(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *DatabaseCreateRequest
+		want []string
+	}{
+		{
+			name: "valid instance",
+			req: &DatabaseCreateRequest{
+				Instance: &CreateDBInstanceInput{
+					DBInstanceIdentifier: aws.String("mydb"),
+					DBInstanceClass:      aws.String("db.t3.micro"),
+					Engine:               aws.String("postgres"),
+					MasterUsername:       aws.String("admin"),
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "invalid instance",
+			req: &DatabaseCreateRequest{
+				Instance: &CreateDBInstanceInput{
+					DBInstanceIdentifier: aws.String("my_db"),
+				},
+			},
+			want: []string{
+				"Instance.DBInstanceIdentifier: identifier my_db must start with a letter and only contain letters, digits and hyphens, without two consecutive hyphens or a trailing hyphen",
+				"Instance.DBInstanceClass is required",
+				"Instance.Engine is required",
+				"Instance.MasterUsername is required",
+			},
+		},
+		{
+			name: "restored instance",
+			req: &DatabaseCreateRequest{
+				Instance: &CreateDBInstanceInput{
+					DBInstanceIdentifier: aws.String("mydb"),
+					DBInstanceClass:      aws.String("db.t3.micro"),
+					SnapshotIdentifier:   aws.String("mysnap"),
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "cluster with member",
+			req: &DatabaseCreateRequest{
+				Cluster: &CreateDBClusterInput{
+					DBClusterIdentifier: aws.String("mycluster"),
+					Engine:              aws.String("aurora-postgresql"),
+				},
+				Instance: &CreateDBInstanceInput{
+					DBClusterIdentifier:  aws.String("othercluster"),
+					DBInstanceIdentifier: aws.String("mycluster-1-"),
+					DBInstanceClass:      aws.String("db.r6g.large"),
+					Engine:               aws.String("aurora-postgresql"),
+				},
+			},
+			want: []string{
+				"Cluster.MasterUsername is required",
+				"Instance.DBInstanceIdentifier: identifier mycluster-1- must start with a letter and only contain letters, digits and hyphens, without two consecutive hyphens or a trailing hyphen",
+				"Instance.DBClusterIdentifier othercluster doesn't match Cluster.DBClusterIdentifier mycluster",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := databaseCreateProblems(tt.req); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -13,7 +13,7 @@ import (
 // Config is representation of the configuration data
 type Config struct {
 	Account       Account
-	AccountsMap   map[string]string
+	AccountsMap   map[string]MappedAccount
 	DefaultConfig CommonConfig
 	Token         string
 	Org           string
@@ -29,10 +29,36 @@ type Account struct {
 	Role       string
 }
 
+// MappedAccount is the account number and default region of an account name in AccountsMap.  It can also be given
+// as just the account number, in which case the region of the API account is used.
+type MappedAccount struct {
+	Id     string
+	Region string
+}
+
+// UnmarshalJSON decodes a mapped account from either an object or an account number string
+func (m *MappedAccount) UnmarshalJSON(b []byte) error {
+	var id string
+	if err := json.Unmarshal(b, &id); err == nil {
+		*m = MappedAccount{Id: id}
+		return nil
+	}
+
+	type mappedAccount MappedAccount
+	var account mappedAccount
+	if err := json.Unmarshal(b, &account); err != nil {
+		return err
+	}
+
+	*m = MappedAccount(account)
+	return nil
+}
+
 type CommonConfig struct {
 	DefaultSubnetGroup                 string
 	DefaultDBParameterGroupName        map[string]string
 	DefaultDBClusterParameterGroupName map[string]string
+	DefaultKmsKeyId                    map[string]string
 }
 
 // LoadConfig loads the JSON configuration from the specified filename and returns a Config struct
@@ -0,0 +1,33 @@
+package common
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadConfigAccountsMap(t *testing.T) {
+	config, err := readConfig(strings.NewReader(`{
+		"accountsMap": {
+			"spinup": "0123456789",
+			"dr": {"id": "9876543210", "region": "us-west-2"}
+		},
+		"org": "localdev",
+		"token": "TOKEN"
+	}`))
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	expected := map[string]MappedAccount{
+		"spinup": {Id: "0123456789"},
+		"dr":     {Id: "9876543210", Region: "us-west-2"},
+	}
+	if !reflect.DeepEqual(config.AccountsMap, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config.AccountsMap)
+	}
+
+	if _, err := readConfig(strings.NewReader(`{"accountsMap": {"spinup": 123}}`)); err == nil {
+		t.Error("expected error for invalid account, got nil")
+	}
+}
@@ -0,0 +1,104 @@
+package kms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/YaleSpinup/apierror"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	log "github.com/sirupsen/logrus"
+)
+
+type KMS struct {
+	session *session.Session
+	Service kmsiface.KMSAPI
+}
+
+type KMSOption func(*KMS)
+
+func New(opts ...KMSOption) KMS {
+	k := KMS{}
+
+	for _, opt := range opts {
+		opt(&k)
+	}
+
+	if k.session != nil {
+		k.Service = kms.New(k.session)
+	}
+
+	return k
+}
+
+func WithSession(sess *session.Session) KMSOption {
+	return func(k *KMS) {
+		log.Debug("using aws session")
+		k.session = sess
+	}
+}
+
+// DescribeKey returns the metadata of the KMS key with the given id, ARN, alias name or alias ARN
+func (k *KMS) DescribeKey(ctx context.Context, id string) (*kms.KeyMetadata, error) {
+	if id == "" {
+		return nil, apierror.New(apierror.ErrBadRequest, "invalid input", nil)
+	}
+
+	log.Infof("describing kms key %s", id)
+
+	out, err := k.Service.DescribeKeyWithContext(ctx, &kms.DescribeKeyInput{
+		KeyId: aws.String(id),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == kms.ErrCodeNotFoundException {
+			return nil, apierror.New(apierror.ErrNotFound, fmt.Sprintf("kms key %s not found", id), err)
+		}
+		return nil, err
+	}
+
+	return out.KeyMetadata, nil
+}
+
+// EnsureEncryptionKey returns the metadata of the KMS key with the given id, ARN, alias name or alias ARN after
+// checking that it's an enabled symmetric encryption key, which is the only kind of key RDS can encrypt with
+func (k *KMS) EnsureEncryptionKey(ctx context.Context, id string) (*kms.KeyMetadata, error) {
+	key, err := k.DescribeKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if state := aws.StringValue(key.KeyState); state != kms.KeyStateEnabled {
+		msg := fmt.Sprintf("kms key %s can't be used, its state is %s", id, state)
+		return nil, apierror.New(apierror.ErrBadRequest, msg, nil)
+	}
+
+	if aws.StringValue(key.KeyUsage) != kms.KeyUsageTypeEncryptDecrypt || aws.StringValue(key.KeySpec) != kms.KeySpecSymmetricDefault {
+		msg := fmt.Sprintf("kms key %s can't be used, it's not a symmetric encryption key", id)
+		return nil, apierror.New(apierror.ErrBadRequest, msg, nil)
+	}
+
+	return key, nil
+}
+
+// ListKeyAliases returns the alias names of the KMS key with the given id or ARN
+func (k *KMS) ListKeyAliases(ctx context.Context, id string) ([]string, error) {
+	if id == "" {
+		return nil, apierror.New(apierror.ErrBadRequest, "invalid input", nil)
+	}
+
+	aliases := []string{}
+	if err := k.Service.ListAliasesPagesWithContext(ctx, &kms.ListAliasesInput{KeyId: aws.String(id)},
+		func(out *kms.ListAliasesOutput, lastPage bool) bool {
+			for _, a := range out.Aliases {
+				aliases = append(aliases, aws.StringValue(a.AliasName))
+			}
+			return true
+		}); err != nil {
+		return nil, err
+	}
+
+	return aliases, nil
+}
@@ -0,0 +1,133 @@
+package kms
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/YaleSpinup/apierror"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+// mockKMSClient is a fake kms client with the given keys, keyed by id and alias
+type mockKMSClient struct {
+	kmsiface.KMSAPI
+	keys    map[string]*kms.KeyMetadata
+	aliases map[string][]string
+}
+
+func (m *mockKMSClient) DescribeKeyWithContext(_ aws.Context, input *kms.DescribeKeyInput, _ ...request.Option) (*kms.DescribeKeyOutput, error) {
+	key, ok := m.keys[aws.StringValue(input.KeyId)]
+	if !ok {
+		return nil, awserr.New(kms.ErrCodeNotFoundException, "not found", nil)
+	}
+	return &kms.DescribeKeyOutput{KeyMetadata: key}, nil
+}
+
+func (m *mockKMSClient) ListAliasesPagesWithContext(_ aws.Context, input *kms.ListAliasesInput, fn func(*kms.ListAliasesOutput, bool) bool, _ ...request.Option) error {
+	aliases := m.aliases[aws.StringValue(input.KeyId)]
+	for i, a := range aliases {
+		if !fn(&kms.ListAliasesOutput{Aliases: []*kms.AliasListEntry{{AliasName: aws.String(a)}}}, i == len(aliases)-1) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func newMockKMS() KMS {
+	key := func(id, state, usage, spec string) *kms.KeyMetadata {
+		return &kms.KeyMetadata{
+			Arn:      aws.String("arn:aws:kms:us-east-1:0123456789:key/" + id),
+			KeyId:    aws.String(id),
+			KeySpec:  aws.String(spec),
+			KeyState: aws.String(state),
+			KeyUsage: aws.String(usage),
+		}
+	}
+
+	enabled := key("enabled", kms.KeyStateEnabled, kms.KeyUsageTypeEncryptDecrypt, kms.KeySpecSymmetricDefault)
+
+	return KMS{
+		Service: &mockKMSClient{
+			keys: map[string]*kms.KeyMetadata{
+				"enabled":    enabled,
+				"alias/rds":  enabled,
+				"disabled":   key("disabled", kms.KeyStateDisabled, kms.KeyUsageTypeEncryptDecrypt, kms.KeySpecSymmetricDefault),
+				"deleting":   key("deleting", kms.KeyStatePendingDeletion, kms.KeyUsageTypeEncryptDecrypt, kms.KeySpecSymmetricDefault),
+				"signing":    key("signing", kms.KeyStateEnabled, kms.KeyUsageTypeSignVerify, kms.KeySpecRsa2048),
+				"asymmetric": key("asymmetric", kms.KeyStateEnabled, kms.KeyUsageTypeEncryptDecrypt, kms.KeySpecRsa4096),
+			},
+			aliases: map[string][]string{
+				"enabled": {"alias/rds", "alias/databases"},
+			},
+		},
+	}
+}
+
+func TestNewSession(t *testing.T) {
+	client := New()
+	to := reflect.TypeOf(client).String()
+	if to != "kms.KMS" {
+		t.Errorf("expected type to be 'kms.KMS', got %s", to)
+	}
+}
+
+func TestEnsureEncryptionKey(t *testing.T) {
+	k := newMockKMS()
+
+	tests := []struct {
+		id      string
+		wantErr string
+	}{
+		{id: "enabled"},
+		{id: "alias/rds"},
+		{id: "disabled", wantErr: apierror.ErrBadRequest},
+		{id: "deleting", wantErr: apierror.ErrBadRequest},
+		{id: "signing", wantErr: apierror.ErrBadRequest},
+		{id: "asymmetric", wantErr: apierror.ErrBadRequest},
+		{id: "missing", wantErr: apierror.ErrNotFound},
+		{id: "", wantErr: apierror.ErrBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			key, err := k.EnsureEncryptionKey(context.TODO(), tt.id)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected nil error, got %s", err)
+				}
+				if aws.StringValue(key.KeyId) != "enabled" {
+					t.Errorf("expected key enabled, got %s", aws.StringValue(key.KeyId))
+				}
+				return
+			}
+
+			aerr, ok := err.(apierror.Error)
+			if !ok || aerr.Code != tt.wantErr {
+				t.Errorf("expected %s error, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestListKeyAliases(t *testing.T) {
+	k := newMockKMS()
+
+	got, err := k.ListKeyAliases(context.TODO(), "enabled")
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	expected := []string{"alias/rds", "alias/databases"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if _, err := k.ListKeyAliases(context.TODO(), ""); err == nil {
+		t.Error("expected error for empty key id, got nil")
+	}
+}
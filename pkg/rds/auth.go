@@ -0,0 +1,36 @@
+package rds
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/rds/rdsutils"
+)
+
+// AuthTokenDuration is how long IAM database authentication tokens are valid
+const AuthTokenDuration = 15 * time.Minute
+
+// BuildAuthToken builds an IAM database authentication token for the given database user to connect to the
+// database at the given address and port.  The token is signed locally with the given credentials, which
+// need to allow rds-db:connect, and it's only valid as long as the credentials are.
+func BuildAuthToken(address string, port int64, region, dbUser string, creds *credentials.Credentials) (string, error) {
+	if address == "" || port == 0 {
+		return "", errors.New("database endpoint address and port cannot be empty")
+	}
+
+	if region == "" {
+		return "", errors.New("region cannot be empty")
+	}
+
+	if dbUser == "" {
+		return "", errors.New("database user cannot be empty")
+	}
+
+	if creds == nil {
+		return "", errors.New("credentials cannot be nil")
+	}
+
+	return rdsutils.BuildAuthToken(fmt.Sprintf("%s:%d", address, port), region, dbUser, creds)
+}
@@ -0,0 +1,68 @@
+package rds
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+func TestBuildAuthToken(t *testing.T) {
+	creds := credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", "session")
+
+	token, err := BuildAuthToken("mydb.abcdefg.us-east-1.rds.amazonaws.com", 5432, "us-east-1", "myuser", creds)
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	if !strings.HasPrefix(token, "mydb.abcdefg.us-east-1.rds.amazonaws.com:5432?") {
+		t.Errorf("expected token for endpoint mydb.abcdefg.us-east-1.rds.amazonaws.com:5432, got %s", token)
+	}
+
+	u, err := url.Parse("https://" + token)
+	if err != nil {
+		t.Fatalf("expected token to be a valid url, got %s", err)
+	}
+
+	q := u.Query()
+	expected := map[string]string{
+		"Action":               "connect",
+		"DBUser":               "myuser",
+		"X-Amz-Expires":        "900",
+		"X-Amz-Security-Token": "session",
+	}
+	for k, v := range expected {
+		if q.Get(k) != v {
+			t.Errorf("expected %s to be %s, got %s", k, v, q.Get(k))
+		}
+	}
+
+	if !strings.HasPrefix(q.Get("X-Amz-Credential"), "AKIDEXAMPLE/") || !strings.Contains(q.Get("X-Amz-Credential"), "/us-east-1/rds-db/") {
+		t.Errorf("expected credential scope for rds-db in us-east-1, got %s", q.Get("X-Amz-Credential"))
+	}
+
+	if q.Get("X-Amz-Signature") == "" {
+		t.Error("expected signed token, got no signature")
+	}
+
+	tests := []struct {
+		address string
+		port    int64
+		region  string
+		user    string
+		creds   *credentials.Credentials
+	}{
+		{"", 5432, "us-east-1", "myuser", creds},
+		{"mydb", 0, "us-east-1", "myuser", creds},
+		{"mydb", 5432, "", "myuser", creds},
+		{"mydb", 5432, "us-east-1", "", creds},
+		{"mydb", 5432, "us-east-1", "myuser", nil},
+	}
+
+	for _, test := range tests {
+		if _, err := BuildAuthToken(test.address, test.port, test.region, test.user, test.creds); err == nil {
+			t.Errorf("expected error for %+v, got nil", test)
+		}
+	}
+}
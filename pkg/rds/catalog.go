@@ -0,0 +1,56 @@
+package rds
+
+import (
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// ListEngineVersions returns every engine version available in the region, including deprecated versions,
+// optionally only for the given engine
+func (r *Client) ListEngineVersions(ctx aws.Context, engine string) ([]*rds.DBEngineVersion, error) {
+	input := &rds.DescribeDBEngineVersionsInput{
+		IncludeAll: aws.Bool(true),
+	}
+	if engine != "" {
+		input.Engine = aws.String(engine)
+	}
+
+	versions := []*rds.DBEngineVersion{}
+	if err := r.Service.DescribeDBEngineVersionsPagesWithContext(ctx, input,
+		func(out *rds.DescribeDBEngineVersionsOutput, lastPage bool) bool {
+			versions = append(versions, out.DBEngineVersions...)
+			return true
+		}); err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// ListOrderableInstanceOptions returns the orderable database instance options for the given engine, optionally
+// only for the given engine version
+func (r *Client) ListOrderableInstanceOptions(ctx aws.Context, engine, version string) ([]*rds.OrderableDBInstanceOption, error) {
+	if engine == "" {
+		return nil, errors.New("engine cannot be empty")
+	}
+
+	input := &rds.DescribeOrderableDBInstanceOptionsInput{
+		Engine: aws.String(engine),
+	}
+	if version != "" {
+		input.EngineVersion = aws.String(version)
+	}
+
+	options := []*rds.OrderableDBInstanceOption{}
+	if err := r.Service.DescribeOrderableDBInstanceOptionsPagesWithContext(ctx, input,
+		func(out *rds.DescribeOrderableDBInstanceOptionsOutput, lastPage bool) bool {
+			options = append(options, out.OrderableDBInstanceOptions...)
+			return true
+		}); err != nil {
+		return nil, err
+	}
+
+	return options, nil
+}
@@ -0,0 +1,127 @@
+package rds
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+// mockCatalogClient is a fake rds client with engine versions and orderable instance options,
+// returned one per page
+type mockCatalogClient struct {
+	rdsiface.RDSAPI
+	versions []*rds.DBEngineVersion
+	options  []*rds.OrderableDBInstanceOption
+}
+
+func (m *mockCatalogClient) DescribeDBEngineVersionsPagesWithContext(_ aws.Context, input *rds.DescribeDBEngineVersionsInput, fn func(*rds.DescribeDBEngineVersionsOutput, bool) bool, _ ...request.Option) error {
+	for i, v := range m.versions {
+		if input.Engine != nil && aws.StringValue(input.Engine) != aws.StringValue(v.Engine) {
+			continue
+		}
+		if aws.StringValue(v.Status) == "deprecated" && !aws.BoolValue(input.IncludeAll) {
+			continue
+		}
+		if !fn(&rds.DescribeDBEngineVersionsOutput{DBEngineVersions: []*rds.DBEngineVersion{v}}, i == len(m.versions)-1) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *mockCatalogClient) DescribeOrderableDBInstanceOptionsPagesWithContext(_ aws.Context, input *rds.DescribeOrderableDBInstanceOptionsInput, fn func(*rds.DescribeOrderableDBInstanceOptionsOutput, bool) bool, _ ...request.Option) error {
+	for i, o := range m.options {
+		if aws.StringValue(input.Engine) != aws.StringValue(o.Engine) {
+			continue
+		}
+		if input.EngineVersion != nil && aws.StringValue(input.EngineVersion) != aws.StringValue(o.EngineVersion) {
+			continue
+		}
+		if !fn(&rds.DescribeOrderableDBInstanceOptionsOutput{OrderableDBInstanceOptions: []*rds.OrderableDBInstanceOption{o}}, i == len(m.options)-1) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func newMockCatalogClient() Client {
+	version := func(engine, version, status string) *rds.DBEngineVersion {
+		return &rds.DBEngineVersion{Engine: aws.String(engine), EngineVersion: aws.String(version), Status: aws.String(status)}
+	}
+	option := func(engine, version, class string) *rds.OrderableDBInstanceOption {
+		return &rds.OrderableDBInstanceOption{Engine: aws.String(engine), EngineVersion: aws.String(version), DBInstanceClass: aws.String(class)}
+	}
+
+	return Client{
+		Service: &mockCatalogClient{
+			versions: []*rds.DBEngineVersion{
+				version("postgres", "11.22", "deprecated"),
+				version("postgres", "16.3", "available"),
+				version("mysql", "8.0.36", "available"),
+			},
+			options: []*rds.OrderableDBInstanceOption{
+				option("postgres", "16.3", "db.t3.micro"),
+				option("postgres", "16.3", "db.r6g.large"),
+				option("postgres", "15.7", "db.t3.micro"),
+				option("mysql", "8.0.36", "db.t3.micro"),
+			},
+		},
+	}
+}
+
+func TestClient_ListEngineVersions(t *testing.T) {
+	mc := newMockCatalogClient()
+
+	tests := []struct {
+		engine string
+		want   int
+	}{
+		{engine: "", want: 3},
+		{engine: "postgres", want: 2},
+		{engine: "oracle-ee", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.engine, func(t *testing.T) {
+			got, err := mc.ListEngineVersions(ctx, tt.engine)
+			if err != nil {
+				t.Fatalf("expected nil error, got %s", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("expected %d versions, got %d", tt.want, len(got))
+			}
+		})
+	}
+}
+
+func TestClient_ListOrderableInstanceOptions(t *testing.T) {
+	mc := newMockCatalogClient()
+
+	tests := []struct {
+		name    string
+		engine  string
+		version string
+		want    int
+		wantErr bool
+	}{
+		{name: "all versions", engine: "postgres", want: 3},
+		{name: "one version", engine: "postgres", version: "16.3", want: 2},
+		{name: "unknown version", engine: "postgres", version: "9.6", want: 0},
+		{name: "empty engine", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mc.ListOrderableInstanceOptions(ctx, tt.engine, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListOrderableInstanceOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("expected %d options, got %d", tt.want, len(got))
+			}
+		})
+	}
+}
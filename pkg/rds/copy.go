@@ -0,0 +1,114 @@
+package rds
+
+import (
+	"errors"
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// SnapshotCopyInput is the input for copying a database instance or cluster snapshot.  The source is given by
+// identifier when it's in the same account and region as the copy, otherwise by ARN.  SourceRegion is only set
+// for a copy from another region, the SDK then generates the pre-signed URL RDS needs to read the source.
+type SnapshotCopyInput struct {
+	SourceSnapshotIdentifier string
+	SourceRegion             string
+	TargetSnapshotIdentifier string
+	KmsKeyId                 string
+	Tags                     []*rds.Tag
+}
+
+// CopySnapshot copies the database instance snapshot in the given input
+func (r *Client) CopySnapshot(ctx aws.Context, input *SnapshotCopyInput) (*rds.DBSnapshot, error) {
+	if input == nil || input.SourceSnapshotIdentifier == "" || input.TargetSnapshotIdentifier == "" {
+		return nil, errors.New("source and target snapshot identifiers cannot be empty")
+	}
+
+	log.Printf("copying database snapshot %s to %s", input.SourceSnapshotIdentifier, input.TargetSnapshotIdentifier)
+
+	copyInput := &rds.CopyDBSnapshotInput{
+		SourceDBSnapshotIdentifier: aws.String(input.SourceSnapshotIdentifier),
+		TargetDBSnapshotIdentifier: aws.String(input.TargetSnapshotIdentifier),
+		Tags:                       input.Tags,
+	}
+	if input.KmsKeyId != "" {
+		copyInput.KmsKeyId = aws.String(input.KmsKeyId)
+	}
+	if input.SourceRegion != "" {
+		copyInput.SourceRegion = aws.String(input.SourceRegion)
+	}
+
+	out, err := r.Service.CopyDBSnapshotWithContext(ctx, copyInput)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.DBSnapshot, nil
+}
+
+// CopyClusterSnapshot copies the database cluster snapshot in the given input
+func (r *Client) CopyClusterSnapshot(ctx aws.Context, input *SnapshotCopyInput) (*rds.DBClusterSnapshot, error) {
+	if input == nil || input.SourceSnapshotIdentifier == "" || input.TargetSnapshotIdentifier == "" {
+		return nil, errors.New("source and target snapshot identifiers cannot be empty")
+	}
+
+	log.Printf("copying database cluster snapshot %s to %s", input.SourceSnapshotIdentifier, input.TargetSnapshotIdentifier)
+
+	copyInput := &rds.CopyDBClusterSnapshotInput{
+		SourceDBClusterSnapshotIdentifier: aws.String(input.SourceSnapshotIdentifier),
+		TargetDBClusterSnapshotIdentifier: aws.String(input.TargetSnapshotIdentifier),
+		Tags:                              input.Tags,
+	}
+	if input.KmsKeyId != "" {
+		copyInput.KmsKeyId = aws.String(input.KmsKeyId)
+	}
+	if input.SourceRegion != "" {
+		copyInput.SourceRegion = aws.String(input.SourceRegion)
+	}
+
+	out, err := r.Service.CopyDBClusterSnapshotWithContext(ctx, copyInput)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.DBClusterSnapshot, nil
+}
+
+// ShareSnapshot allows the given account to restore or copy the manual database instance or cluster snapshot
+func (r *Client) ShareSnapshot(ctx aws.Context, id string, cluster bool, account string) error {
+	log.Printf("sharing database snapshot %s with account %s", id, account)
+	return r.modifySnapshotRestoreAttribute(ctx, id, cluster, []*string{aws.String(account)}, nil)
+}
+
+// UnshareSnapshot stops sharing the manual database instance or cluster snapshot with the given account
+func (r *Client) UnshareSnapshot(ctx aws.Context, id string, cluster bool, account string) error {
+	log.Printf("unsharing database snapshot %s with account %s", id, account)
+	return r.modifySnapshotRestoreAttribute(ctx, id, cluster, nil, []*string{aws.String(account)})
+}
+
+// modifySnapshotRestoreAttribute adds and removes accounts from the restore attribute of a snapshot,
+// which lists the accounts the snapshot is shared with
+func (r *Client) modifySnapshotRestoreAttribute(ctx aws.Context, id string, cluster bool, add, remove []*string) error {
+	if id == "" {
+		return errors.New("snapshot identifier cannot be empty")
+	}
+
+	if cluster {
+		_, err := r.Service.ModifyDBClusterSnapshotAttributeWithContext(ctx, &rds.ModifyDBClusterSnapshotAttributeInput{
+			AttributeName:               aws.String("restore"),
+			DBClusterSnapshotIdentifier: aws.String(id),
+			ValuesToAdd:                 add,
+			ValuesToRemove:              remove,
+		})
+		return err
+	}
+
+	_, err := r.Service.ModifyDBSnapshotAttributeWithContext(ctx, &rds.ModifyDBSnapshotAttributeInput{
+		AttributeName:        aws.String("restore"),
+		DBSnapshotIdentifier: aws.String(id),
+		ValuesToAdd:          add,
+		ValuesToRemove:       remove,
+	})
+	return err
+}
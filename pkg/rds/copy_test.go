@@ -0,0 +1,106 @@
+package rds
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+// mockCopyClient records the copy and snapshot attribute calls
+type mockCopyClient struct {
+	rdsiface.RDSAPI
+	copyInput        *rds.CopyDBSnapshotInput
+	clusterCopyInput *rds.CopyDBClusterSnapshotInput
+	attributeInput   *rds.ModifyDBSnapshotAttributeInput
+	clusterAttrInput *rds.ModifyDBClusterSnapshotAttributeInput
+}
+
+func (m *mockCopyClient) CopyDBSnapshotWithContext(_ aws.Context, input *rds.CopyDBSnapshotInput, _ ...request.Option) (*rds.CopyDBSnapshotOutput, error) {
+	m.copyInput = input
+	return &rds.CopyDBSnapshotOutput{DBSnapshot: &rds.DBSnapshot{DBSnapshotIdentifier: input.TargetDBSnapshotIdentifier}}, nil
+}
+
+func (m *mockCopyClient) CopyDBClusterSnapshotWithContext(_ aws.Context, input *rds.CopyDBClusterSnapshotInput, _ ...request.Option) (*rds.CopyDBClusterSnapshotOutput, error) {
+	m.clusterCopyInput = input
+	return &rds.CopyDBClusterSnapshotOutput{DBClusterSnapshot: &rds.DBClusterSnapshot{DBClusterSnapshotIdentifier: input.TargetDBClusterSnapshotIdentifier}}, nil
+}
+
+func (m *mockCopyClient) ModifyDBSnapshotAttributeWithContext(_ aws.Context, input *rds.ModifyDBSnapshotAttributeInput, _ ...request.Option) (*rds.ModifyDBSnapshotAttributeOutput, error) {
+	m.attributeInput = input
+	return &rds.ModifyDBSnapshotAttributeOutput{}, nil
+}
+
+func (m *mockCopyClient) ModifyDBClusterSnapshotAttributeWithContext(_ aws.Context, input *rds.ModifyDBClusterSnapshotAttributeInput, _ ...request.Option) (*rds.ModifyDBClusterSnapshotAttributeOutput, error) {
+	m.clusterAttrInput = input
+	return &rds.ModifyDBClusterSnapshotAttributeOutput{}, nil
+}
+
+func TestCopySnapshot(t *testing.T) {
+	m := &mockCopyClient{}
+	r := &Client{Service: m}
+
+	tags := []*rds.Tag{{Key: aws.String("spinup:org"), Value: aws.String("localdev")}}
+	got, err := r.CopySnapshot(ctx, &SnapshotCopyInput{
+		SourceSnapshotIdentifier: "arn:aws:rds:us-east-1:0123456789:snapshot:mydb-snap",
+		SourceRegion:             "us-east-1",
+		TargetSnapshotIdentifier: "mydb-dr",
+		KmsKeyId:                 "arn:aws:kms:us-west-2:0123456789:key/1234",
+		Tags:                     tags,
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if aws.StringValue(got.DBSnapshotIdentifier) != "mydb-dr" {
+		t.Errorf("unexpected copy %+v", got)
+	}
+
+	expected := &rds.CopyDBSnapshotInput{
+		KmsKeyId:                   aws.String("arn:aws:kms:us-west-2:0123456789:key/1234"),
+		SourceDBSnapshotIdentifier: aws.String("arn:aws:rds:us-east-1:0123456789:snapshot:mydb-snap"),
+		SourceRegion:               aws.String("us-east-1"),
+		Tags:                       tags,
+		TargetDBSnapshotIdentifier: aws.String("mydb-dr"),
+	}
+	if !reflect.DeepEqual(m.copyInput, expected) {
+		t.Errorf("expected %+v, got %+v", expected, m.copyInput)
+	}
+
+	// a copy in the same region has no source region and keeps the key of the source
+	if _, err := r.CopyClusterSnapshot(ctx, &SnapshotCopyInput{SourceSnapshotIdentifier: "mycluster-snap", TargetSnapshotIdentifier: "mycluster-copy"}); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if m.clusterCopyInput.SourceRegion != nil || m.clusterCopyInput.KmsKeyId != nil {
+		t.Errorf("expected no source region and key, got %+v", m.clusterCopyInput)
+	}
+
+	if _, err := r.CopySnapshot(ctx, &SnapshotCopyInput{SourceSnapshotIdentifier: "mydb-snap"}); err == nil {
+		t.Error("expected error for empty target identifier, got nil")
+	}
+}
+
+func TestShareSnapshot(t *testing.T) {
+	m := &mockCopyClient{}
+	r := &Client{Service: m}
+
+	if err := r.ShareSnapshot(ctx, "mydb-snap", false, "9876543210"); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if !reflect.DeepEqual(m.attributeInput.ValuesToAdd, []*string{aws.String("9876543210")}) || m.attributeInput.ValuesToRemove != nil {
+		t.Errorf("unexpected share input %+v", m.attributeInput)
+	}
+
+	if err := r.UnshareSnapshot(ctx, "mycluster-snap", true, "9876543210"); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if aws.StringValue(m.clusterAttrInput.AttributeName) != "restore" || !reflect.DeepEqual(m.clusterAttrInput.ValuesToRemove, []*string{aws.String("9876543210")}) {
+		t.Errorf("unexpected unshare input %+v", m.clusterAttrInput)
+	}
+
+	if err := r.ShareSnapshot(ctx, "", false, "9876543210"); err == nil {
+		t.Error("expected error for empty snapshot identifier, got nil")
+	}
+}
@@ -0,0 +1,44 @@
+package rds
+
+import (
+	"errors"
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// SetMasterUserPassword immediately sets the master user password of an RDS database cluster or instance.
+// Like StopDatabase, it first looks for a cluster with the given identifier and falls back to an instance.
+func (r *Client) SetMasterUserPassword(ctx aws.Context, id, password string) error {
+	if id == "" {
+		return errors.New("database identifier cannot be empty")
+	}
+
+	if password == "" {
+		return errors.New("password cannot be empty")
+	}
+
+	cluster, err := r.describeCluster(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Setting master user password of database with identifier %s", id)
+
+	if cluster != nil {
+		_, err = r.Service.ModifyDBClusterWithContext(ctx, &rds.ModifyDBClusterInput{
+			ApplyImmediately:    aws.Bool(true),
+			DBClusterIdentifier: aws.String(id),
+			MasterUserPassword:  aws.String(password),
+		})
+		return err
+	}
+
+	_, err = r.Service.ModifyDBInstanceWithContext(ctx, &rds.ModifyDBInstanceInput{
+		ApplyImmediately:     aws.Bool(true),
+		DBInstanceIdentifier: aws.String(id),
+		MasterUserPassword:   aws.String(password),
+	})
+	return err
+}
@@ -0,0 +1,47 @@
+package rds
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+func (m *mockDescribeClient) ModifyDBClusterWithContext(_ aws.Context, input *rds.ModifyDBClusterInput, _ ...request.Option) (*rds.ModifyDBClusterOutput, error) {
+	m.modified = append(m.modified, "cluster/"+aws.StringValue(input.DBClusterIdentifier))
+	return &rds.ModifyDBClusterOutput{}, nil
+}
+
+func (m *mockDescribeClient) ModifyDBInstanceWithContext(_ aws.Context, input *rds.ModifyDBInstanceInput, _ ...request.Option) (*rds.ModifyDBInstanceOutput, error) {
+	m.modified = append(m.modified, "instance/"+aws.StringValue(input.DBInstanceIdentifier))
+	return &rds.ModifyDBInstanceOutput{}, nil
+}
+
+func TestClient_SetMasterUserPassword(t *testing.T) {
+	mock := &mockDescribeClient{
+		clusters: map[string]*rds.DBCluster{
+			"cluster": {DBClusterIdentifier: aws.String("cluster")},
+		},
+	}
+	mc := Client{Service: mock}
+
+	if err := mc.SetMasterUserPassword(ctx, "cluster", "secret"); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if err := mc.SetMasterUserPassword(ctx, "instance", "secret"); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	expected := []string{"cluster/cluster", "instance/instance"}
+	if len(mock.modified) != 2 || mock.modified[0] != expected[0] || mock.modified[1] != expected[1] {
+		t.Errorf("expected %v to be modified, got %v", expected, mock.modified)
+	}
+
+	if err := mc.SetMasterUserPassword(ctx, "", "secret"); err == nil {
+		t.Error("expected error for empty identifier, got nil")
+	}
+	if err := mc.SetMasterUserPassword(ctx, "cluster", ""); err == nil {
+		t.Error("expected error for empty password, got nil")
+	}
+}
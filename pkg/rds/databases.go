@@ -2,8 +2,12 @@ package rds
 
 import (
 	"errors"
+	"fmt"
 	"log"
+	"sort"
+	"strings"
 
+	"github.com/YaleSpinup/apierror"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/rds"
@@ -68,3 +72,181 @@ func (r *Client) StartDatabase(ctx buffalo.Context, id string) error {
 
 	return err
 }
+
+// DatabaseFilter is used to filter the list of database instances and clusters
+// Empty fields are ignored.  EngineVersion matches the exact version or any version
+// with the given prefix, e.g. "14" matches "14.5".  All given Tags must match.
+type DatabaseFilter struct {
+	Engine        string
+	EngineVersion string
+	Status        string
+	Tags          map[string]string
+}
+
+// ListDBInstances returns all database instances matching the given filter, sorted by identifier.
+// It pages through all results of DescribeDBInstances.
+func (r *Client) ListDBInstances(ctx aws.Context, filter *DatabaseFilter) ([]*rds.DBInstance, error) {
+	input := &rds.DescribeDBInstancesInput{
+		MaxRecords: aws.Int64(100),
+	}
+
+	if filter != nil && filter.Engine != "" {
+		input.Filters = []*rds.Filter{
+			{
+				Name:   aws.String("engine"),
+				Values: aws.StringSlice([]string{filter.Engine}),
+			},
+		}
+	}
+
+	instances := []*rds.DBInstance{}
+	if err := r.Service.DescribeDBInstancesPagesWithContext(ctx, input, func(out *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+		for _, i := range out.DBInstances {
+			if filter.matches(i.Engine, i.EngineVersion, i.DBInstanceStatus, i.TagList) {
+				instances = append(instances, i)
+			}
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return aws.StringValue(instances[i].DBInstanceIdentifier) < aws.StringValue(instances[j].DBInstanceIdentifier)
+	})
+
+	log.Printf("found %d matching database instances", len(instances))
+
+	return instances, nil
+}
+
+// ListDBClusters returns all database clusters matching the given filter, sorted by identifier.
+// It pages through all results of DescribeDBClusters.
+func (r *Client) ListDBClusters(ctx aws.Context, filter *DatabaseFilter) ([]*rds.DBCluster, error) {
+	input := &rds.DescribeDBClustersInput{
+		MaxRecords: aws.Int64(100),
+	}
+
+	if filter != nil && filter.Engine != "" {
+		input.Filters = []*rds.Filter{
+			{
+				Name:   aws.String("engine"),
+				Values: aws.StringSlice([]string{filter.Engine}),
+			},
+		}
+	}
+
+	clusters := []*rds.DBCluster{}
+	if err := r.Service.DescribeDBClustersPagesWithContext(ctx, input, func(out *rds.DescribeDBClustersOutput, lastPage bool) bool {
+		for _, c := range out.DBClusters {
+			if filter.matches(c.Engine, c.EngineVersion, c.Status, c.TagList) {
+				clusters = append(clusters, c)
+			}
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		return aws.StringValue(clusters[i].DBClusterIdentifier) < aws.StringValue(clusters[j].DBClusterIdentifier)
+	})
+
+	log.Printf("found %d matching database clusters", len(clusters))
+
+	return clusters, nil
+}
+
+// matches returns true if the given database attributes match the filter
+func (f *DatabaseFilter) matches(engine, engineVersion, status *string, tags []*rds.Tag) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.Engine != "" && aws.StringValue(engine) != f.Engine {
+		return false
+	}
+
+	if f.EngineVersion != "" {
+		v := aws.StringValue(engineVersion)
+		if v != f.EngineVersion && !strings.HasPrefix(v, f.EngineVersion+".") {
+			return false
+		}
+	}
+
+	if f.Status != "" && !strings.EqualFold(aws.StringValue(status), f.Status) {
+		return false
+	}
+
+	for key, value := range f.Tags {
+		found := false
+		for _, t := range tags {
+			if aws.StringValue(t.Key) == key && aws.StringValue(t.Value) == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// DescribeDatabase returns the database cluster or instance with the given identifier, looking for a cluster
+// first.  If the identifier is an instance belonging to a cluster, its cluster is returned along with it.
+func (r *Client) DescribeDatabase(ctx aws.Context, id string) (*rds.DBCluster, *rds.DBInstance, error) {
+	if id == "" {
+		return nil, nil, errors.New("database identifier cannot be empty")
+	}
+
+	cluster, err := r.describeCluster(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cluster != nil {
+		return cluster, nil, nil
+	}
+
+	instancesOutput, err := r.Service.DescribeDBInstancesWithContext(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(id),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == rds.ErrCodeDBInstanceNotFoundFault {
+			msg := fmt.Sprintf("database %s not found", id)
+			return nil, nil, apierror.New(apierror.ErrNotFound, msg, nil)
+		}
+		return nil, nil, err
+	}
+	if len(instancesOutput.DBInstances) != 1 {
+		return nil, nil, errors.New("unexpected number of database instances")
+	}
+	instance := instancesOutput.DBInstances[0]
+
+	if instance.DBClusterIdentifier != nil {
+		if cluster, err = r.describeCluster(ctx, aws.StringValue(instance.DBClusterIdentifier)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return cluster, instance, nil
+}
+
+// describeCluster returns the database cluster with the given identifier, or nil if it doesn't exist
+func (r *Client) describeCluster(ctx aws.Context, id string) (*rds.DBCluster, error) {
+	clustersOutput, err := r.Service.DescribeDBClustersWithContext(ctx, &rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(id),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == rds.ErrCodeDBClusterNotFoundFault {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(clustersOutput.DBClusters) != 1 {
+		return nil, errors.New("unexpected number of database clusters")
+	}
+
+	return clustersOutput.DBClusters[0], nil
+}
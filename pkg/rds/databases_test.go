@@ -1,6 +1,15 @@
 package rds
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
 
 func TestStopDatabase(t *testing.T) {
 	t.Log("TODO")
@@ -9,3 +18,153 @@ func TestStopDatabase(t *testing.T) {
 func TestStartDatabase(t *testing.T) {
 	t.Log("TODO")
 }
+
+func (m *mockRDSClient) DescribeDBInstancesPagesWithContext(_ aws.Context, input *rds.DescribeDBInstancesInput, fn func(*rds.DescribeDBInstancesOutput, bool) bool, _ ...request.Option) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	pages := []*rds.DescribeDBInstancesOutput{
+		{
+			DBInstances: []*rds.DBInstance{
+				{DBInstanceIdentifier: aws.String("db3"), Engine: aws.String("postgres"), EngineVersion: aws.String("14.5"), DBInstanceStatus: aws.String("available")},
+				{DBInstanceIdentifier: aws.String("db1"), Engine: aws.String("postgres"), EngineVersion: aws.String("10.21"), DBInstanceStatus: aws.String("stopped")},
+			},
+			Marker: aws.String("page2"),
+		},
+		{
+			DBInstances: []*rds.DBInstance{
+				{
+					DBInstanceIdentifier: aws.String("db2"),
+					Engine:               aws.String("mysql"),
+					EngineVersion:        aws.String("8.0.35"),
+					DBInstanceStatus:     aws.String("available"),
+					TagList:              []*rds.Tag{{Key: aws.String("spinup:org"), Value: aws.String("localdev")}},
+				},
+			},
+		},
+	}
+
+	for i, p := range pages {
+		if !fn(p, i == len(pages)-1) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func TestClient_ListDBInstances(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  *DatabaseFilter
+		err     error
+		want    []string
+		wantErr bool
+	}{
+		{name: "no filter", want: []string{"db1", "db2", "db3"}},
+		{name: "engine version prefix", filter: &DatabaseFilter{EngineVersion: "14"}, want: []string{"db3"}},
+		{name: "engine version is not a partial match", filter: &DatabaseFilter{EngineVersion: "1"}, want: []string{}},
+		{name: "status", filter: &DatabaseFilter{Status: "Available"}, want: []string{"db2", "db3"}},
+		{name: "engine", filter: &DatabaseFilter{Engine: "mysql"}, want: []string{"db2"}},
+		{name: "tag", filter: &DatabaseFilter{Tags: map[string]string{"spinup:org": "localdev"}}, want: []string{"db2"}},
+		{name: "tag mismatch", filter: &DatabaseFilter{Tags: map[string]string{"spinup:org": "other"}}, want: []string{}},
+		{name: "aws error", err: awserr.New("Bad Request", "boom.", nil), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Client{Service: newmockRDSClient(t, tt.err)}
+			got, err := r.ListDBInstances(ctx, tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.ListDBInstances() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			ids := []string{}
+			for _, i := range got {
+				ids = append(ids, aws.StringValue(i.DBInstanceIdentifier))
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("Client.ListDBInstances() = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+// mockDescribeClient describes the given clusters and instances
+type mockDescribeClient struct {
+	rdsiface.RDSAPI
+	clusters  map[string]*rds.DBCluster
+	instances map[string]*rds.DBInstance
+	modified  []string
+}
+
+func (m *mockDescribeClient) DescribeDBClustersWithContext(_ aws.Context, input *rds.DescribeDBClustersInput, _ ...request.Option) (*rds.DescribeDBClustersOutput, error) {
+	c, ok := m.clusters[aws.StringValue(input.DBClusterIdentifier)]
+	if !ok {
+		return nil, awserr.New(rds.ErrCodeDBClusterNotFoundFault, "not found", nil)
+	}
+	return &rds.DescribeDBClustersOutput{DBClusters: []*rds.DBCluster{c}}, nil
+}
+
+func (m *mockDescribeClient) DescribeDBInstancesWithContext(_ aws.Context, input *rds.DescribeDBInstancesInput, _ ...request.Option) (*rds.DescribeDBInstancesOutput, error) {
+	i, ok := m.instances[aws.StringValue(input.DBInstanceIdentifier)]
+	if !ok {
+		return nil, awserr.New(rds.ErrCodeDBInstanceNotFoundFault, "not found", nil)
+	}
+	return &rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{i}}, nil
+}
+
+func TestClient_DescribeDatabase(t *testing.T) {
+	mc := Client{
+		Service: &mockDescribeClient{
+			clusters: map[string]*rds.DBCluster{
+				"cluster": {DBClusterIdentifier: aws.String("cluster")},
+			},
+			instances: map[string]*rds.DBInstance{
+				"instance": {DBInstanceIdentifier: aws.String("instance")},
+				"member":   {DBInstanceIdentifier: aws.String("member"), DBClusterIdentifier: aws.String("cluster")},
+			},
+		},
+	}
+
+	tests := []struct {
+		id          string
+		wantCluster string
+		wantInst    string
+		wantErr     bool
+	}{
+		{id: "cluster", wantCluster: "cluster"},
+		{id: "instance", wantInst: "instance"},
+		{id: "member", wantCluster: "cluster", wantInst: "member"},
+		{id: "unknown", wantErr: true},
+		{id: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			cluster, instance, err := mc.DescribeDatabase(ctx, tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DescribeDatabase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			gotCluster := ""
+			if cluster != nil {
+				gotCluster = aws.StringValue(cluster.DBClusterIdentifier)
+			}
+			if gotCluster != tt.wantCluster {
+				t.Errorf("DescribeDatabase() cluster = %s, want %s", gotCluster, tt.wantCluster)
+			}
+
+			gotInst := ""
+			if instance != nil {
+				gotInst = aws.StringValue(instance.DBInstanceIdentifier)
+			}
+			if gotInst != tt.wantInst {
+				t.Errorf("DescribeDatabase() instance = %s, want %s", gotInst, tt.wantInst)
+			}
+		})
+	}
+}
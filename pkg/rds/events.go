@@ -0,0 +1,47 @@
+package rds
+
+import (
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// EventSource is an RDS resource to list the events of.  SourceType is one of the rds.SourceType values,
+// for example rds.SourceTypeDbInstance or rds.SourceTypeDbCluster.
+type EventSource struct {
+	Identifier string
+	SourceType string
+}
+
+// ListEvents returns the events between the given times for all of the given sources, merged and sorted oldest first.
+// If categories are given, only the events in those categories (backup, failover, ...) are returned.
+func (r *Client) ListEvents(ctx aws.Context, since, until time.Time, categories []string, sources ...EventSource) ([]*rds.Event, error) {
+	events := []*rds.Event{}
+	for _, s := range sources {
+		input := &rds.DescribeEventsInput{
+			SourceIdentifier: aws.String(s.Identifier),
+			SourceType:       aws.String(s.SourceType),
+			StartTime:        aws.Time(since),
+			EndTime:          aws.Time(until),
+		}
+		if len(categories) > 0 {
+			input.EventCategories = aws.StringSlice(categories)
+		}
+
+		if err := r.Service.DescribeEventsPagesWithContext(ctx, input,
+			func(out *rds.DescribeEventsOutput, lastPage bool) bool {
+				events = append(events, out.Events...)
+				return true
+			}); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return aws.TimeValue(events[i].Date).Before(aws.TimeValue(events[j].Date))
+	})
+
+	return events, nil
+}
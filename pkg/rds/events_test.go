@@ -0,0 +1,151 @@
+package rds
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+// mockEventsClient is a fake rds client with events, keyed by source type and identifier
+type mockEventsClient struct {
+	rdsiface.RDSAPI
+	events map[string][]*rds.Event
+}
+
+func (m *mockEventsClient) DescribeEventsPagesWithContext(_ aws.Context, input *rds.DescribeEventsInput, fn func(*rds.DescribeEventsOutput, bool) bool, _ ...request.Option) error {
+	categories := map[string]bool{}
+	for _, c := range input.EventCategories {
+		categories[aws.StringValue(c)] = true
+	}
+
+	events := m.events[aws.StringValue(input.SourceType)+"/"+aws.StringValue(input.SourceIdentifier)]
+
+	// each event is returned on its own page
+	for i, e := range events {
+		if e.Date.Before(aws.TimeValue(input.StartTime)) || e.Date.After(aws.TimeValue(input.EndTime)) {
+			continue
+		}
+
+		matched := len(categories) == 0
+		for _, c := range e.EventCategories {
+			matched = matched || categories[aws.StringValue(c)]
+		}
+		if !matched {
+			continue
+		}
+
+		if !fn(&rds.DescribeEventsOutput{Events: []*rds.Event{e}}, i == len(events)-1) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func event(message string, minute int, categories ...string) *rds.Event {
+	return &rds.Event{
+		Date:            aws.Time(time.Date(2024, 1, 1, 12, minute, 0, 0, time.UTC)),
+		EventCategories: aws.StringSlice(categories),
+		Message:         aws.String(message),
+	}
+}
+
+func TestClient_ListEvents(t *testing.T) {
+	mc := Client{
+		Service: &mockEventsClient{
+			events: map[string][]*rds.Event{
+				"db-cluster/mycluster": {
+					event("cluster created", 0, "creation"),
+					event("cluster failover started", 20, "failover"),
+					event("cluster failover completed", 25, "failover"),
+				},
+				"db-instance/mycluster-1": {
+					event("instance created", 5, "creation"),
+					event("instance rebooted", 22, "availability"),
+					event("backing up instance", 30, "backup"),
+				},
+			},
+		},
+	}
+
+	sources := []EventSource{
+		{Identifier: "mycluster", SourceType: rds.SourceTypeDbCluster},
+		{Identifier: "mycluster-1", SourceType: rds.SourceTypeDbInstance},
+	}
+
+	messages := func(events []*rds.Event) []string {
+		m := []string{}
+		for _, e := range events {
+			m = append(m, aws.StringValue(e.Message))
+		}
+		return m
+	}
+
+	tests := []struct {
+		name       string
+		since      time.Time
+		until      time.Time
+		categories []string
+		sources    []EventSource
+		want       []string
+	}{
+		{
+			name:    "all events merged and sorted",
+			since:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			sources: sources,
+			want:    []string{"cluster created", "instance created", "cluster failover started", "instance rebooted", "cluster failover completed", "backing up instance"},
+		},
+		{
+			name:    "events since",
+			since:   time.Date(2024, 1, 1, 12, 21, 0, 0, time.UTC),
+			sources: sources,
+			want:    []string{"instance rebooted", "cluster failover completed", "backing up instance"},
+		},
+		{
+			name:    "events until",
+			since:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			until:   time.Date(2024, 1, 1, 12, 21, 0, 0, time.UTC),
+			sources: sources,
+			want:    []string{"cluster created", "instance created", "cluster failover started"},
+		},
+		{
+			name:       "events in categories",
+			since:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			categories: []string{"failover", "backup"},
+			sources:    sources,
+			want:       []string{"cluster failover started", "cluster failover completed", "backing up instance"},
+		},
+		{
+			name:    "single source",
+			since:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			sources: sources[1:],
+			want:    []string{"instance created", "instance rebooted", "backing up instance"},
+		},
+		{
+			name:  "no sources",
+			since: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			until := tt.until
+			if until.IsZero() {
+				until = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+			}
+
+			events, err := mc.ListEvents(ctx, tt.since, until, tt.categories, tt.sources...)
+			if err != nil {
+				t.Fatalf("expected nil error, got %s", err)
+			}
+			if got := messages(events); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
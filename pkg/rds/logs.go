@@ -0,0 +1,65 @@
+package rds
+
+import (
+	"errors"
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// ListLogFiles returns the engine log files of the RDS instance with the given identifier
+func (r *Client) ListLogFiles(ctx aws.Context, instanceId string) ([]*rds.DescribeDBLogFilesDetails, error) {
+	if instanceId == "" {
+		return nil, errors.New("instance identifier cannot be empty")
+	}
+
+	files := []*rds.DescribeDBLogFilesDetails{}
+	if err := r.Service.DescribeDBLogFilesPagesWithContext(ctx, &rds.DescribeDBLogFilesInput{
+		DBInstanceIdentifier: aws.String(instanceId),
+	}, func(out *rds.DescribeDBLogFilesOutput, lastPage bool) bool {
+		files = append(files, out.DescribeDBLogFiles...)
+		return true
+	}); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// DownloadLogFilePages downloads the log file with the given name from the RDS instance with the given identifier,
+// calling fn with each portion of the file until there's no additional data pending or fn returns false.
+// With tailLines, only the given number of lines from the end of the file are downloaded, in a single portion.
+func (r *Client) DownloadLogFilePages(ctx aws.Context, instanceId, file string, tailLines int64, fn func(data string, lastPage bool) bool) error {
+	if instanceId == "" || file == "" {
+		return errors.New("instance identifier and log file name cannot be empty")
+	}
+
+	log.Printf("downloading log file %s of instance %s", file, instanceId)
+
+	input := &rds.DownloadDBLogFilePortionInput{
+		DBInstanceIdentifier: aws.String(instanceId),
+		LogFileName:          aws.String(file),
+	}
+
+	// without a marker the most recent lines are returned, a "0" marker starts at the beginning of the file
+	if tailLines > 0 {
+		input.NumberOfLines = aws.Int64(tailLines)
+	} else {
+		input.Marker = aws.String("0")
+	}
+
+	for {
+		out, err := r.Service.DownloadDBLogFilePortionWithContext(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		lastPage := tailLines > 0 || !aws.BoolValue(out.AdditionalDataPending)
+		if !fn(aws.StringValue(out.LogFileData), lastPage) || lastPage {
+			return nil
+		}
+
+		input.Marker = out.Marker
+	}
+}
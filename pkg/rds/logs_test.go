@@ -0,0 +1,150 @@
+package rds
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+// mockLogsClient is a fake rds client with log files, returned a line per portion
+type mockLogsClient struct {
+	rdsiface.RDSAPI
+	files map[string][]string
+	calls int
+}
+
+func (m *mockLogsClient) DescribeDBLogFilesPagesWithContext(_ aws.Context, input *rds.DescribeDBLogFilesInput, fn func(*rds.DescribeDBLogFilesOutput, bool) bool, _ ...request.Option) error {
+	for name, lines := range m.files {
+		fn(&rds.DescribeDBLogFilesOutput{
+			DescribeDBLogFiles: []*rds.DescribeDBLogFilesDetails{
+				{LogFileName: aws.String(name), Size: aws.Int64(int64(len(strings.Join(lines, ""))))},
+			},
+		}, false)
+	}
+	return nil
+}
+
+func (m *mockLogsClient) DownloadDBLogFilePortionWithContext(_ aws.Context, input *rds.DownloadDBLogFilePortionInput, _ ...request.Option) (*rds.DownloadDBLogFilePortionOutput, error) {
+	m.calls++
+
+	lines, ok := m.files[aws.StringValue(input.LogFileName)]
+	if !ok {
+		return nil, awserr.New(rds.ErrCodeDBLogFileNotFoundFault, "not found", nil)
+	}
+
+	if input.Marker == nil {
+		n := int(aws.Int64Value(input.NumberOfLines))
+		if n > len(lines) {
+			n = len(lines)
+		}
+		return &rds.DownloadDBLogFilePortionOutput{
+			AdditionalDataPending: aws.Bool(false),
+			LogFileData:           aws.String(strings.Join(lines[len(lines)-n:], "")),
+		}, nil
+	}
+
+	i, _ := strconv.Atoi(aws.StringValue(input.Marker))
+	return &rds.DownloadDBLogFilePortionOutput{
+		AdditionalDataPending: aws.Bool(i+1 < len(lines)),
+		LogFileData:           aws.String(lines[i]),
+		Marker:                aws.String(strconv.Itoa(i + 1)),
+	}, nil
+}
+
+func newLogsClient() (*mockLogsClient, Client) {
+	mock := &mockLogsClient{
+		files: map[string][]string{
+			"error/postgresql.log": {"line 1\n", "line 2\n", "line 3\n", "line 4\n"},
+		},
+	}
+	return mock, Client{Service: mock}
+}
+
+func TestClient_ListLogFiles(t *testing.T) {
+	_, mc := newLogsClient()
+
+	files, err := mc.ListLogFiles(ctx, "mydb")
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if len(files) != 1 || aws.StringValue(files[0].LogFileName) != "error/postgresql.log" {
+		t.Errorf("expected error/postgresql.log, got %+v", files)
+	}
+
+	if _, err := mc.ListLogFiles(ctx, ""); err == nil {
+		t.Error("expected error for empty instance identifier, got nil")
+	}
+}
+
+func TestClient_DownloadLogFilePages(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		tailLines int64
+		maxPages  int
+		want      []string
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "whole file",
+			file:      "error/postgresql.log",
+			want:      []string{"line 1\n", "line 2\n", "line 3\n", "line 4\n"},
+			wantCalls: 4,
+		},
+		{
+			name:      "stopped by callback",
+			file:      "error/postgresql.log",
+			maxPages:  2,
+			want:      []string{"line 1\n", "line 2\n"},
+			wantCalls: 2,
+		},
+		{
+			name:      "tail",
+			file:      "error/postgresql.log",
+			tailLines: 2,
+			want:      []string{"line 3\nline 4\n"},
+			wantCalls: 1,
+		},
+		{
+			name:      "missing file",
+			file:      "error/missing.log",
+			want:      []string{},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:    "empty file name",
+			want:    []string{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, mc := newLogsClient()
+
+			got := []string{}
+			err := mc.DownloadLogFilePages(ctx, "mydb", tt.file, tt.tailLines, func(data string, lastPage bool) bool {
+				got = append(got, data)
+				return tt.maxPages == 0 || len(got) < tt.maxPages
+			})
+			if tt.wantErr != (err != nil) {
+				t.Errorf("expected error: %t, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			if mock.calls != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, mock.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package rds
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// maintenance opt in types, see https://docs.aws.amazon.com/AmazonRDS/latest/APIReference/API_ApplyPendingMaintenanceAction.html
+const (
+	OptInImmediate       = "immediate"
+	OptInNextMaintenance = "next-maintenance"
+	OptInUndo            = "undo-opt-in"
+)
+
+// ListPendingMaintenanceActions returns the pending maintenance actions for the RDS resources with the given ARNs,
+// or for all of the resources in the account if no ARNs are given.  Resources without pending actions are omitted.
+func (r *Client) ListPendingMaintenanceActions(ctx aws.Context, arns ...string) ([]*rds.ResourcePendingMaintenanceActions, error) {
+	inputs := []*rds.DescribePendingMaintenanceActionsInput{}
+	for _, a := range arns {
+		inputs = append(inputs, &rds.DescribePendingMaintenanceActionsInput{ResourceIdentifier: aws.String(a)})
+	}
+	if len(inputs) == 0 {
+		inputs = append(inputs, &rds.DescribePendingMaintenanceActionsInput{})
+	}
+
+	actions := []*rds.ResourcePendingMaintenanceActions{}
+	for _, input := range inputs {
+		if err := r.Service.DescribePendingMaintenanceActionsPagesWithContext(ctx, input,
+			func(out *rds.DescribePendingMaintenanceActionsOutput, lastPage bool) bool {
+				for _, a := range out.PendingMaintenanceActions {
+					if len(a.PendingMaintenanceActionDetails) > 0 {
+						actions = append(actions, a)
+					}
+				}
+				return true
+			}); err != nil {
+			return nil, err
+		}
+	}
+
+	return actions, nil
+}
+
+// ApplyPendingMaintenanceAction applies, or schedules, the given pending maintenance action for the RDS
+// resource with the given ARN.  The opt in type is one of OptInImmediate, OptInNextMaintenance or OptInUndo.
+func (r *Client) ApplyPendingMaintenanceAction(ctx aws.Context, arn, action, optInType string) (*rds.ResourcePendingMaintenanceActions, error) {
+	if arn == "" || action == "" {
+		return nil, errors.New("resource ARN and maintenance action cannot be empty")
+	}
+
+	if optInType != OptInImmediate && optInType != OptInNextMaintenance && optInType != OptInUndo {
+		return nil, errors.New("invalid maintenance opt in type " + optInType)
+	}
+
+	log.Printf("Applying pending maintenance action %s for %s (opt in: %s)", action, arn, optInType)
+
+	out, err := r.Service.ApplyPendingMaintenanceActionWithContext(ctx, &rds.ApplyPendingMaintenanceActionInput{
+		ApplyAction:        aws.String(action),
+		OptInType:          aws.String(optInType),
+		ResourceIdentifier: aws.String(arn),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.ResourcePendingMaintenanceActions, nil
+}
+
+// MaintenanceDueBefore returns the pending maintenance actions that are forced or automatically applied before the
+// given time, with only the details of the actions that are due.  Resources without due actions are omitted.
+func MaintenanceDueBefore(actions []*rds.ResourcePendingMaintenanceActions, before time.Time) []*rds.ResourcePendingMaintenanceActions {
+	due := []*rds.ResourcePendingMaintenanceActions{}
+	for _, a := range actions {
+		details := []*rds.PendingMaintenanceAction{}
+		for _, d := range a.PendingMaintenanceActionDetails {
+			if (d.ForcedApplyDate != nil && d.ForcedApplyDate.Before(before)) ||
+				(d.AutoAppliedAfterDate != nil && d.AutoAppliedAfterDate.Before(before)) {
+				details = append(details, d)
+			}
+		}
+
+		if len(details) > 0 {
+			due = append(due, &rds.ResourcePendingMaintenanceActions{
+				PendingMaintenanceActionDetails: details,
+				ResourceIdentifier:              a.ResourceIdentifier,
+			})
+		}
+	}
+	return due
+}
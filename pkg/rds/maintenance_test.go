@@ -0,0 +1,115 @@
+package rds
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+// mockMaintenanceClient is a fake rds client with pending maintenance actions, keyed by resource ARN
+type mockMaintenanceClient struct {
+	rdsiface.RDSAPI
+	actions map[string][]*rds.PendingMaintenanceAction
+	applied []string
+}
+
+func (m *mockMaintenanceClient) DescribePendingMaintenanceActionsPagesWithContext(_ aws.Context, input *rds.DescribePendingMaintenanceActionsInput, fn func(*rds.DescribePendingMaintenanceActionsOutput, bool) bool, _ ...request.Option) error {
+	out := &rds.DescribePendingMaintenanceActionsOutput{}
+	for arn, details := range m.actions {
+		if input.ResourceIdentifier == nil || aws.StringValue(input.ResourceIdentifier) == arn {
+			out.PendingMaintenanceActions = append(out.PendingMaintenanceActions, &rds.ResourcePendingMaintenanceActions{
+				PendingMaintenanceActionDetails: details,
+				ResourceIdentifier:              aws.String(arn),
+			})
+		}
+	}
+	fn(out, true)
+	return nil
+}
+
+func (m *mockMaintenanceClient) ApplyPendingMaintenanceActionWithContext(_ aws.Context, input *rds.ApplyPendingMaintenanceActionInput, _ ...request.Option) (*rds.ApplyPendingMaintenanceActionOutput, error) {
+	m.applied = append(m.applied, aws.StringValue(input.ResourceIdentifier)+"/"+aws.StringValue(input.ApplyAction)+"/"+aws.StringValue(input.OptInType))
+	return &rds.ApplyPendingMaintenanceActionOutput{
+		ResourcePendingMaintenanceActions: &rds.ResourcePendingMaintenanceActions{ResourceIdentifier: input.ResourceIdentifier},
+	}, nil
+}
+
+func newMaintenanceClient() (*mockMaintenanceClient, Client) {
+	mock := &mockMaintenanceClient{
+		actions: map[string][]*rds.PendingMaintenanceAction{
+			"arn:aws:rds:us-east-1:0123456789:db:db1": {
+				{Action: aws.String("system-update"), ForcedApplyDate: aws.Time(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))},
+				{Action: aws.String("db-upgrade"), AutoAppliedAfterDate: aws.Time(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))},
+			},
+			"arn:aws:rds:us-east-1:0123456789:db:db2": {
+				{Action: aws.String("system-update")},
+			},
+			"arn:aws:rds:us-east-1:0123456789:db:db3": {},
+		},
+	}
+	return mock, Client{Service: mock}
+}
+
+func TestClient_ListPendingMaintenanceActions(t *testing.T) {
+	_, mc := newMaintenanceClient()
+
+	actions, err := mc.ListPendingMaintenanceActions(ctx)
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if len(actions) != 2 {
+		t.Errorf("expected 2 resources with pending actions, got %d", len(actions))
+	}
+
+	actions, err = mc.ListPendingMaintenanceActions(ctx, "arn:aws:rds:us-east-1:0123456789:db:db1", "arn:aws:rds:us-east-1:0123456789:db:db3")
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if len(actions) != 1 || aws.StringValue(actions[0].ResourceIdentifier) != "arn:aws:rds:us-east-1:0123456789:db:db1" {
+		t.Errorf("expected pending actions for db1, got %+v", actions)
+	}
+}
+
+func TestClient_ApplyPendingMaintenanceAction(t *testing.T) {
+	mock, mc := newMaintenanceClient()
+
+	if _, err := mc.ApplyPendingMaintenanceAction(ctx, "arn:aws:rds:us-east-1:0123456789:db:db2", "system-update", OptInNextMaintenance); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	expected := "arn:aws:rds:us-east-1:0123456789:db:db2/system-update/next-maintenance"
+	if len(mock.applied) != 1 || mock.applied[0] != expected {
+		t.Errorf("expected %s to be applied, got %v", expected, mock.applied)
+	}
+
+	if _, err := mc.ApplyPendingMaintenanceAction(ctx, "arn:aws:rds:us-east-1:0123456789:db:db2", "system-update", "whenever"); err == nil {
+		t.Error("expected error for invalid opt in type, got nil")
+	}
+
+	if _, err := mc.ApplyPendingMaintenanceAction(ctx, "", "system-update", OptInImmediate); err == nil {
+		t.Error("expected error for empty ARN, got nil")
+	}
+}
+
+func TestMaintenanceDueBefore(t *testing.T) {
+	_, mc := newMaintenanceClient()
+
+	actions, err := mc.ListPendingMaintenanceActions(ctx)
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	due := MaintenanceDueBefore(actions, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+	if len(due) != 1 || len(due[0].PendingMaintenanceActionDetails) != 1 || aws.StringValue(due[0].PendingMaintenanceActionDetails[0].Action) != "system-update" {
+		t.Errorf("expected only the forced system-update of db1 to be due, got %+v", due)
+	}
+
+	due = MaintenanceDueBefore(actions, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC))
+	if len(due) != 1 || len(due[0].PendingMaintenanceActionDetails) != 2 {
+		t.Errorf("expected both actions of db1 to be due, got %+v", due)
+	}
+}
@@ -0,0 +1,138 @@
+package rds
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// ListClusterMembers returns the instances in the database cluster with the given identifier, sorted by identifier
+func (r *Client) ListClusterMembers(ctx aws.Context, cluster string) ([]*rds.DBInstance, error) {
+	if cluster == "" {
+		return nil, errors.New("cluster identifier cannot be empty")
+	}
+
+	members := []*rds.DBInstance{}
+	if err := r.Service.DescribeDBInstancesPagesWithContext(ctx, &rds.DescribeDBInstancesInput{
+		Filters: []*rds.Filter{
+			{
+				Name:   aws.String("db-cluster-id"),
+				Values: aws.StringSlice([]string{cluster}),
+			},
+		},
+	}, func(out *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+		members = append(members, out.DBInstances...)
+		return true
+	}); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return aws.StringValue(members[i].DBInstanceIdentifier) < aws.StringValue(members[j].DBInstanceIdentifier)
+	})
+
+	return members, nil
+}
+
+// SubnetGroupAvailabilityZones returns the sorted availability zones of the subnets in the given subnet group
+func (r *Client) SubnetGroupAvailabilityZones(ctx aws.Context, name string) ([]string, error) {
+	if name == "" {
+		return nil, errors.New("subnet group name cannot be empty")
+	}
+
+	out, err := r.Service.DescribeDBSubnetGroupsWithContext(ctx, &rds.DescribeDBSubnetGroupsInput{
+		DBSubnetGroupName: aws.String(name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.DBSubnetGroups) != 1 {
+		return nil, errors.New("unexpected number of subnet groups")
+	}
+
+	seen := map[string]bool{}
+	azs := []string{}
+	for _, s := range out.DBSubnetGroups[0].Subnets {
+		if s.SubnetAvailabilityZone == nil {
+			continue
+		}
+		az := aws.StringValue(s.SubnetAvailabilityZone.Name)
+		if az != "" && !seen[az] {
+			seen[az] = true
+			azs = append(azs, az)
+		}
+	}
+	sort.Strings(azs)
+
+	return azs, nil
+}
+
+// MemberIdentifiers generates count identifiers for new instances in the given cluster, in the
+// form <cluster>-<n>, skipping the identifiers of the existing members
+func MemberIdentifiers(cluster string, existing []string, count int) []string {
+	taken := map[string]bool{}
+	for _, e := range existing {
+		taken[e] = true
+	}
+
+	ids := []string{}
+	for n := 1; len(ids) < count; n++ {
+		id := fmt.Sprintf("%s-%d", cluster, n)
+		if !taken[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// SpreadAvailabilityZones returns the availability zones for count new instances, picking the
+// zone with the fewest instances each time, given the zones of the existing instances
+func SpreadAvailabilityZones(azs, existing []string, count int) []string {
+	if len(azs) == 0 {
+		return make([]string, count)
+	}
+
+	used := map[string]int{}
+	for _, az := range existing {
+		used[az]++
+	}
+
+	spread := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		pick := azs[0]
+		for _, az := range azs[1:] {
+			if used[az] < used[pick] {
+				pick = az
+			}
+		}
+		used[pick]++
+		spread = append(spread, pick)
+	}
+	return spread
+}
+
+// DeleteCluster deletes the RDS database cluster with the given identifier, which must not have any members left.
+// If a final snapshot identifier is given, a final snapshot of the cluster is created with that name.
+func (r *Client) DeleteCluster(ctx aws.Context, id, finalSnapshot string) error {
+	if id == "" {
+		return errors.New("database identifier cannot be empty")
+	}
+
+	input := &rds.DeleteDBClusterInput{
+		DBClusterIdentifier: aws.String(id),
+		SkipFinalSnapshot:   aws.Bool(true),
+	}
+	if finalSnapshot != "" {
+		input.FinalDBSnapshotIdentifier = aws.String(finalSnapshot)
+		input.SkipFinalSnapshot = aws.Bool(false)
+	}
+
+	log.Printf("Deleting database cluster %s (final snapshot: %q)", id, finalSnapshot)
+
+	_, err := r.Service.DeleteDBClusterWithContext(ctx, input)
+	return err
+}
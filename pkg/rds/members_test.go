@@ -0,0 +1,175 @@
+package rds
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+func (m *mockRDSClient) DescribeDBSubnetGroupsWithContext(_ aws.Context, input *rds.DescribeDBSubnetGroupsInput, _ ...request.Option) (*rds.DescribeDBSubnetGroupsOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &rds.DescribeDBSubnetGroupsOutput{
+		DBSubnetGroups: []*rds.DBSubnetGroup{
+			{
+				DBSubnetGroupName: input.DBSubnetGroupName,
+				Subnets: []*rds.Subnet{
+					{SubnetAvailabilityZone: &rds.AvailabilityZone{Name: aws.String("us-east-1b")}},
+					{SubnetAvailabilityZone: &rds.AvailabilityZone{Name: aws.String("us-east-1a")}},
+					{SubnetAvailabilityZone: &rds.AvailabilityZone{Name: aws.String("us-east-1b")}},
+					{},
+				},
+			},
+		},
+	}, nil
+}
+
+func TestClient_SubnetGroupAvailabilityZones(t *testing.T) {
+	mc := Client{Service: newmockRDSClient(t, nil)}
+
+	got, err := mc.SubnetGroupAvailabilityZones(ctx, "subnets")
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	expected := []string{"us-east-1a", "us-east-1b"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if _, err := mc.SubnetGroupAvailabilityZones(ctx, ""); err == nil {
+		t.Error("expected error for empty subnet group name, got nil")
+	}
+}
+
+func TestMemberIdentifiers(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing []string
+		count    int
+		want     []string
+	}{
+		{
+			name:     "first readers",
+			existing: []string{"mycluster"},
+			count:    2,
+			want:     []string{"mycluster-1", "mycluster-2"},
+		},
+		{
+			name:     "skip existing",
+			existing: []string{"mycluster", "mycluster-1", "mycluster-3"},
+			count:    2,
+			want:     []string{"mycluster-2", "mycluster-4"},
+		},
+		{
+			name:  "none",
+			count: 0,
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MemberIdentifiers("mycluster", tt.existing, tt.count); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MemberIdentifiers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpreadAvailabilityZones(t *testing.T) {
+	azs := []string{"us-east-1a", "us-east-1b", "us-east-1c"}
+
+	tests := []struct {
+		name     string
+		azs      []string
+		existing []string
+		count    int
+		want     []string
+	}{
+		{
+			name:  "no existing instances",
+			azs:   azs,
+			count: 4,
+			want:  []string{"us-east-1a", "us-east-1b", "us-east-1c", "us-east-1a"},
+		},
+		{
+			name:     "fill the emptiest zones first",
+			azs:      azs,
+			existing: []string{"us-east-1a", "us-east-1c", "us-east-1a"},
+			count:    2,
+			want:     []string{"us-east-1b", "us-east-1b"},
+		},
+		{
+			name:  "no zones",
+			count: 2,
+			want:  []string{"", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SpreadAvailabilityZones(tt.azs, tt.existing, tt.count); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SpreadAvailabilityZones() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// mockDeleteClusterClient records the cluster delete input
+type mockDeleteClusterClient struct {
+	rdsiface.RDSAPI
+	input *rds.DeleteDBClusterInput
+}
+
+func (m *mockDeleteClusterClient) DeleteDBClusterWithContext(_ aws.Context, input *rds.DeleteDBClusterInput, _ ...request.Option) (*rds.DeleteDBClusterOutput, error) {
+	m.input = input
+	return &rds.DeleteDBClusterOutput{}, nil
+}
+
+func TestClient_DeleteCluster(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            string
+		finalSnapshot string
+		wantSkip      bool
+		wantErr       bool
+	}{
+		{name: "without final snapshot", id: "mycluster", wantSkip: true},
+		{name: "with final snapshot", id: "mycluster", finalSnapshot: "final-mycluster-20240101120000"},
+		{name: "empty id", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockDeleteClusterClient{}
+			mc := Client{Service: mock}
+
+			err := mc.DeleteCluster(ctx, tt.id, tt.finalSnapshot)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected nil error, got %s", err)
+			}
+
+			if aws.StringValue(mock.input.DBClusterIdentifier) != tt.id {
+				t.Errorf("expected cluster %s to be deleted, got %s", tt.id, aws.StringValue(mock.input.DBClusterIdentifier))
+			}
+			if aws.BoolValue(mock.input.SkipFinalSnapshot) != tt.wantSkip {
+				t.Errorf("expected SkipFinalSnapshot %t, got %t", tt.wantSkip, aws.BoolValue(mock.input.SkipFinalSnapshot))
+			}
+			if aws.StringValue(mock.input.FinalDBSnapshotIdentifier) != tt.finalSnapshot {
+				t.Errorf("expected final snapshot %q, got %q", tt.finalSnapshot, aws.StringValue(mock.input.FinalDBSnapshotIdentifier))
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package rds
+
+import (
+	"errors"
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// SetDeletionProtection immediately enables or disables the deletion protection of an RDS database cluster or
+// instance.  Like StopDatabase, it first looks for a cluster with the given identifier and falls back to an instance.
+func (r *Client) SetDeletionProtection(ctx aws.Context, id string, protect bool) error {
+	if id == "" {
+		return errors.New("database identifier cannot be empty")
+	}
+
+	cluster, err := r.describeCluster(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Setting deletion protection of database with identifier %s to %t", id, protect)
+
+	if cluster != nil {
+		_, err = r.Service.ModifyDBClusterWithContext(ctx, &rds.ModifyDBClusterInput{
+			ApplyImmediately:    aws.Bool(true),
+			DBClusterIdentifier: aws.String(id),
+			DeletionProtection:  aws.Bool(protect),
+		})
+		return err
+	}
+
+	_, err = r.Service.ModifyDBInstanceWithContext(ctx, &rds.ModifyDBInstanceInput{
+		ApplyImmediately:     aws.Bool(true),
+		DBInstanceIdentifier: aws.String(id),
+		DeletionProtection:   aws.Bool(protect),
+	})
+	return err
+}
@@ -0,0 +1,34 @@
+package rds
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+func TestClient_SetDeletionProtection(t *testing.T) {
+	mock := &mockDescribeClient{
+		clusters: map[string]*rds.DBCluster{
+			"cluster": {DBClusterIdentifier: aws.String("cluster"), DeletionProtection: aws.Bool(true)},
+		},
+	}
+	mc := Client{Service: mock}
+
+	if err := mc.SetDeletionProtection(ctx, "cluster", false); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if err := mc.SetDeletionProtection(ctx, "instance", false); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	expected := []string{"cluster/cluster", "instance/instance"}
+	if !reflect.DeepEqual(mock.modified, expected) {
+		t.Errorf("expected %v to be modified, got %v", expected, mock.modified)
+	}
+
+	if err := mc.SetDeletionProtection(ctx, "", false); err == nil {
+		t.Error("expected error for empty identifier, got nil")
+	}
+}
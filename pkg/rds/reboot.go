@@ -0,0 +1,91 @@
+package rds
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/YaleSpinup/apierror"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/gobuffalo/buffalo"
+)
+
+// RebootDatabase reboots an RDS database instance or all of the instances in a cluster, and returns the rebooted
+// instances.  Like StopDatabase, it first looks for a cluster with the given identifier and falls back to an instance.
+// ForceFailover reboots a Multi-AZ instance with a failover to the standby, it can't be used for clusters.
+func (r *Client) RebootDatabase(ctx buffalo.Context, id string, forceFailover bool) ([]*rds.DBInstance, error) {
+	if id == "" {
+		return nil, errors.New("database identifier cannot be empty")
+	}
+
+	cluster, err := r.describeCluster(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []*string{aws.String(id)}
+	if cluster != nil {
+		if forceFailover {
+			return nil, apierror.New(apierror.ErrBadRequest, "ForceFailover is not supported for clusters, use failover instead", nil)
+		}
+
+		ids = []*string{}
+		for _, m := range cluster.DBClusterMembers {
+			ids = append(ids, m.DBInstanceIdentifier)
+		}
+	}
+
+	instances := []*rds.DBInstance{}
+	for _, i := range ids {
+		log.Printf("Rebooting database instance with identifier %s (force failover: %t)", aws.StringValue(i), forceFailover)
+
+		input := &rds.RebootDBInstanceInput{
+			DBInstanceIdentifier: i,
+		}
+		if forceFailover {
+			input.ForceFailover = aws.Bool(true)
+		}
+
+		out, err := r.Service.RebootDBInstanceWithContext(ctx, input)
+		if err != nil {
+			return instances, err
+		}
+		instances = append(instances, out.DBInstance)
+	}
+
+	return instances, nil
+}
+
+// FailoverDatabase forces a failover of an RDS database cluster, optionally to the given target instance
+func (r *Client) FailoverDatabase(ctx buffalo.Context, id, target string) (*rds.DBCluster, error) {
+	if id == "" {
+		return nil, errors.New("database identifier cannot be empty")
+	}
+
+	cluster, err := r.describeCluster(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if cluster == nil {
+		msg := fmt.Sprintf("database %s is not a cluster, reboot it with ForceFailover instead", id)
+		return nil, apierror.New(apierror.ErrBadRequest, msg, nil)
+	}
+
+	log.Printf("Failing over database cluster with identifier %s (target: %s)", id, target)
+
+	input := &rds.FailoverDBClusterInput{
+		DBClusterIdentifier: aws.String(id),
+	}
+	if target != "" {
+		input.TargetDBInstanceIdentifier = aws.String(target)
+	}
+
+	out, err := r.Service.FailoverDBClusterWithContext(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.DBCluster, nil
+}
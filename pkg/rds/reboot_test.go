@@ -0,0 +1,94 @@
+package rds
+
+import (
+	"testing"
+
+	"github.com/YaleSpinup/apierror"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+func (m *mockDescribeClient) RebootDBInstanceWithContext(_ aws.Context, input *rds.RebootDBInstanceInput, _ ...request.Option) (*rds.RebootDBInstanceOutput, error) {
+	i, ok := m.instances[aws.StringValue(input.DBInstanceIdentifier)]
+	if !ok {
+		return nil, awserr.New(rds.ErrCodeDBInstanceNotFoundFault, "not found", nil)
+	}
+	return &rds.RebootDBInstanceOutput{DBInstance: i}, nil
+}
+
+func (m *mockDescribeClient) FailoverDBClusterWithContext(_ aws.Context, input *rds.FailoverDBClusterInput, _ ...request.Option) (*rds.FailoverDBClusterOutput, error) {
+	return &rds.FailoverDBClusterOutput{DBCluster: m.clusters[aws.StringValue(input.DBClusterIdentifier)]}, nil
+}
+
+func newRebootClient() Client {
+	return Client{
+		Service: &mockDescribeClient{
+			clusters: map[string]*rds.DBCluster{
+				"cluster": {
+					DBClusterIdentifier: aws.String("cluster"),
+					DBClusterMembers: []*rds.DBClusterMember{
+						{DBInstanceIdentifier: aws.String("cluster")},
+						{DBInstanceIdentifier: aws.String("cluster-1")},
+					},
+				},
+			},
+			instances: map[string]*rds.DBInstance{
+				"cluster":   {DBInstanceIdentifier: aws.String("cluster")},
+				"cluster-1": {DBInstanceIdentifier: aws.String("cluster-1")},
+				"instance":  {DBInstanceIdentifier: aws.String("instance")},
+			},
+		},
+	}
+}
+
+func TestClient_RebootDatabase(t *testing.T) {
+	mc := newRebootClient()
+
+	tests := []struct {
+		id            string
+		forceFailover bool
+		want          int
+		wantErr       bool
+	}{
+		{id: "cluster", want: 2},
+		{id: "cluster", forceFailover: true, wantErr: true},
+		{id: "instance", want: 1},
+		{id: "instance", forceFailover: true, want: 1},
+		{id: "unknown", wantErr: true},
+		{id: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := mc.RebootDatabase(ctx, tt.id, tt.forceFailover)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("RebootDatabase(%s, %t) error = %v, wantErr %v", tt.id, tt.forceFailover, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && len(got) != tt.want {
+			t.Errorf("RebootDatabase(%s, %t) rebooted %d instances, want %d", tt.id, tt.forceFailover, len(got), tt.want)
+		}
+	}
+}
+
+func TestClient_FailoverDatabase(t *testing.T) {
+	mc := newRebootClient()
+
+	got, err := mc.FailoverDatabase(ctx, "cluster", "cluster-1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if aws.StringValue(got.DBClusterIdentifier) != "cluster" {
+		t.Errorf("unexpected cluster %+v", got)
+	}
+
+	_, err = mc.FailoverDatabase(ctx, "instance", "")
+	if aerr, ok := err.(apierror.Error); !ok || aerr.Code != apierror.ErrBadRequest {
+		t.Errorf("expected bad request error, got %v", err)
+	}
+
+	if _, err := mc.FailoverDatabase(ctx, "", ""); err == nil {
+		t.Error("expected error for empty identifier, got nil")
+	}
+}
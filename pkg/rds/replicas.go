@@ -0,0 +1,65 @@
+package rds
+
+import (
+	"errors"
+	"log"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// ListReadReplicas returns the read replicas of the database instance with the given identifier.
+// Replicas that can't be found (e.g. in another region or being deleted) are skipped.
+func (r *Client) ListReadReplicas(ctx aws.Context, id string) ([]*rds.DBInstance, error) {
+	if id == "" {
+		return nil, errors.New("database identifier cannot be empty")
+	}
+
+	out, err := r.Service.DescribeDBInstancesWithContext(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(out.DBInstances) != 1 {
+		return nil, errors.New("unexpected number of database instances")
+	}
+
+	replicas := []*rds.DBInstance{}
+	for _, replicaId := range out.DBInstances[0].ReadReplicaDBInstanceIdentifiers {
+		replicaOut, err := r.Service.DescribeDBInstancesWithContext(ctx, &rds.DescribeDBInstancesInput{
+			DBInstanceIdentifier: replicaId,
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == rds.ErrCodeDBInstanceNotFoundFault {
+				log.Printf("read replica %s of %s not found, skipping", aws.StringValue(replicaId), id)
+				continue
+			}
+			return nil, err
+		}
+		replicas = append(replicas, replicaOut.DBInstances...)
+	}
+
+	return replicas, nil
+}
+
+// PromoteReadReplica promotes the read replica with the given identifier to a standalone database instance.
+// The backup retention period is optional, if it's nil the retention period of the replica is kept.
+func (r *Client) PromoteReadReplica(ctx aws.Context, id string, backupRetentionPeriod *int64) (*rds.DBInstance, error) {
+	if id == "" {
+		return nil, errors.New("database identifier cannot be empty")
+	}
+
+	log.Printf("promoting read replica with identifier %s", id)
+
+	out, err := r.Service.PromoteReadReplicaWithContext(ctx, &rds.PromoteReadReplicaInput{
+		BackupRetentionPeriod: backupRetentionPeriod,
+		DBInstanceIdentifier:  aws.String(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out.DBInstance, nil
+}
@@ -0,0 +1,91 @@
+package rds
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+func (m *mockRDSClient) PromoteReadReplicaWithContext(_ aws.Context, input *rds.PromoteReadReplicaInput, _ ...request.Option) (*rds.PromoteReadReplicaOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &rds.PromoteReadReplicaOutput{
+		DBInstance: &rds.DBInstance{
+			DBInstanceIdentifier:  input.DBInstanceIdentifier,
+			BackupRetentionPeriod: input.BackupRetentionPeriod,
+		},
+	}, nil
+}
+
+func TestClient_ListReadReplicas(t *testing.T) {
+	mc := Client{
+		Service: &mockDescribeClient{
+			instances: map[string]*rds.DBInstance{
+				"source": {
+					DBInstanceIdentifier:             aws.String("source"),
+					ReadReplicaDBInstanceIdentifiers: aws.StringSlice([]string{"replica1", "gone", "replica2"}),
+				},
+				"replica1": {DBInstanceIdentifier: aws.String("replica1")},
+				"replica2": {DBInstanceIdentifier: aws.String("replica2")},
+				"single":   {DBInstanceIdentifier: aws.String("single")},
+			},
+		},
+	}
+
+	tests := []struct {
+		id      string
+		want    []string
+		wantErr bool
+	}{
+		{id: "source", want: []string{"replica1", "replica2"}},
+		{id: "single", want: []string{}},
+		{id: "unknown", wantErr: true},
+		{id: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, err := mc.ListReadReplicas(ctx, tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ListReadReplicas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("ListReadReplicas() got %d replicas, want %d", len(got), len(tt.want))
+			}
+			for i, r := range got {
+				if aws.StringValue(r.DBInstanceIdentifier) != tt.want[i] {
+					t.Errorf("ListReadReplicas() got replica %s, want %s", aws.StringValue(r.DBInstanceIdentifier), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestClient_PromoteReadReplica(t *testing.T) {
+	mc := Client{Service: newmockRDSClient(t, nil)}
+
+	got, err := mc.PromoteReadReplica(ctx, "replica", aws.Int64(7))
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if aws.StringValue(got.DBInstanceIdentifier) != "replica" || aws.Int64Value(got.BackupRetentionPeriod) != 7 {
+		t.Errorf("unexpected promoted instance %+v", got)
+	}
+
+	if _, err := mc.PromoteReadReplica(ctx, "", nil); err == nil {
+		t.Error("expected error for empty identifier, got nil")
+	}
+
+	mc = Client{Service: newmockRDSClient(t, awserr.New(rds.ErrCodeInvalidDBInstanceStateFault, "not a replica", nil))}
+	if _, err := mc.PromoteReadReplica(ctx, "replica", nil); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
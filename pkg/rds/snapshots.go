@@ -38,11 +38,12 @@ func (r *Client) DescribeDBSnaphot(ctx buffalo.Context, snapshotId string) (*rds
 	})
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok {
-			if aerr.Code() != rds.ErrCodeDBSnapshotNotFoundFault {
+			if aerr.Code() == rds.ErrCodeDBSnapshotNotFoundFault {
 				msg := fmt.Sprintf("instance with snapshot id %s not found", snapshotId)
 				return nil, apierror.New(apierror.ErrNotFound, msg, err)
 			}
 		}
+		return nil, err
 	}
 	if len(instanceSnapshotsOutput.DBSnapshots) != 1 {
 		msg := fmt.Sprintf("expected 1 snapshot but found %d, snapshot id: %s", len(instanceSnapshotsOutput.DBSnapshots), snapshotId)
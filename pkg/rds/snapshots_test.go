@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/YaleSpinup/apierror"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/request"
@@ -114,6 +115,51 @@ func TestClient_GetSnapshotInfo(t *testing.T) {
 	}
 }
 
+func TestClient_DescribeDBSnaphot(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+		wantErr  error
+	}{
+		{
+			name: "success case",
+		},
+		{
+			name:     "snapshot not found",
+			err:      awserr.New(rds.ErrCodeDBSnapshotNotFoundFault, "not found.", nil),
+			wantCode: apierror.ErrNotFound,
+		},
+		{
+			// other errors used to be reported as not found
+			name:    "aws error",
+			err:     awserr.New("AccessDenied", "denied.", nil),
+			wantErr: awserr.New("AccessDenied", "denied.", nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Client{Service: newmockRDSClient(t, tt.err)}
+			got, err := r.DescribeDBSnaphot(ctx, "mydb-snap")
+
+			switch {
+			case tt.wantCode != "":
+				if aerr, ok := err.(apierror.Error); !ok || aerr.Code != tt.wantCode {
+					t.Errorf("expected apierror with code %s, got %v", tt.wantCode, err)
+				}
+			case tt.wantErr != nil:
+				if !reflect.DeepEqual(err, tt.wantErr) {
+					t.Errorf("expected error %v, got %v", tt.wantErr, err)
+				}
+			case err != nil:
+				t.Errorf("expected nil error, got %s", err)
+			case aws.StringValue(got.EngineVersion) != "14.5":
+				t.Errorf("unexpected snapshot %+v", got)
+			}
+		})
+	}
+}
+
 func TestClient_DescribeDBEngineVersions(t *testing.T) {
 	type fields struct {
 		Service                            rdsiface.RDSAPI
@@ -0,0 +1,256 @@
+package rds
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// storage types, see https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/CHAP_Storage.html
+const (
+	StorageTypeGP2               = "gp2"
+	StorageTypeGP3               = "gp3"
+	StorageTypeIO1               = "io1"
+	StorageTypeIO2               = "io2"
+	StorageTypeAurora            = "aurora"
+	StorageTypeAuroraIOOptimized = "aurora-iopt1"
+)
+
+// Storage is the storage configuration of a database instance: the allocated and maximum allocated storage
+// in GiB, the provisioned IOPS and the storage throughput in MiB/s.  Zero values are not set.
+type Storage struct {
+	StorageType         string
+	AllocatedStorage    int64
+	Iops                int64
+	StorageThroughput   int64
+	MaxAllocatedStorage int64
+}
+
+// IsZero returns true if none of the storage configuration is set
+func (s Storage) IsZero() bool {
+	return s == Storage{}
+}
+
+// InstanceStorage returns the storage configuration of the given database instance
+func InstanceStorage(instance *rds.DBInstance) Storage {
+	return Storage{
+		StorageType:         aws.StringValue(instance.StorageType),
+		AllocatedStorage:    aws.Int64Value(instance.AllocatedStorage),
+		Iops:                aws.Int64Value(instance.Iops),
+		StorageThroughput:   aws.Int64Value(instance.StorageThroughput),
+		MaxAllocatedStorage: aws.Int64Value(instance.MaxAllocatedStorage),
+	}
+}
+
+// storageLimits are the engine specific storage limits of an instance
+type storageLimits struct {
+	// minimum allocated storage by storage type, in GiB
+	minStorage map[string]int64
+	// maximum allocated storage, in GiB
+	maxStorage int64
+	// gp3 storage below this size has a fixed baseline IOPS and throughput
+	gp3Threshold int64
+	// provisioned IOPS and throughput range of gp3 storage at or above the threshold
+	gp3MinIops, gp3MaxIops             int64
+	gp3MinThroughput, gp3MaxThroughput int64
+}
+
+var defaultStorageLimits = storageLimits{
+	minStorage:       map[string]int64{StorageTypeGP2: 20, StorageTypeGP3: 20, StorageTypeIO1: 100, StorageTypeIO2: 100},
+	maxStorage:       65536,
+	gp3Threshold:     400,
+	gp3MinIops:       12000,
+	gp3MaxIops:       64000,
+	gp3MinThroughput: 500,
+	gp3MaxThroughput: 4000,
+}
+
+// storageLimitsFor returns the storage limits for the given engine
+func storageLimitsFor(engine string) storageLimits {
+	switch {
+	case strings.HasPrefix(engine, "oracle"):
+		limits := defaultStorageLimits
+		limits.gp3Threshold = 200
+		return limits
+	case strings.HasPrefix(engine, "sqlserver"):
+		return storageLimits{
+			minStorage:       map[string]int64{StorageTypeGP2: 20, StorageTypeGP3: 20, StorageTypeIO1: 20, StorageTypeIO2: 20},
+			maxStorage:       16384,
+			gp3Threshold:     0,
+			gp3MinIops:       3000,
+			gp3MaxIops:       16000,
+			gp3MinThroughput: 125,
+			gp3MaxThroughput: 1000,
+		}
+	default:
+		return defaultStorageLimits
+	}
+}
+
+// ValidateStorage checks the storage configuration of a new database instance with the given engine against
+// the RDS limits.  The engine can be empty when it's not known, eg. when restoring from a snapshot.
+func ValidateStorage(engine string, s Storage) error {
+	if s.IsZero() {
+		return nil
+	}
+
+	if strings.HasPrefix(engine, "aurora") {
+		return fmt.Errorf("the storage of %s instances is configured on the cluster", engine)
+	}
+
+	storageType := s.StorageType
+	if storageType == "" && s.Iops > 0 {
+		// RDS defaults to provisioned IOPS storage when IOPS are given
+		storageType = StorageTypeIO1
+	}
+
+	limits := storageLimitsFor(engine)
+
+	if storageType != "" {
+		min, ok := limits.minStorage[storageType]
+		if !ok {
+			return fmt.Errorf("invalid storage type %s, must be one of gp2, gp3, io1 or io2", storageType)
+		}
+
+		if s.AllocatedStorage > 0 && s.AllocatedStorage < min {
+			return fmt.Errorf("allocated storage must be at least %d GiB for %s storage", min, storageType)
+		}
+	}
+
+	if s.AllocatedStorage > limits.maxStorage {
+		return fmt.Errorf("allocated storage can be at most %d GiB", limits.maxStorage)
+	}
+
+	if s.MaxAllocatedStorage > 0 {
+		if s.MaxAllocatedStorage < s.AllocatedStorage {
+			return fmt.Errorf("maximum allocated storage %d GiB is less than the allocated storage %d GiB", s.MaxAllocatedStorage, s.AllocatedStorage)
+		}
+		if s.MaxAllocatedStorage > limits.maxStorage {
+			return fmt.Errorf("maximum allocated storage can be at most %d GiB", limits.maxStorage)
+		}
+	}
+
+	switch storageType {
+	case StorageTypeGP2, "":
+		if s.Iops > 0 || s.StorageThroughput > 0 {
+			return fmt.Errorf("IOPS and storage throughput can't be set for gp2 storage")
+		}
+	case StorageTypeGP3:
+		if s.Iops == 0 && s.StorageThroughput == 0 {
+			return nil
+		}
+		if s.AllocatedStorage < limits.gp3Threshold {
+			return fmt.Errorf("IOPS and storage throughput can only be set for gp3 storage of at least %d GiB", limits.gp3Threshold)
+		}
+		if s.Iops > 0 && (s.Iops < limits.gp3MinIops || s.Iops > limits.gp3MaxIops) {
+			return fmt.Errorf("IOPS must be between %d and %d for gp3 storage", limits.gp3MinIops, limits.gp3MaxIops)
+		}
+		if s.StorageThroughput > 0 && (s.StorageThroughput < limits.gp3MinThroughput || s.StorageThroughput > limits.gp3MaxThroughput) {
+			return fmt.Errorf("storage throughput must be between %d and %d MiB/s for gp3 storage", limits.gp3MinThroughput, limits.gp3MaxThroughput)
+		}
+	case StorageTypeIO1, StorageTypeIO2:
+		if s.StorageThroughput > 0 {
+			return fmt.Errorf("storage throughput can't be set for %s storage", storageType)
+		}
+		if s.Iops < 1000 || s.Iops > 256000 {
+			return fmt.Errorf("IOPS must be between 1000 and 256000 for %s storage", storageType)
+		}
+
+		// the ratio of IOPS to allocated storage is limited
+		maxRatio := int64(50)
+		if storageType == StorageTypeIO2 {
+			maxRatio = 1000
+		}
+		if s.AllocatedStorage > 0 && (s.Iops*2 < s.AllocatedStorage || s.Iops > s.AllocatedStorage*maxRatio) {
+			return fmt.Errorf("IOPS must be between 0.5 and %d times the allocated storage for %s storage", maxRatio, storageType)
+		}
+	}
+
+	return nil
+}
+
+// ValidateStorageChange checks a change of the storage configuration of an existing database instance with the
+// given engine and current storage.  Storage can never shrink, and the change is validated like new storage,
+// with the current configuration filled in for anything that isn't changed.
+func ValidateStorageChange(engine string, current, change Storage) error {
+	if change.IsZero() {
+		return nil
+	}
+
+	if change.AllocatedStorage > 0 && change.AllocatedStorage < current.AllocatedStorage {
+		return fmt.Errorf("allocated storage can't shrink from %d GiB to %d GiB", current.AllocatedStorage, change.AllocatedStorage)
+	}
+
+	merged := change
+	if merged.StorageType == "" {
+		merged.StorageType = current.StorageType
+	}
+	if merged.AllocatedStorage == 0 {
+		merged.AllocatedStorage = current.AllocatedStorage
+	}
+	if merged.MaxAllocatedStorage == 0 {
+		merged.MaxAllocatedStorage = current.MaxAllocatedStorage
+	}
+
+	// provisioned IOPS carry over between io1 and io2, gp3 reports its baseline IOPS so they don't carry over
+	if merged.Iops == 0 && isProvisionedIops(merged.StorageType) && isProvisionedIops(current.StorageType) {
+		merged.Iops = current.Iops
+	}
+
+	return ValidateStorage(engine, merged)
+}
+
+// ValidateClusterStorageType checks the storage type of a database cluster with the given engine.  The engine can
+// be empty when it's not known, eg. when restoring from a snapshot.
+func ValidateClusterStorageType(engine, storageType string) error {
+	if storageType == "" {
+		return nil
+	}
+
+	aurora := storageType == StorageTypeAurora || storageType == StorageTypeAuroraIOOptimized
+	switch {
+	case engine == "":
+		if aurora || storageType == StorageTypeGP3 || isProvisionedIops(storageType) {
+			return nil
+		}
+	case strings.HasPrefix(engine, "aurora"):
+		if aurora {
+			return nil
+		}
+		return fmt.Errorf("invalid storage type %s for %s clusters, must be aurora or aurora-iopt1", storageType, engine)
+	default:
+		// Multi-AZ database clusters
+		if storageType == StorageTypeGP3 || isProvisionedIops(storageType) {
+			return nil
+		}
+		return fmt.Errorf("invalid storage type %s for %s clusters, must be gp3, io1 or io2", storageType, engine)
+	}
+
+	return fmt.Errorf("invalid storage type %s", storageType)
+}
+
+// SetMaxAllocatedStorage immediately sets the maximum allocated storage of the RDS database instance with the given
+// identifier, which enables storage autoscaling up to that size
+func (r *Client) SetMaxAllocatedStorage(ctx aws.Context, id string, max int64) error {
+	if id == "" {
+		return errors.New("database identifier cannot be empty")
+	}
+
+	log.Printf("Setting maximum allocated storage of database instance %s to %d GiB", id, max)
+
+	_, err := r.Service.ModifyDBInstanceWithContext(ctx, &rds.ModifyDBInstanceInput{
+		ApplyImmediately:     aws.Bool(true),
+		DBInstanceIdentifier: aws.String(id),
+		MaxAllocatedStorage:  aws.Int64(max),
+	})
+	return err
+}
+
+// isProvisionedIops returns true if the given storage type is provisioned IOPS storage
+func isProvisionedIops(storageType string) bool {
+	return storageType == StorageTypeIO1 || storageType == StorageTypeIO2
+}
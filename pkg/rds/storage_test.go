@@ -0,0 +1,125 @@
+package rds
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateStorage(t *testing.T) {
+	tests := []struct {
+		name    string
+		engine  string
+		storage Storage
+		wantErr bool
+	}{
+		{name: "empty", engine: "postgres"},
+		{name: "gp2", engine: "postgres", storage: Storage{StorageType: "gp2", AllocatedStorage: 20}},
+		{name: "gp2 too small", engine: "postgres", storage: Storage{StorageType: "gp2", AllocatedStorage: 10}, wantErr: true},
+		{name: "gp2 with iops", engine: "postgres", storage: Storage{StorageType: "gp2", AllocatedStorage: 100, StorageThroughput: 500}, wantErr: true},
+		{name: "gp3 baseline", engine: "postgres", storage: Storage{StorageType: "gp3", AllocatedStorage: 100}},
+		{name: "gp3 provisioned", engine: "postgres", storage: Storage{StorageType: "gp3", AllocatedStorage: 400, Iops: 12000, StorageThroughput: 500}},
+		{name: "gp3 provisioned too small", engine: "postgres", storage: Storage{StorageType: "gp3", AllocatedStorage: 300, Iops: 12000}, wantErr: true},
+		{name: "gp3 oracle threshold", engine: "oracle-ee", storage: Storage{StorageType: "gp3", AllocatedStorage: 200, Iops: 12000}},
+		{name: "gp3 sqlserver", engine: "sqlserver-se", storage: Storage{StorageType: "gp3", AllocatedStorage: 20, Iops: 3000, StorageThroughput: 125}},
+		{name: "gp3 iops too low", engine: "postgres", storage: Storage{StorageType: "gp3", AllocatedStorage: 500, Iops: 3000}, wantErr: true},
+		{name: "gp3 throughput too high", engine: "mysql", storage: Storage{StorageType: "gp3", AllocatedStorage: 500, StorageThroughput: 5000}, wantErr: true},
+		{name: "io1", engine: "postgres", storage: Storage{StorageType: "io1", AllocatedStorage: 100, Iops: 3000}},
+		{name: "io1 default type", engine: "postgres", storage: Storage{AllocatedStorage: 100, Iops: 3000}},
+		{name: "io1 too small", engine: "postgres", storage: Storage{StorageType: "io1", AllocatedStorage: 50, Iops: 1000}, wantErr: true},
+		{name: "io1 sqlserver small", engine: "sqlserver-ex", storage: Storage{StorageType: "io1", AllocatedStorage: 20, Iops: 1000}},
+		{name: "io1 missing iops", engine: "postgres", storage: Storage{StorageType: "io1", AllocatedStorage: 100}, wantErr: true},
+		{name: "io1 ratio too high", engine: "postgres", storage: Storage{StorageType: "io1", AllocatedStorage: 100, Iops: 10000}, wantErr: true},
+		{name: "io2 ratio", engine: "postgres", storage: Storage{StorageType: "io2", AllocatedStorage: 100, Iops: 10000}},
+		{name: "io2 throughput", engine: "postgres", storage: Storage{StorageType: "io2", AllocatedStorage: 100, Iops: 10000, StorageThroughput: 500}, wantErr: true},
+		{name: "invalid type", engine: "postgres", storage: Storage{StorageType: "standard", AllocatedStorage: 100}, wantErr: true},
+		{name: "autoscaling", engine: "postgres", storage: Storage{StorageType: "gp3", AllocatedStorage: 100, MaxAllocatedStorage: 1000}},
+		{name: "autoscaling below allocated", engine: "postgres", storage: Storage{StorageType: "gp3", AllocatedStorage: 100, MaxAllocatedStorage: 50}, wantErr: true},
+		{name: "autoscaling too large", engine: "sqlserver-se", storage: Storage{StorageType: "gp3", AllocatedStorage: 100, MaxAllocatedStorage: 20000}, wantErr: true},
+		{name: "aurora instance", engine: "aurora-postgresql", storage: Storage{StorageType: "gp3"}, wantErr: true},
+		{name: "unknown engine", storage: Storage{StorageType: "gp3", AllocatedStorage: 100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateStorage(tt.engine, tt.storage); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStorage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateStorageChange(t *testing.T) {
+	gp2 := Storage{StorageType: "gp2", AllocatedStorage: 100}
+	gp3 := Storage{StorageType: "gp3", AllocatedStorage: 100, Iops: 3000, StorageThroughput: 125}
+	io1 := Storage{StorageType: "io1", AllocatedStorage: 200, Iops: 5000, MaxAllocatedStorage: 500}
+
+	tests := []struct {
+		name    string
+		current Storage
+		change  Storage
+		wantErr bool
+	}{
+		{name: "no change", current: gp2},
+		{name: "grow", current: gp2, change: Storage{AllocatedStorage: 200}},
+		{name: "shrink", current: gp2, change: Storage{AllocatedStorage: 50}, wantErr: true},
+		{name: "gp2 to gp3", current: gp2, change: Storage{StorageType: "gp3"}},
+		{name: "gp3 baseline iops don't carry over", current: gp3, change: Storage{AllocatedStorage: 200}},
+		{name: "gp3 provisioned too small", current: gp3, change: Storage{Iops: 12000}, wantErr: true},
+		{name: "io1 to io2 keeps iops", current: io1, change: Storage{StorageType: "io2"}},
+		{name: "io1 to gp3 drops iops", current: io1, change: Storage{StorageType: "gp3"}},
+		{name: "grow past autoscaling limit", current: io1, change: Storage{AllocatedStorage: 600}, wantErr: true},
+		{name: "grow with autoscaling limit", current: io1, change: Storage{AllocatedStorage: 600, MaxAllocatedStorage: 1000}},
+		{name: "gp2 to io1 without iops", current: gp2, change: Storage{StorageType: "io1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateStorageChange("postgres", tt.current, tt.change); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStorageChange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateClusterStorageType(t *testing.T) {
+	tests := []struct {
+		engine      string
+		storageType string
+		wantErr     bool
+	}{
+		{engine: "aurora-postgresql", storageType: ""},
+		{engine: "aurora-postgresql", storageType: "aurora"},
+		{engine: "aurora-mysql", storageType: "aurora-iopt1"},
+		{engine: "aurora-mysql", storageType: "gp3", wantErr: true},
+		{engine: "postgres", storageType: "io1"},
+		{engine: "postgres", storageType: "aurora-iopt1", wantErr: true},
+		{engine: "", storageType: "aurora-iopt1"},
+		{engine: "", storageType: "gp2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.engine+"/"+tt.storageType, func(t *testing.T) {
+			if err := ValidateClusterStorageType(tt.engine, tt.storageType); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateClusterStorageType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_SetMaxAllocatedStorage(t *testing.T) {
+	mock := &mockDescribeClient{}
+	mc := Client{Service: mock}
+
+	if err := mc.SetMaxAllocatedStorage(ctx, "instance", 1000); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	expected := []string{"instance/instance"}
+	if !reflect.DeepEqual(mock.modified, expected) {
+		t.Errorf("expected %v to be modified, got %v", expected, mock.modified)
+	}
+
+	if err := mc.SetMaxAllocatedStorage(ctx, "", 1000); err == nil {
+		t.Error("expected error for empty identifier, got nil")
+	}
+}
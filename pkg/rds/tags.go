@@ -2,9 +2,12 @@ package rds
 
 import (
 	"errors"
+	"fmt"
 	"log"
 
+	"github.com/YaleSpinup/apierror"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/rds"
 )
 
@@ -44,3 +47,150 @@ func (cl Client) DetermineArn(dbName string) ([]string, error) {
 
 	return arns, nil
 }
+
+// DatabaseTags returns the tags for the RDS cluster and/or instance with the given name, keyed by ARN
+// It returns a not found error if neither a cluster nor an instance with the given name exist
+func (cl Client) DatabaseTags(ctx aws.Context, dbName string) (map[string][]*rds.Tag, error) {
+	tags := map[string][]*rds.Tag{}
+
+	clustersOutput, err := cl.Service.DescribeDBClustersWithContext(ctx, &rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(dbName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != rds.ErrCodeDBClusterNotFoundFault {
+			return nil, err
+		}
+	} else {
+		for _, c := range clustersOutput.DBClusters {
+			tags[aws.StringValue(c.DBClusterArn)] = c.TagList
+		}
+	}
+
+	instancesOutput, err := cl.Service.DescribeDBInstancesWithContext(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(dbName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != rds.ErrCodeDBInstanceNotFoundFault {
+			return nil, err
+		}
+	} else {
+		for _, i := range instancesOutput.DBInstances {
+			tags[aws.StringValue(i.DBInstanceArn)] = i.TagList
+		}
+	}
+
+	if len(tags) == 0 {
+		msg := fmt.Sprintf("database %s not found", dbName)
+		return nil, apierror.New(apierror.ErrNotFound, msg, nil)
+	}
+
+	return tags, nil
+}
+
+// SnapshotTags returns the tags for the RDS cluster and/or instance snapshot with the given identifier, keyed by ARN
+// It returns a not found error if neither a cluster nor an instance snapshot with the given identifier exist
+func (cl Client) SnapshotTags(ctx aws.Context, snapshotId string) (map[string][]*rds.Tag, error) {
+	tags := map[string][]*rds.Tag{}
+
+	clusterSnapshotsOutput, err := cl.Service.DescribeDBClusterSnapshotsWithContext(ctx, &rds.DescribeDBClusterSnapshotsInput{
+		DBClusterSnapshotIdentifier: aws.String(snapshotId),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != rds.ErrCodeDBClusterSnapshotNotFoundFault {
+			return nil, err
+		}
+	} else {
+		for _, s := range clusterSnapshotsOutput.DBClusterSnapshots {
+			tags[aws.StringValue(s.DBClusterSnapshotArn)] = s.TagList
+		}
+	}
+
+	instanceSnapshotsOutput, err := cl.Service.DescribeDBSnapshotsWithContext(ctx, &rds.DescribeDBSnapshotsInput{
+		DBSnapshotIdentifier: aws.String(snapshotId),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != rds.ErrCodeDBSnapshotNotFoundFault {
+			return nil, err
+		}
+	} else {
+		for _, s := range instanceSnapshotsOutput.DBSnapshots {
+			tags[aws.StringValue(s.DBSnapshotArn)] = s.TagList
+		}
+	}
+
+	if len(tags) == 0 {
+		msg := fmt.Sprintf("snapshot %s not found", snapshotId)
+		return nil, apierror.New(apierror.ErrNotFound, msg, nil)
+	}
+
+	return tags, nil
+}
+
+// TagDatabase adds the given tags to the RDS cluster or instance with the given identifier
+func (cl Client) TagDatabase(ctx aws.Context, id string, cluster bool, tags []*rds.Tag) error {
+	arn, err := cl.databaseArn(ctx, id, cluster)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("tagging database %s", arn)
+
+	_, err = cl.Service.AddTagsToResourceWithContext(ctx, &rds.AddTagsToResourceInput{
+		ResourceName: aws.String(arn),
+		Tags:         tags,
+	})
+	return err
+}
+
+// UntagDatabase removes the tags with the given keys from the RDS cluster or instance with the given identifier
+func (cl Client) UntagDatabase(ctx aws.Context, id string, cluster bool, keys []string) error {
+	arn, err := cl.databaseArn(ctx, id, cluster)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("removing tags %v from database %s", keys, arn)
+
+	_, err = cl.Service.RemoveTagsFromResourceWithContext(ctx, &rds.RemoveTagsFromResourceInput{
+		ResourceName: aws.String(arn),
+		TagKeys:      aws.StringSlice(keys),
+	})
+	return err
+}
+
+// databaseArn returns the ARN of the RDS cluster or instance with the given identifier
+func (cl Client) databaseArn(ctx aws.Context, id string, cluster bool) (string, error) {
+	if id == "" {
+		return "", errors.New("database identifier cannot be empty")
+	}
+
+	if cluster {
+		out, err := cl.Service.DescribeDBClustersWithContext(ctx, &rds.DescribeDBClustersInput{
+			DBClusterIdentifier: aws.String(id),
+		})
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == rds.ErrCodeDBClusterNotFoundFault {
+				return "", apierror.New(apierror.ErrNotFound, fmt.Sprintf("database cluster %s not found", id), err)
+			}
+			return "", err
+		}
+		if len(out.DBClusters) != 1 {
+			return "", apierror.New(apierror.ErrNotFound, fmt.Sprintf("database cluster %s not found", id), nil)
+		}
+		return aws.StringValue(out.DBClusters[0].DBClusterArn), nil
+	}
+
+	out, err := cl.Service.DescribeDBInstancesWithContext(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(id),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == rds.ErrCodeDBInstanceNotFoundFault {
+			return "", apierror.New(apierror.ErrNotFound, fmt.Sprintf("database instance %s not found", id), err)
+		}
+		return "", err
+	}
+	if len(out.DBInstances) != 1 {
+		return "", apierror.New(apierror.ErrNotFound, fmt.Sprintf("database instance %s not found", id), nil)
+	}
+	return aws.StringValue(out.DBInstances[0].DBInstanceArn), nil
+}
@@ -1,9 +1,13 @@
 package rds
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/YaleSpinup/apierror"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 )
@@ -82,3 +86,141 @@ func TestDetermineArn(t *testing.T) {
 		t.Fatalf("Expected error, got: nil")
 	}
 }
+
+// mockTagsClient returns the tags for clusters and instances
+type mockTagsClient struct {
+	rdsiface.RDSAPI
+	clusters  map[string][]*rds.Tag
+	instances map[string][]*rds.Tag
+}
+
+func (m *mockTagsClient) DescribeDBClustersWithContext(_ aws.Context, input *rds.DescribeDBClustersInput, _ ...request.Option) (*rds.DescribeDBClustersOutput, error) {
+	id := aws.StringValue(input.DBClusterIdentifier)
+	tags, ok := m.clusters[id]
+	if !ok {
+		return nil, awserr.New(rds.ErrCodeDBClusterNotFoundFault, "not found", nil)
+	}
+	return &rds.DescribeDBClustersOutput{
+		DBClusters: []*rds.DBCluster{{DBClusterArn: aws.String("arn:aws:rds:us-east-1:123456789012:cluster:" + id), TagList: tags}},
+	}, nil
+}
+
+func (m *mockTagsClient) DescribeDBInstancesWithContext(_ aws.Context, input *rds.DescribeDBInstancesInput, _ ...request.Option) (*rds.DescribeDBInstancesOutput, error) {
+	id := aws.StringValue(input.DBInstanceIdentifier)
+	tags, ok := m.instances[id]
+	if !ok {
+		return nil, awserr.New(rds.ErrCodeDBInstanceNotFoundFault, "not found", nil)
+	}
+	return &rds.DescribeDBInstancesOutput{
+		DBInstances: []*rds.DBInstance{{DBInstanceArn: aws.String("arn:aws:rds:us-east-1:123456789012:db:" + id), TagList: tags}},
+	}, nil
+}
+
+func TestDatabaseTags(t *testing.T) {
+	orgTags := []*rds.Tag{{Key: aws.String("spinup:org"), Value: aws.String("localdev")}}
+	mc := Client{
+		Service: &mockTagsClient{
+			clusters:  map[string][]*rds.Tag{"cluster": orgTags, "both": orgTags},
+			instances: map[string][]*rds.Tag{"instance": orgTags, "both": orgTags},
+		},
+	}
+
+	for db, want := range map[string]int{"cluster": 1, "instance": 1, "both": 2} {
+		got, err := mc.DatabaseTags(ctx, db)
+		if err != nil {
+			t.Fatalf("Expected error nil for %s, got: %v", db, err)
+		}
+		if len(got) != want {
+			t.Errorf("Expected %d tagged resources for %s, got: %d", want, db, len(got))
+		}
+	}
+
+	_, err := mc.DatabaseTags(ctx, "unknown")
+	if aerr, ok := err.(apierror.Error); !ok || aerr.Code != apierror.ErrNotFound {
+		t.Errorf("Expected not found error, got: %v", err)
+	}
+}
+
+// mockDatabaseTagsClient describes clusters and instances and records the tagged resources
+type mockDatabaseTagsClient struct {
+	mockTagsClient
+	tagged   map[string][]*rds.Tag
+	untagged map[string][]string
+}
+
+func (m *mockDatabaseTagsClient) RemoveTagsFromResourceWithContext(_ aws.Context, input *rds.RemoveTagsFromResourceInput, _ ...request.Option) (*rds.RemoveTagsFromResourceOutput, error) {
+	m.untagged[aws.StringValue(input.ResourceName)] = aws.StringValueSlice(input.TagKeys)
+	return &rds.RemoveTagsFromResourceOutput{}, nil
+}
+
+func (m *mockDatabaseTagsClient) AddTagsToResourceWithContext(_ aws.Context, input *rds.AddTagsToResourceInput, _ ...request.Option) (*rds.AddTagsToResourceOutput, error) {
+	m.tagged[aws.StringValue(input.ResourceName)] = input.Tags
+	return &rds.AddTagsToResourceOutput{}, nil
+}
+
+func TestTagDatabase(t *testing.T) {
+	mock := &mockDatabaseTagsClient{
+		mockTagsClient: mockTagsClient{
+			clusters:  map[string][]*rds.Tag{"mycluster": {}},
+			instances: map[string][]*rds.Tag{"mydb": {}},
+		},
+		tagged: map[string][]*rds.Tag{},
+	}
+	mc := Client{Service: mock}
+
+	tags := []*rds.Tag{{Key: aws.String("spinup:final-snapshot"), Value: aws.String("mydb")}}
+	if err := mc.TagDatabase(ctx, "mydb", false, tags); err != nil {
+		t.Fatalf("Expected error nil, got: %v", err)
+	}
+	if err := mc.TagDatabase(ctx, "mycluster", true, tags); err != nil {
+		t.Fatalf("Expected error nil, got: %v", err)
+	}
+
+	for _, arn := range []string{"arn:aws:rds:us-east-1:123456789012:db:mydb", "arn:aws:rds:us-east-1:123456789012:cluster:mycluster"} {
+		if got, ok := mock.tagged[arn]; !ok || len(got) != 1 || aws.StringValue(got[0].Key) != "spinup:final-snapshot" {
+			t.Errorf("Expected %s to be tagged, got: %v", arn, mock.tagged)
+		}
+	}
+
+	// an instance isn't tagged as a cluster
+	err := mc.TagDatabase(ctx, "mydb", true, tags)
+	if aerr, ok := err.(apierror.Error); !ok || aerr.Code != apierror.ErrNotFound {
+		t.Errorf("Expected not found error, got: %v", err)
+	}
+
+	if err := mc.TagDatabase(ctx, "", false, tags); err == nil {
+		t.Error("Expected error for empty database identifier, got: nil")
+	}
+}
+
+func TestUntagDatabase(t *testing.T) {
+	mock := &mockDatabaseTagsClient{
+		mockTagsClient: mockTagsClient{
+			clusters:  map[string][]*rds.Tag{"mycluster": {}},
+			instances: map[string][]*rds.Tag{"mydb": {}},
+		},
+		untagged: map[string][]string{},
+	}
+	mc := Client{Service: mock}
+
+	keys := []string{"spinup:final-snapshot"}
+	if err := mc.UntagDatabase(ctx, "mydb", false, keys); err != nil {
+		t.Fatalf("Expected error nil, got: %v", err)
+	}
+	if err := mc.UntagDatabase(ctx, "mycluster", true, keys); err != nil {
+		t.Fatalf("Expected error nil, got: %v", err)
+	}
+
+	expected := map[string][]string{
+		"arn:aws:rds:us-east-1:123456789012:db:mydb":           keys,
+		"arn:aws:rds:us-east-1:123456789012:cluster:mycluster": keys,
+	}
+	if !reflect.DeepEqual(mock.untagged, expected) {
+		t.Errorf("Expected %v to be untagged, got: %v", expected, mock.untagged)
+	}
+
+	err := mc.UntagDatabase(ctx, "missing", false, keys)
+	if aerr, ok := err.(apierror.Error); !ok || aerr.Code != apierror.ErrNotFound {
+		t.Errorf("Expected not found error, got: %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+package rds
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/YaleSpinup/apierror"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// UpgradeInput is the input for upgrading the engine version of a database cluster or instance
+type UpgradeInput struct {
+	// Identifier is the database cluster or instance identifier
+	Identifier string
+	// Cluster is true if Identifier is a database cluster
+	Cluster bool
+	// EngineVersion is the target engine version
+	EngineVersion string
+	// AllowMajorVersionUpgrade has to be set if the target is a major version upgrade
+	AllowMajorVersionUpgrade bool
+	// DBClusterParameterGroupName is the parameter group for the cluster in the new version (optional)
+	DBClusterParameterGroupName string
+	// DBParameterGroupName is the parameter group for the instance, or the cluster members, in the new version (optional)
+	DBParameterGroupName string
+}
+
+// ValidUpgradeTarget returns the upgrade target for the given engine version, if it's a valid upgrade target
+// of the current version of the given engine.  It returns a BadRequest error if it's not.
+func (r *Client) ValidUpgradeTarget(ctx aws.Context, engine, currentVersion, targetVersion string) (*rds.UpgradeTarget, error) {
+	if engine == "" || currentVersion == "" || targetVersion == "" {
+		return nil, errors.New("engine, current and target version cannot be empty")
+	}
+
+	out, err := r.Service.DescribeDBEngineVersionsWithContext(ctx, &rds.DescribeDBEngineVersionsInput{
+		Engine:        aws.String(engine),
+		EngineVersion: aws.String(currentVersion),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(out.DBEngineVersions) == 0 {
+		msg := fmt.Sprintf("engine version %s %s not found", engine, currentVersion)
+		return nil, apierror.New(apierror.ErrNotFound, msg, nil)
+	}
+
+	for _, t := range out.DBEngineVersions[0].ValidUpgradeTarget {
+		if aws.StringValue(t.EngineVersion) == targetVersion {
+			return t, nil
+		}
+	}
+
+	msg := fmt.Sprintf("%s %s is not a valid upgrade target of version %s", engine, targetVersion, currentVersion)
+	return nil, apierror.New(apierror.ErrBadRequest, msg, nil)
+}
+
+// UpgradeDatabase immediately upgrades the engine version of an RDS database cluster or instance, switching
+// to the given parameter groups.  For a cluster, the instance parameter group is applied to all of its members,
+// which is only supported for major version upgrades.
+func (r *Client) UpgradeDatabase(ctx aws.Context, input *UpgradeInput) error {
+	if input == nil || input.Identifier == "" || input.EngineVersion == "" {
+		return errors.New("database identifier and engine version cannot be empty")
+	}
+
+	log.Printf("Upgrading database with identifier %s to engine version %s (major: %t)", input.Identifier, input.EngineVersion, input.AllowMajorVersionUpgrade)
+
+	if input.Cluster {
+		clusterInput := &rds.ModifyDBClusterInput{
+			AllowMajorVersionUpgrade: aws.Bool(input.AllowMajorVersionUpgrade),
+			ApplyImmediately:         aws.Bool(true),
+			DBClusterIdentifier:      aws.String(input.Identifier),
+			EngineVersion:            aws.String(input.EngineVersion),
+		}
+		if input.DBClusterParameterGroupName != "" {
+			clusterInput.DBClusterParameterGroupName = aws.String(input.DBClusterParameterGroupName)
+		}
+		if input.DBParameterGroupName != "" && input.AllowMajorVersionUpgrade {
+			clusterInput.DBInstanceParameterGroupName = aws.String(input.DBParameterGroupName)
+		}
+
+		_, err := r.Service.ModifyDBClusterWithContext(ctx, clusterInput)
+		return err
+	}
+
+	instanceInput := &rds.ModifyDBInstanceInput{
+		AllowMajorVersionUpgrade: aws.Bool(input.AllowMajorVersionUpgrade),
+		ApplyImmediately:         aws.Bool(true),
+		DBInstanceIdentifier:     aws.String(input.Identifier),
+		EngineVersion:            aws.String(input.EngineVersion),
+	}
+	if input.DBParameterGroupName != "" {
+		instanceInput.DBParameterGroupName = aws.String(input.DBParameterGroupName)
+	}
+
+	_, err := r.Service.ModifyDBInstanceWithContext(ctx, instanceInput)
+	return err
+}
+
+// UpgradePath is the shortest sequence of upgrades from the current engine version to the target version
+type UpgradePath struct {
+	Engine         string
+	CurrentVersion string
+	TargetVersion  string
+	Steps          []*UpgradeStep
+}
+
+// UpgradeStep is a single upgrade in an UpgradePath, with the parameter group family required by its engine version
+type UpgradeStep struct {
+	EngineVersion          string
+	IsMajorVersionUpgrade  bool
+	DBParameterGroupFamily string
+}
+
+// PlanUpgradePath returns the shortest path of valid upgrades from the current engine version to the target version,
+// e.g. postgres 10.21 -> 14.10 -> 16.1.  Deprecated versions are included, since databases and snapshots can still be
+// on them.  It returns a NotFound error if either version is unknown and a BadRequest error if there's no path.
+func (r *Client) PlanUpgradePath(ctx aws.Context, engine, currentVersion, targetVersion string) (*UpgradePath, error) {
+	if engine == "" || currentVersion == "" || targetVersion == "" {
+		return nil, errors.New("engine, current and target version cannot be empty")
+	}
+
+	versions := map[string]*rds.DBEngineVersion{}
+	if err := r.Service.DescribeDBEngineVersionsPagesWithContext(ctx, &rds.DescribeDBEngineVersionsInput{
+		Engine:     aws.String(engine),
+		IncludeAll: aws.Bool(true),
+	}, func(out *rds.DescribeDBEngineVersionsOutput, lastPage bool) bool {
+		for _, v := range out.DBEngineVersions {
+			versions[aws.StringValue(v.EngineVersion)] = v
+		}
+		return true
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, v := range []string{currentVersion, targetVersion} {
+		if _, ok := versions[v]; !ok {
+			msg := fmt.Sprintf("engine version %s %s not found", engine, v)
+			return nil, apierror.New(apierror.ErrNotFound, msg, nil)
+		}
+	}
+
+	steps, ok := planUpgradePath(versions, currentVersion, targetVersion)
+	if !ok {
+		msg := fmt.Sprintf("there is no upgrade path from %s %s to %s", engine, currentVersion, targetVersion)
+		return nil, apierror.New(apierror.ErrBadRequest, msg, nil)
+	}
+
+	return &UpgradePath{
+		Engine:         engine,
+		CurrentVersion: currentVersion,
+		TargetVersion:  targetVersion,
+		Steps:          steps,
+	}, nil
+}
+
+// planUpgradePath does a breadth first search of the ValidUpgradeTarget graph of the given engine versions for the
+// shortest path from the current to the target version.  The upgrade targets of a version are visited from the latest
+// to the oldest, so of the paths with the fewest upgrades the one through the latest versions is returned.
+func planUpgradePath(versions map[string]*rds.DBEngineVersion, current, target string) ([]*UpgradeStep, bool) {
+	if current == target {
+		return []*UpgradeStep{}, true
+	}
+
+	// previous holds the upgrade target that led to each visited version
+	previous := map[string]*rds.UpgradeTarget{current: nil}
+	from := map[string]string{}
+	queue := []string{current}
+
+	for len(queue) > 0 {
+		version := queue[0]
+		queue = queue[1:]
+
+		v, ok := versions[version]
+		if !ok {
+			continue
+		}
+
+		for i := len(v.ValidUpgradeTarget) - 1; i >= 0; i-- {
+			t := v.ValidUpgradeTarget[i]
+			next := aws.StringValue(t.EngineVersion)
+			if _, seen := previous[next]; seen {
+				continue
+			}
+			previous[next] = t
+			from[next] = version
+
+			if next == target {
+				return upgradeSteps(versions, previous, from, current, target), true
+			}
+
+			queue = append(queue, next)
+		}
+	}
+
+	return nil, false
+}
+
+// upgradeSteps walks back from the target to the current version and returns the upgrade steps in order
+func upgradeSteps(versions map[string]*rds.DBEngineVersion, previous map[string]*rds.UpgradeTarget, from map[string]string, current, target string) []*UpgradeStep {
+	steps := []*UpgradeStep{}
+	for version := target; version != current; version = from[version] {
+		step := &UpgradeStep{
+			EngineVersion:         version,
+			IsMajorVersionUpgrade: aws.BoolValue(previous[version].IsMajorVersionUpgrade),
+		}
+		if v, ok := versions[version]; ok {
+			step.DBParameterGroupFamily = aws.StringValue(v.DBParameterGroupFamily)
+		}
+		steps = append([]*UpgradeStep{step}, steps...)
+	}
+	return steps
+}
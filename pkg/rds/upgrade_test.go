@@ -0,0 +1,230 @@
+package rds
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/YaleSpinup/apierror"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+// mockUpgradeClient is a fake rds client with engine versions, keyed by version
+type mockUpgradeClient struct {
+	rdsiface.RDSAPI
+	versions         map[string]*rds.DBEngineVersion
+	modifiedCluster  *rds.ModifyDBClusterInput
+	modifiedInstance *rds.ModifyDBInstanceInput
+}
+
+func (m *mockUpgradeClient) DescribeDBEngineVersionsWithContext(_ aws.Context, input *rds.DescribeDBEngineVersionsInput, _ ...request.Option) (*rds.DescribeDBEngineVersionsOutput, error) {
+	out := &rds.DescribeDBEngineVersionsOutput{DBEngineVersions: []*rds.DBEngineVersion{}}
+	if v, ok := m.versions[aws.StringValue(input.EngineVersion)]; ok {
+		out.DBEngineVersions = append(out.DBEngineVersions, v)
+	}
+	return out, nil
+}
+
+func (m *mockUpgradeClient) ModifyDBClusterWithContext(_ aws.Context, input *rds.ModifyDBClusterInput, _ ...request.Option) (*rds.ModifyDBClusterOutput, error) {
+	m.modifiedCluster = input
+	return &rds.ModifyDBClusterOutput{}, nil
+}
+
+func (m *mockUpgradeClient) ModifyDBInstanceWithContext(_ aws.Context, input *rds.ModifyDBInstanceInput, _ ...request.Option) (*rds.ModifyDBInstanceOutput, error) {
+	m.modifiedInstance = input
+	return &rds.ModifyDBInstanceOutput{}, nil
+}
+
+// engineVersion returns a postgres engine version with the given valid upgrade targets
+func engineVersion(version, family string, targets ...*rds.UpgradeTarget) *rds.DBEngineVersion {
+	return &rds.DBEngineVersion{
+		DBParameterGroupFamily: aws.String(family),
+		Engine:                 aws.String("postgres"),
+		EngineVersion:          aws.String(version),
+		ValidUpgradeTarget:     targets,
+	}
+}
+
+// upgradeTarget returns a postgres upgrade target
+func upgradeTarget(version string, major bool) *rds.UpgradeTarget {
+	return &rds.UpgradeTarget{
+		Engine:                aws.String("postgres"),
+		EngineVersion:         aws.String(version),
+		IsMajorVersionUpgrade: aws.Bool(major),
+	}
+}
+
+func TestClient_ValidUpgradeTarget(t *testing.T) {
+	mc := Client{
+		Service: &mockUpgradeClient{
+			versions: map[string]*rds.DBEngineVersion{
+				"13.4": engineVersion("13.4", "postgres13", upgradeTarget("13.7", false), upgradeTarget("14.3", true)),
+			},
+		},
+	}
+
+	target, err := mc.ValidUpgradeTarget(ctx, "postgres", "13.4", "14.3")
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if !aws.BoolValue(target.IsMajorVersionUpgrade) {
+		t.Error("expected 14.3 to be a major version upgrade")
+	}
+
+	tests := []struct {
+		current string
+		target  string
+		code    string
+	}{
+		{"13.4", "15.2", apierror.ErrBadRequest},
+		{"12.1", "13.4", apierror.ErrNotFound},
+	}
+
+	for _, test := range tests {
+		_, err := mc.ValidUpgradeTarget(ctx, "postgres", test.current, test.target)
+		if aerr, ok := err.(apierror.Error); !ok || aerr.Code != test.code {
+			t.Errorf("expected %s error upgrading %s to %s, got %v", test.code, test.current, test.target, err)
+		}
+	}
+
+	if _, err := mc.ValidUpgradeTarget(ctx, "postgres", "", "14.3"); err == nil {
+		t.Error("expected error for empty current version, got nil")
+	}
+}
+
+func TestClient_UpgradeDatabase(t *testing.T) {
+	mock := &mockUpgradeClient{}
+	mc := Client{Service: mock}
+
+	err := mc.UpgradeDatabase(ctx, &UpgradeInput{
+		Identifier:                  "cluster",
+		Cluster:                     true,
+		EngineVersion:               "14.3",
+		AllowMajorVersionUpgrade:    true,
+		DBClusterParameterGroupName: "cluster-pg14",
+		DBParameterGroupName:        "pg14",
+	})
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	expectedCluster := &rds.ModifyDBClusterInput{
+		AllowMajorVersionUpgrade:     aws.Bool(true),
+		ApplyImmediately:             aws.Bool(true),
+		DBClusterIdentifier:          aws.String("cluster"),
+		DBClusterParameterGroupName:  aws.String("cluster-pg14"),
+		DBInstanceParameterGroupName: aws.String("pg14"),
+		EngineVersion:                aws.String("14.3"),
+	}
+	if mock.modifiedCluster.String() != expectedCluster.String() {
+		t.Errorf("expected %s, got %s", expectedCluster, mock.modifiedCluster)
+	}
+
+	// the instance parameter group isn't set for minor cluster upgrades
+	if err := mc.UpgradeDatabase(ctx, &UpgradeInput{Identifier: "cluster", Cluster: true, EngineVersion: "13.7", DBParameterGroupName: "pg13"}); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if mock.modifiedCluster.DBInstanceParameterGroupName != nil {
+		t.Errorf("expected no instance parameter group for minor upgrade, got %s", aws.StringValue(mock.modifiedCluster.DBInstanceParameterGroupName))
+	}
+
+	if err := mc.UpgradeDatabase(ctx, &UpgradeInput{Identifier: "instance", EngineVersion: "13.7", DBParameterGroupName: "pg13"}); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	expectedInstance := &rds.ModifyDBInstanceInput{
+		AllowMajorVersionUpgrade: aws.Bool(false),
+		ApplyImmediately:         aws.Bool(true),
+		DBInstanceIdentifier:     aws.String("instance"),
+		DBParameterGroupName:     aws.String("pg13"),
+		EngineVersion:            aws.String("13.7"),
+	}
+	if mock.modifiedInstance.String() != expectedInstance.String() {
+		t.Errorf("expected %s, got %s", expectedInstance, mock.modifiedInstance)
+	}
+
+	if err := mc.UpgradeDatabase(ctx, &UpgradeInput{Identifier: "instance"}); err == nil {
+		t.Error("expected error for empty engine version, got nil")
+	}
+}
+
+func (m *mockUpgradeClient) DescribeDBEngineVersionsPagesWithContext(_ aws.Context, input *rds.DescribeDBEngineVersionsInput, fn func(*rds.DescribeDBEngineVersionsOutput, bool) bool, _ ...request.Option) error {
+	// return each version on its own page
+	i := 0
+	for _, v := range m.versions {
+		i++
+		if !fn(&rds.DescribeDBEngineVersionsOutput{DBEngineVersions: []*rds.DBEngineVersion{v}}, i == len(m.versions)) {
+			break
+		}
+	}
+	return nil
+}
+
+func TestClient_PlanUpgradePath(t *testing.T) {
+	mc := Client{
+		Service: &mockUpgradeClient{
+			versions: map[string]*rds.DBEngineVersion{
+				"10.21": engineVersion("10.21", "postgres10", upgradeTarget("11.16", true), upgradeTarget("12.11", true), upgradeTarget("13.7", true), upgradeTarget("14.3", true), upgradeTarget("14.10", true)),
+				"11.16": engineVersion("11.16", "postgres11", upgradeTarget("12.11", true), upgradeTarget("16.1", true)),
+				"12.11": engineVersion("12.11", "postgres12", upgradeTarget("13.7", true)),
+				"13.7":  engineVersion("13.7", "postgres13", upgradeTarget("14.3", true)),
+				"14.3":  engineVersion("14.3", "postgres14", upgradeTarget("14.10", false), upgradeTarget("16.1", true)),
+				"14.10": engineVersion("14.10", "postgres14", upgradeTarget("16.1", true)),
+				"15.5":  engineVersion("15.5", "postgres15"),
+				"16.1":  engineVersion("16.1", "postgres16"),
+			},
+		},
+	}
+
+	path, err := mc.PlanUpgradePath(ctx, "postgres", "10.21", "16.1")
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+
+	// 10.21 -> 11.16 -> 16.1 and 10.21 -> 14.10 -> 16.1 are both two hops, the latest versions are preferred
+	expected := []*UpgradeStep{
+		{EngineVersion: "14.10", IsMajorVersionUpgrade: true, DBParameterGroupFamily: "postgres14"},
+		{EngineVersion: "16.1", IsMajorVersionUpgrade: true, DBParameterGroupFamily: "postgres16"},
+	}
+	if !reflect.DeepEqual(path.Steps, expected) {
+		t.Errorf("expected steps %s, got %s", awsutil.Prettify(expected), awsutil.Prettify(path.Steps))
+	}
+
+	path, err = mc.PlanUpgradePath(ctx, "postgres", "13.7", "14.10")
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	expected = []*UpgradeStep{
+		{EngineVersion: "14.3", IsMajorVersionUpgrade: true, DBParameterGroupFamily: "postgres14"},
+		{EngineVersion: "14.10", IsMajorVersionUpgrade: false, DBParameterGroupFamily: "postgres14"},
+	}
+	if !reflect.DeepEqual(path.Steps, expected) {
+		t.Errorf("expected steps %s, got %s", awsutil.Prettify(expected), awsutil.Prettify(path.Steps))
+	}
+
+	path, err = mc.PlanUpgradePath(ctx, "postgres", "16.1", "16.1")
+	if err != nil || len(path.Steps) != 0 {
+		t.Errorf("expected no steps to the current version, got %v (%v)", path, err)
+	}
+
+	tests := []struct {
+		current string
+		target  string
+		code    string
+	}{
+		{"14.3", "15.5", apierror.ErrBadRequest},
+		{"16.1", "10.21", apierror.ErrBadRequest},
+		{"9.6", "16.1", apierror.ErrNotFound},
+		{"10.21", "17.0", apierror.ErrNotFound},
+	}
+
+	for _, test := range tests {
+		_, err := mc.PlanUpgradePath(ctx, "postgres", test.current, test.target)
+		if aerr, ok := err.(apierror.Error); !ok || aerr.Code != test.code {
+			t.Errorf("expected %s error planning %s to %s, got %v", test.code, test.current, test.target, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package rds
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// maxIdentifierLength is the maximum length of an RDS cluster or instance identifier
+const maxIdentifierLength = 63
+
+// identifierPattern matches RDS cluster and instance identifiers: letters, digits and hyphens, starting
+// with a letter, without two consecutive hyphens and not ending with a hyphen
+var identifierPattern = regexp.MustCompile(`^[a-zA-Z](-?[a-zA-Z0-9])*$`)
+
+// ValidateIdentifier checks the given RDS cluster or instance identifier against the RDS naming rules
+func ValidateIdentifier(id string) error {
+	if id == "" {
+		return errors.New("identifier cannot be empty")
+	}
+
+	if len(id) > maxIdentifierLength {
+		return fmt.Errorf("identifier %s is longer than %d characters", id, maxIdentifierLength)
+	}
+
+	if !identifierPattern.MatchString(id) {
+		return fmt.Errorf("identifier %s must start with a letter and only contain letters, digits and hyphens, without two consecutive hyphens or a trailing hyphen", id)
+	}
+
+	return nil
+}
+
+// DescribeEngineVersion returns the given engine version, or the default version of the engine if no version
+// is given.  It returns nil if the engine or version doesn't exist.
+func (r *Client) DescribeEngineVersion(ctx aws.Context, engine, version string) (*rds.DBEngineVersion, error) {
+	if engine == "" {
+		return nil, errors.New("engine cannot be empty")
+	}
+
+	input := &rds.DescribeDBEngineVersionsInput{
+		Engine:      aws.String(engine),
+		DefaultOnly: aws.Bool(version == ""),
+	}
+	if version != "" {
+		input.EngineVersion = aws.String(version)
+	}
+
+	out, err := r.Service.DescribeDBEngineVersionsWithContext(ctx, input)
+	if err != nil {
+		// an unknown engine is an invalid parameter
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "InvalidParameterValue" {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if len(out.DBEngineVersions) == 0 {
+		return nil, nil
+	}
+
+	return out.DBEngineVersions[0], nil
+}
+
+// OrderableInstanceClass returns true if database instances of the given class can be created with the given engine
+// and engine version.  If no version is given, any version of the engine will do.
+func (r *Client) OrderableInstanceClass(ctx aws.Context, engine, version, class string) (bool, error) {
+	if engine == "" || class == "" {
+		return false, errors.New("engine and instance class cannot be empty")
+	}
+
+	input := &rds.DescribeOrderableDBInstanceOptionsInput{
+		DBInstanceClass: aws.String(class),
+		Engine:          aws.String(engine),
+	}
+	if version != "" {
+		input.EngineVersion = aws.String(version)
+	}
+
+	found := false
+	if err := r.Service.DescribeOrderableDBInstanceOptionsPagesWithContext(ctx, input,
+		func(out *rds.DescribeOrderableDBInstanceOptionsOutput, lastPage bool) bool {
+			found = len(out.OrderableDBInstanceOptions) > 0
+			return !found
+		}); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "InvalidParameterValue" {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return found, nil
+}
+
+// SubnetGroupExists returns true if the database subnet group with the given name exists
+func (r *Client) SubnetGroupExists(ctx aws.Context, name string) (bool, error) {
+	_, err := r.Service.DescribeDBSubnetGroupsWithContext(ctx, &rds.DescribeDBSubnetGroupsInput{
+		DBSubnetGroupName: aws.String(name),
+	})
+	return exists(err, rds.ErrCodeDBSubnetGroupNotFoundFault)
+}
+
+// ParameterGroupExists returns true if the database parameter group with the given name exists
+func (r *Client) ParameterGroupExists(ctx aws.Context, name string) (bool, error) {
+	_, err := r.Service.DescribeDBParameterGroupsWithContext(ctx, &rds.DescribeDBParameterGroupsInput{
+		DBParameterGroupName: aws.String(name),
+	})
+	return exists(err, rds.ErrCodeDBParameterGroupNotFoundFault)
+}
+
+// ClusterParameterGroupExists returns true if the database cluster parameter group with the given name exists
+func (r *Client) ClusterParameterGroupExists(ctx aws.Context, name string) (bool, error) {
+	_, err := r.Service.DescribeDBClusterParameterGroupsWithContext(ctx, &rds.DescribeDBClusterParameterGroupsInput{
+		DBClusterParameterGroupName: aws.String(name),
+	})
+	return exists(err, rds.ErrCodeDBParameterGroupNotFoundFault)
+}
+
+// exists returns whether a resource exists, given the error from describing it and the not found error code
+func exists(err error, notFoundCode string) (bool, error) {
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == notFoundCode {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
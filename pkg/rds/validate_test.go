@@ -0,0 +1,225 @@
+package rds
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+// mockValidateClient is a fake rds client with engine versions, orderable instance classes and groups
+type mockValidateClient struct {
+	rdsiface.RDSAPI
+	versions map[string][]string
+	classes  map[string]bool
+	groups   map[string]bool
+}
+
+func (m *mockValidateClient) DescribeDBEngineVersionsWithContext(_ aws.Context, input *rds.DescribeDBEngineVersionsInput, _ ...request.Option) (*rds.DescribeDBEngineVersionsOutput, error) {
+	versions, ok := m.versions[aws.StringValue(input.Engine)]
+	if !ok {
+		return nil, awserr.New("InvalidParameterValue", "Invalid DB engine", nil)
+	}
+
+	out := &rds.DescribeDBEngineVersionsOutput{}
+	for i, v := range versions {
+		// the first version is the default
+		if (aws.BoolValue(input.DefaultOnly) && i == 0) || v == aws.StringValue(input.EngineVersion) {
+			out.DBEngineVersions = append(out.DBEngineVersions, &rds.DBEngineVersion{
+				Engine:                 input.Engine,
+				EngineVersion:          aws.String(v),
+				DBParameterGroupFamily: aws.String(aws.StringValue(input.Engine) + v),
+			})
+		}
+	}
+	return out, nil
+}
+
+func (m *mockValidateClient) DescribeOrderableDBInstanceOptionsPagesWithContext(_ aws.Context, input *rds.DescribeOrderableDBInstanceOptionsInput, fn func(*rds.DescribeOrderableDBInstanceOptionsOutput, bool) bool, _ ...request.Option) error {
+	if _, ok := m.versions[aws.StringValue(input.Engine)]; !ok {
+		return awserr.New("InvalidParameterValue", "Invalid DB engine", nil)
+	}
+
+	// an empty page comes first, to make sure every page is checked
+	if !fn(&rds.DescribeOrderableDBInstanceOptionsOutput{}, false) {
+		return nil
+	}
+
+	out := &rds.DescribeOrderableDBInstanceOptionsOutput{}
+	if m.classes[aws.StringValue(input.Engine)+"/"+aws.StringValue(input.DBInstanceClass)] {
+		out.OrderableDBInstanceOptions = []*rds.OrderableDBInstanceOption{{DBInstanceClass: input.DBInstanceClass}}
+	}
+	fn(out, true)
+	return nil
+}
+
+func (m *mockValidateClient) DescribeDBSubnetGroupsWithContext(_ aws.Context, input *rds.DescribeDBSubnetGroupsInput, _ ...request.Option) (*rds.DescribeDBSubnetGroupsOutput, error) {
+	if !m.groups["subnet/"+aws.StringValue(input.DBSubnetGroupName)] {
+		return nil, awserr.New(rds.ErrCodeDBSubnetGroupNotFoundFault, "not found", nil)
+	}
+	return &rds.DescribeDBSubnetGroupsOutput{}, nil
+}
+
+func (m *mockValidateClient) DescribeDBParameterGroupsWithContext(_ aws.Context, input *rds.DescribeDBParameterGroupsInput, _ ...request.Option) (*rds.DescribeDBParameterGroupsOutput, error) {
+	if aws.StringValue(input.DBParameterGroupName) == "broken" {
+		return nil, awserr.New(rds.ErrCodeInvalidDBParameterGroupStateFault, "boom", nil)
+	}
+	if !m.groups["parameter/"+aws.StringValue(input.DBParameterGroupName)] {
+		return nil, awserr.New(rds.ErrCodeDBParameterGroupNotFoundFault, "not found", nil)
+	}
+	return &rds.DescribeDBParameterGroupsOutput{}, nil
+}
+
+func (m *mockValidateClient) DescribeDBClusterParameterGroupsWithContext(_ aws.Context, input *rds.DescribeDBClusterParameterGroupsInput, _ ...request.Option) (*rds.DescribeDBClusterParameterGroupsOutput, error) {
+	if !m.groups["cluster-parameter/"+aws.StringValue(input.DBClusterParameterGroupName)] {
+		return nil, awserr.New(rds.ErrCodeDBParameterGroupNotFoundFault, "not found", nil)
+	}
+	return &rds.DescribeDBClusterParameterGroupsOutput{}, nil
+}
+
+func newMockValidateClient() Client {
+	return Client{
+		Service: &mockValidateClient{
+			versions: map[string][]string{
+				"postgres":     {"16.3", "15.7"},
+				"aurora-mysql": {"8.0.mysql_aurora.3.05.2"},
+			},
+			classes: map[string]bool{
+				"postgres/db.t3.micro":      true,
+				"aurora-mysql/db.r6g.large": true,
+			},
+			groups: map[string]bool{
+				"subnet/default":             true,
+				"parameter/pg16":             true,
+				"cluster-parameter/aurora80": true,
+			},
+		},
+	}
+}
+
+func TestValidateIdentifier(t *testing.T) {
+	tests := []struct {
+		id      string
+		wantErr bool
+	}{
+		{id: "mydb", wantErr: false},
+		{id: "my-db-1", wantErr: false},
+		{id: "M", wantErr: false},
+		{id: "", wantErr: true},
+		{id: "1mydb", wantErr: true},
+		{id: "-mydb", wantErr: true},
+		{id: "mydb-", wantErr: true},
+		{id: "my--db", wantErr: true},
+		{id: "my_db", wantErr: true},
+		{id: "my.db", wantErr: true},
+		{id: "a23456789012345678901234567890123456789012345678901234567890123", wantErr: false},
+		{id: "a234567890123456789012345678901234567890123456789012345678901234", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if err := ValidateIdentifier(tt.id); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateIdentifier(%q) error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_DescribeEngineVersion(t *testing.T) {
+	mc := newMockValidateClient()
+
+	tests := []struct {
+		name    string
+		engine  string
+		version string
+		want    string
+		wantErr bool
+	}{
+		{name: "default version", engine: "postgres", want: "16.3"},
+		{name: "given version", engine: "postgres", version: "15.7", want: "15.7"},
+		{name: "unknown version", engine: "postgres", version: "9.6"},
+		{name: "unknown engine", engine: "oracle-xx", version: "1"},
+		{name: "empty engine", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mc.DescribeEngineVersion(ctx, tt.engine, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DescribeEngineVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			v := ""
+			if got != nil {
+				v = aws.StringValue(got.EngineVersion)
+			}
+			if v != tt.want {
+				t.Errorf("expected version %q, got %q", tt.want, v)
+			}
+		})
+	}
+}
+
+func TestClient_OrderableInstanceClass(t *testing.T) {
+	mc := newMockValidateClient()
+
+	tests := []struct {
+		name    string
+		engine  string
+		class   string
+		want    bool
+		wantErr bool
+	}{
+		{name: "orderable", engine: "postgres", class: "db.t3.micro", want: true},
+		{name: "aurora orderable", engine: "aurora-mysql", class: "db.r6g.large", want: true},
+		{name: "not orderable", engine: "postgres", class: "db.r6g.large", want: false},
+		{name: "unknown engine", engine: "oracle-xx", class: "db.t3.micro", want: false},
+		{name: "empty class", engine: "postgres", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mc.OrderableInstanceClass(ctx, tt.engine, "", tt.class)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("OrderableInstanceClass() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_GroupExists(t *testing.T) {
+	mc := newMockValidateClient()
+
+	tests := []struct {
+		name    string
+		exists  func(aws.Context, string) (bool, error)
+		group   string
+		want    bool
+		wantErr bool
+	}{
+		{name: "subnet group", exists: mc.SubnetGroupExists, group: "default", want: true},
+		{name: "missing subnet group", exists: mc.SubnetGroupExists, group: "nope", want: false},
+		{name: "parameter group", exists: mc.ParameterGroupExists, group: "pg16", want: true},
+		{name: "missing parameter group", exists: mc.ParameterGroupExists, group: "nope", want: false},
+		{name: "parameter group error", exists: mc.ParameterGroupExists, group: "broken", wantErr: true},
+		{name: "cluster parameter group", exists: mc.ClusterParameterGroupExists, group: "aurora80", want: true},
+		{name: "missing cluster parameter group", exists: mc.ClusterParameterGroupExists, group: "pg16", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.exists(ctx, tt.group)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package rds
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+)
+
+// WaitUntilInstanceAvailable waits for the given database instance to become available
+func (r *Client) WaitUntilInstanceAvailable(ctx aws.Context, id string, opts ...request.WaiterOption) error {
+	if id == "" {
+		return errors.New("database identifier cannot be empty")
+	}
+
+	log.Printf("waiting for database instance %s to become available", id)
+
+	return r.Service.WaitUntilDBInstanceAvailableWithContext(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(id),
+	}, opts...)
+}
+
+// WaitUntilInstanceDeleted waits for the given database instance to be deleted
+func (r *Client) WaitUntilInstanceDeleted(ctx aws.Context, id string, opts ...request.WaiterOption) error {
+	if id == "" {
+		return errors.New("database identifier cannot be empty")
+	}
+
+	log.Printf("waiting for database instance %s to be deleted", id)
+
+	return r.Service.WaitUntilDBInstanceDeletedWithContext(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(id),
+	}, opts...)
+}
+
+// WaitUntilInstanceStopped waits for the given database instance to be stopped.
+// The SDK doesn't provide a waiter for this state so it's built the same way as the SDK waiters.
+func (r *Client) WaitUntilInstanceStopped(ctx aws.Context, id string, opts ...request.WaiterOption) error {
+	if id == "" {
+		return errors.New("database identifier cannot be empty")
+	}
+
+	log.Printf("waiting for database instance %s to be stopped", id)
+
+	w := request.Waiter{
+		Name:        "WaitUntilDBInstanceStopped",
+		MaxAttempts: 60,
+		Delay:       request.ConstantWaiterDelay(30 * time.Second),
+		Acceptors: []request.WaiterAcceptor{
+			{
+				State:   request.SuccessWaiterState,
+				Matcher: request.PathAllWaiterMatch, Argument: "DBInstances[].DBInstanceStatus",
+				Expected: "stopped",
+			},
+			{
+				State:   request.FailureWaiterState,
+				Matcher: request.PathAnyWaiterMatch, Argument: "DBInstances[].DBInstanceStatus",
+				Expected: "deleting",
+			},
+			{
+				State:   request.FailureWaiterState,
+				Matcher: request.PathAnyWaiterMatch, Argument: "DBInstances[].DBInstanceStatus",
+				Expected: "failed",
+			},
+		},
+		NewRequest: func(opts []request.Option) (*request.Request, error) {
+			req, _ := r.Service.DescribeDBInstancesRequest(&rds.DescribeDBInstancesInput{
+				DBInstanceIdentifier: aws.String(id),
+			})
+			req.SetContext(ctx)
+			req.ApplyOptions(opts...)
+			return req, nil
+		},
+	}
+	w.ApplyOptions(opts...)
+
+	return w.WaitWithContext(ctx)
+}
+
+// WaitUntilClusterAvailable waits for the given database cluster to become available
+func (r *Client) WaitUntilClusterAvailable(ctx aws.Context, id string, opts ...request.WaiterOption) error {
+	if id == "" {
+		return errors.New("database identifier cannot be empty")
+	}
+
+	log.Printf("waiting for database cluster %s to become available", id)
+
+	return r.Service.WaitUntilDBClusterAvailableWithContext(ctx, &rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(id),
+	}, opts...)
+}
+
+// WaitUntilClusterDeleted waits for the given database cluster to be deleted
+func (r *Client) WaitUntilClusterDeleted(ctx aws.Context, id string, opts ...request.WaiterOption) error {
+	if id == "" {
+		return errors.New("database identifier cannot be empty")
+	}
+
+	log.Printf("waiting for database cluster %s to be deleted", id)
+
+	return r.Service.WaitUntilDBClusterDeletedWithContext(ctx, &rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(id),
+	}, opts...)
+}
+
+// WaitUntilClusterStopped waits for the given database cluster to be stopped.
+// The SDK doesn't provide a waiter for this state so it's built the same way as the SDK waiters.
+func (r *Client) WaitUntilClusterStopped(ctx aws.Context, id string, opts ...request.WaiterOption) error {
+	if id == "" {
+		return errors.New("database identifier cannot be empty")
+	}
+
+	log.Printf("waiting for database cluster %s to be stopped", id)
+
+	w := request.Waiter{
+		Name:        "WaitUntilDBClusterStopped",
+		MaxAttempts: 60,
+		Delay:       request.ConstantWaiterDelay(30 * time.Second),
+		Acceptors: []request.WaiterAcceptor{
+			{
+				State:   request.SuccessWaiterState,
+				Matcher: request.PathAllWaiterMatch, Argument: "DBClusters[].Status",
+				Expected: "stopped",
+			},
+			{
+				State:   request.FailureWaiterState,
+				Matcher: request.PathAnyWaiterMatch, Argument: "DBClusters[].Status",
+				Expected: "deleting",
+			},
+			{
+				State:   request.FailureWaiterState,
+				Matcher: request.PathAnyWaiterMatch, Argument: "DBClusters[].Status",
+				Expected: "failed",
+			},
+		},
+		NewRequest: func(opts []request.Option) (*request.Request, error) {
+			req, _ := r.Service.DescribeDBClustersRequest(&rds.DescribeDBClustersInput{
+				DBClusterIdentifier: aws.String(id),
+			})
+			req.SetContext(ctx)
+			req.ApplyOptions(opts...)
+			return req, nil
+		},
+	}
+	w.ApplyOptions(opts...)
+
+	return w.WaitWithContext(ctx)
+}
+
+// WaitUntilEngineVersion waits for the given database cluster or instance to run the given engine version.
+// A database can still be available for a while after an upgrade is requested, before the upgrade starts, so
+// it's only upgraded once its engine version changed.  The SDK doesn't provide a waiter for this either.
+func (r *Client) WaitUntilEngineVersion(ctx aws.Context, id string, cluster bool, version string, opts ...request.WaiterOption) error {
+	if id == "" || version == "" {
+		return errors.New("database identifier and engine version cannot be empty")
+	}
+
+	log.Printf("waiting for database %s to run engine version %s", id, version)
+
+	versionPath, statusPath := "DBInstances[].EngineVersion", "DBInstances[].DBInstanceStatus"
+	newRequest := func(opts []request.Option) (*request.Request, error) {
+		req, _ := r.Service.DescribeDBInstancesRequest(&rds.DescribeDBInstancesInput{
+			DBInstanceIdentifier: aws.String(id),
+		})
+		req.SetContext(ctx)
+		req.ApplyOptions(opts...)
+		return req, nil
+	}
+	if cluster {
+		versionPath, statusPath = "DBClusters[].EngineVersion", "DBClusters[].Status"
+		newRequest = func(opts []request.Option) (*request.Request, error) {
+			req, _ := r.Service.DescribeDBClustersRequest(&rds.DescribeDBClustersInput{
+				DBClusterIdentifier: aws.String(id),
+			})
+			req.SetContext(ctx)
+			req.ApplyOptions(opts...)
+			return req, nil
+		}
+	}
+
+	w := request.Waiter{
+		Name:        "WaitUntilEngineVersion",
+		MaxAttempts: 60,
+		Delay:       request.ConstantWaiterDelay(30 * time.Second),
+		Acceptors: []request.WaiterAcceptor{
+			{
+				State:   request.SuccessWaiterState,
+				Matcher: request.PathAllWaiterMatch, Argument: versionPath,
+				Expected: version,
+			},
+			{
+				State:   request.FailureWaiterState,
+				Matcher: request.PathAnyWaiterMatch, Argument: statusPath,
+				Expected: "deleting",
+			},
+			{
+				State:   request.FailureWaiterState,
+				Matcher: request.PathAnyWaiterMatch, Argument: statusPath,
+				Expected: "failed",
+			},
+		},
+		NewRequest: newRequest,
+	}
+	w.ApplyOptions(opts...)
+
+	return w.WaitWithContext(ctx)
+}
+
+// WaitUntilSnapshotAvailable waits for the given database instance snapshot to become available
+func (r *Client) WaitUntilSnapshotAvailable(ctx aws.Context, id string, opts ...request.WaiterOption) error {
+	if id == "" {
+		return errors.New("snapshot identifier cannot be empty")
+	}
+
+	log.Printf("waiting for database snapshot %s to become available", id)
+
+	return r.Service.WaitUntilDBSnapshotAvailableWithContext(ctx, &rds.DescribeDBSnapshotsInput{
+		DBSnapshotIdentifier: aws.String(id),
+	}, opts...)
+}
+
+// WaitUntilClusterSnapshotAvailable waits for the given database cluster snapshot to become available
+func (r *Client) WaitUntilClusterSnapshotAvailable(ctx aws.Context, id string, opts ...request.WaiterOption) error {
+	if id == "" {
+		return errors.New("snapshot identifier cannot be empty")
+	}
+
+	log.Printf("waiting for database cluster snapshot %s to become available", id)
+
+	return r.Service.WaitUntilDBClusterSnapshotAvailableWithContext(ctx, &rds.DescribeDBClusterSnapshotsInput{
+		DBClusterSnapshotIdentifier: aws.String(id),
+	}, opts...)
+}
+
+// WaitUntilDatabaseAvailable waits for an RDS database cluster or instance to become available.
+// Like StartDatabase, it first looks for a cluster with the given identifier and falls back to an instance.
+func (r *Client) WaitUntilDatabaseAvailable(ctx aws.Context, id string, opts ...request.WaiterOption) error {
+	cluster, err := r.isCluster(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if cluster {
+		return r.WaitUntilClusterAvailable(ctx, id, opts...)
+	}
+
+	return r.WaitUntilInstanceAvailable(ctx, id, opts...)
+}
+
+// WaitUntilDatabaseStopped waits for an RDS database cluster or instance to be stopped.
+// Like StopDatabase, it first looks for a cluster with the given identifier and falls back to an instance.
+func (r *Client) WaitUntilDatabaseStopped(ctx aws.Context, id string, opts ...request.WaiterOption) error {
+	cluster, err := r.isCluster(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if cluster {
+		return r.WaitUntilClusterStopped(ctx, id, opts...)
+	}
+
+	return r.WaitUntilInstanceStopped(ctx, id, opts...)
+}
+
+// IsWaiterTimeout returns true if the given error was returned by a waiter that
+// ran out of attempts before the resource reached the desired (or a failure) state
+func IsWaiterTimeout(err error) bool {
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code() == request.WaiterResourceNotReadyErrorCode && aerr.OrigErr() == nil && aerr.Message() == "exceeded wait attempts"
+	}
+	return false
+}
+
+// isCluster returns true if a database cluster with the given identifier exists
+func (r *Client) isCluster(ctx aws.Context, id string) (bool, error) {
+	if id == "" {
+		return false, errors.New("database identifier cannot be empty")
+	}
+
+	if _, err := r.Service.DescribeDBClustersWithContext(ctx, &rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(id),
+	}); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == rds.ErrCodeDBClusterNotFoundFault {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
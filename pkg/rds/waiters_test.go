@@ -0,0 +1,135 @@
+package rds
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+)
+
+// mockWaiterClient records which waiter was called
+type mockWaiterClient struct {
+	rdsiface.RDSAPI
+	clusters map[string]bool
+	called   string
+}
+
+func (m *mockWaiterClient) DescribeDBClustersWithContext(_ aws.Context, input *rds.DescribeDBClustersInput, _ ...request.Option) (*rds.DescribeDBClustersOutput, error) {
+	if !m.clusters[aws.StringValue(input.DBClusterIdentifier)] {
+		return nil, awserr.New(rds.ErrCodeDBClusterNotFoundFault, "not found", nil)
+	}
+	return &rds.DescribeDBClustersOutput{DBClusters: []*rds.DBCluster{{DBClusterIdentifier: input.DBClusterIdentifier}}}, nil
+}
+
+func (m *mockWaiterClient) WaitUntilDBClusterAvailableWithContext(aws.Context, *rds.DescribeDBClustersInput, ...request.WaiterOption) error {
+	m.called = "cluster"
+	return nil
+}
+
+func (m *mockWaiterClient) WaitUntilDBInstanceAvailableWithContext(aws.Context, *rds.DescribeDBInstancesInput, ...request.WaiterOption) error {
+	m.called = "instance"
+	return nil
+}
+
+func TestWaitUntilDatabaseAvailable(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    string
+		wantErr bool
+	}{
+		{name: "cluster", id: "mycluster", want: "cluster"},
+		{name: "instance", id: "myinstance", want: "instance"},
+		{name: "empty id", id: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mockWaiterClient{clusters: map[string]bool{"mycluster": true}}
+			r := &Client{Service: m}
+			err := r.WaitUntilDatabaseAvailable(ctx, tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Client.WaitUntilDatabaseAvailable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if m.called != tt.want {
+				t.Errorf("expected %s waiter to be called, got %q", tt.want, m.called)
+			}
+		})
+	}
+}
+
+// mockEngineVersionClient describes a database instance that is upgraded after the given number of describes
+type mockEngineVersionClient struct {
+	rdsiface.RDSAPI
+	describes int
+	upgradeAt int
+}
+
+func (m *mockEngineVersionClient) DescribeDBInstancesRequest(input *rds.DescribeDBInstancesInput) (*request.Request, *rds.DescribeDBInstancesOutput) {
+	m.describes++
+
+	instance := &rds.DBInstance{
+		DBInstanceIdentifier: input.DBInstanceIdentifier,
+		DBInstanceStatus:     aws.String("available"),
+		EngineVersion:        aws.String("13.7"),
+	}
+	if m.describes > m.upgradeAt {
+		instance.EngineVersion = aws.String("14.10")
+	}
+
+	output := &rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{instance}}
+	req := request.New(aws.Config{}, metadata.ClientInfo{}, request.Handlers{}, nil, &request.Operation{Name: "DescribeDBInstances"}, input, output)
+	return req, output
+}
+
+func TestWaitUntilEngineVersion(t *testing.T) {
+	// the waiter sleeps between attempts, which needs a real context
+	ctx := context.Background()
+	opts := []request.WaiterOption{request.WithWaiterDelay(request.ConstantWaiterDelay(0)), request.WithWaiterMaxAttempts(5)}
+
+	// the database is still available with the old version when the upgrade starts
+	m := &mockEngineVersionClient{upgradeAt: 2}
+	r := &Client{Service: m}
+	if err := r.WaitUntilEngineVersion(ctx, "mydb", false, "14.10", opts...); err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if m.describes != 3 {
+		t.Errorf("expected to wait for the new engine version, got %d describes", m.describes)
+	}
+
+	m = &mockEngineVersionClient{upgradeAt: 10}
+	r = &Client{Service: m}
+	if err := r.WaitUntilEngineVersion(ctx, "mydb", false, "14.10", opts...); !IsWaiterTimeout(err) {
+		t.Errorf("expected waiter timeout, got %v", err)
+	}
+
+	if err := r.WaitUntilEngineVersion(ctx, "mydb", false, ""); err == nil {
+		t.Error("expected error for empty engine version, got nil")
+	}
+}
+
+func TestIsWaiterTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "exceeded attempts", err: awserr.New(request.WaiterResourceNotReadyErrorCode, "exceeded wait attempts", nil), want: true},
+		{name: "failure state", err: awserr.New(request.WaiterResourceNotReadyErrorCode, "failed waiting for successful resource state", nil), want: false},
+		{name: "aws error", err: awserr.New(rds.ErrCodeDBInstanceNotFoundFault, "not found", nil), want: false},
+		{name: "other error", err: errors.New("boom"), want: false},
+		{name: "nil error", err: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsWaiterTimeout(tt.err); got != tt.want {
+				t.Errorf("IsWaiterTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
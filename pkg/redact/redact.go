@@ -0,0 +1,66 @@
+// Package redact masks credentials in log output.  It recognizes sensitive keys (passwords, secrets, tokens
+// and the X-Auth-Token header) in the formats the service logs in: JSON, the %+v output of structs and maps,
+// the pretty printed AWS SDK types and the key=value fields of the logrus text formatter.
+package redact
+
+import (
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Mask replaces the value of sensitive keys
+const Mask = "[REDACTED]"
+
+// keyValue matches a sensitive key, optionally quoted (including escaped quotes in JSON that's been quoted
+// again by the logrus text formatter), followed by ':' or '=' and a value.  Sensitive keys end with password,
+// secret, token or secretaccesskey (case insensitive), e.g. MasterUserPassword, X-Auth-Token or SessionToken.
+// Keys like MasterUserSecretArn or secretsmanager:RotateSecret don't, and the resource type in an ARN
+// (arn:aws:secretsmanager:...:secret:name) isn't a key, so they are logged as is.  The value is
+// a quoted string (or one with escaped quotes), a list in brackets, or a bare word.
+var keyValue = regexp.MustCompile(`(^|[^\w:-])(\\?"?)([\w-]*?(?i:password|passwd|secret|secretaccesskey|token))(\\?"?\s*[:=]\s*)(\\"(?:[^"\\]|\\[^"])*\\"|"(?:[^"\\]|\\.)*"|\[[^\]]*\]|[^\s,{}\[\]&"\\]+)`)
+
+// String returns the given string with the values of sensitive keys masked
+func String(s string) string {
+	return keyValue.ReplaceAllStringFunc(s, func(match string) string {
+		parts := keyValue.FindStringSubmatch(match)
+		value := parts[5]
+
+		if value == "true" || value == "false" || value == "<nil>" || value == "null" {
+			return match
+		}
+
+		switch {
+		case strings.HasPrefix(value, `\"`):
+			value = `\"` + Mask + `\"`
+		case strings.HasPrefix(value, `"`):
+			value = `"` + Mask + `"`
+		case strings.HasPrefix(value, "["):
+			value = "[" + Mask + "]"
+		default:
+			value = Mask
+		}
+
+		return parts[1] + parts[2] + parts[3] + parts[4] + value
+	})
+}
+
+// Writer is an io.Writer that masks sensitive values before writing to the underlying writer.  Both the
+// stdlib and the logrus loggers write each entry with a single call, so entries are masked as a whole.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter returns a new Writer writing to the given writer
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write masks the sensitive values in p and writes it to the underlying writer.  It returns len(p) on
+// success, since the masked output is usually a different length than the input.
+func (w *Writer) Write(p []byte) (int, error) {
+	if _, err := w.w.Write([]byte(String(string(p)))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,94 @@
+package redact
+
+import (
+	"bytes"
+	"log"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "json",
+			input: `{"MasterUserPassword":"s3cr3t","MasterUsername":"admin"}`,
+			want:  `{"MasterUserPassword":"[REDACTED]","MasterUsername":"admin"}`,
+		},
+		{
+			name:  "indented json",
+			input: "{\n\t\"MasterUserPassword\": \"s3cr3t\",\n\t\"Port\": 5432\n}",
+			want:  "{\n\t\"MasterUserPassword\": \"[REDACTED]\",\n\t\"Port\": 5432\n}",
+		},
+		{
+			name:  "escaped quotes",
+			input: `json: "MasterUserPassword": "s3\"cr3t", "Engine": "postgres"`,
+			want:  `json: "MasterUserPassword": "[REDACTED]", "Engine": "postgres"`,
+		},
+		{
+			name:  "aws sdk pretty print",
+			input: "{\n  DBInstanceIdentifier: \"mydb\",\n  MasterUserPassword: \"s3cr3t\"\n}",
+			want:  "{\n  DBInstanceIdentifier: \"mydb\",\n  MasterUserPassword: \"[REDACTED]\"\n}",
+		},
+		{
+			name:  "struct",
+			input: `{MasterUserPassword:s3cr3t MasterUsername:admin}`,
+			want:  `{MasterUserPassword:[REDACTED] MasterUsername:admin}`,
+		},
+		{
+			name:  "header",
+			input: `map[Content-Type:[application/json] X-Auth-Token:[abcdef]]`,
+			want:  `map[Content-Type:[application/json] X-Auth-Token:[[REDACTED]]]`,
+		},
+		{
+			name:  "logrus fields",
+			input: `level=debug msg="assuming role" secret=abc token="def" path=/v1/rds`,
+			want:  `level=debug msg="assuming role" secret=[REDACTED] token="[REDACTED]" path=/v1/rds`,
+		},
+		{
+			name:  "json quoted by logrus",
+			input: `params="{\"MasterUserPassword\":[\"s3cr3t\"],\"db\":[\"mydb\"]}"`,
+			want:  `params="{\"MasterUserPassword\":[[REDACTED]],\"db\":[\"mydb\"]}"`,
+		},
+		{
+			name:  "aws credentials",
+			input: `SecretAccessKey: "abc", SessionToken: "def", AccessKeyId: "AKIA"`,
+			want:  `SecretAccessKey: "[REDACTED]", SessionToken: "[REDACTED]", AccessKeyId: "AKIA"`,
+		},
+		{
+			name:  "booleans and arns",
+			input: `{"ManageMasterUserPassword":true,"MasterUserSecretArn":"arn:aws:secretsmanager:us-east-1:0123456789:secret:rds!db-1"}`,
+			want:  `{"ManageMasterUserPassword":true,"MasterUserSecretArn":"arn:aws:secretsmanager:us-east-1:0123456789:secret:rds!db-1"}`,
+		},
+		{
+			name:  "policy actions",
+			input: `{"Action":["secretsmanager:RotateSecret"],"Resource":["arn:aws:secretsmanager:*:0123456789:secret:rds!*"]}`,
+			want:  `{"Action":["secretsmanager:RotateSecret"],"Resource":["arn:aws:secretsmanager:*:0123456789:secret:rds!*"]}`,
+		},
+		{
+			name:  "plain message",
+			input: "Missing token header for request /v1/rds/0123456789/mydb",
+			want:  "Missing token header for request /v1/rds/0123456789/mydb",
+		},
+	}
+
+	for _, test := range tests {
+		if got := String(test.input); got != test.want {
+			t.Errorf("%s: expected %s, got %s", test.name, test.want, got)
+		}
+	}
+}
+
+func TestWriter(t *testing.T) {
+	buf := bytes.Buffer{}
+	logger := log.New(NewWriter(&buf), "", 0)
+
+	logger.Printf("creating database with password %s", `{"MasterUserPassword":"s3cr3t"}`)
+
+	expected := `creating database with password {"MasterUserPassword":"[REDACTED]"}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expected %s, got %s", expected, buf.String())
+	}
+}
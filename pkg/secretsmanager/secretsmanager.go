@@ -0,0 +1,62 @@
+package secretsmanager
+
+import (
+	"context"
+
+	"github.com/YaleSpinup/apierror"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	log "github.com/sirupsen/logrus"
+)
+
+type SecretsManager struct {
+	session *session.Session
+	Service secretsmanageriface.SecretsManagerAPI
+}
+
+type SecretsManagerOption func(*SecretsManager)
+
+func New(opts ...SecretsManagerOption) SecretsManager {
+	s := SecretsManager{}
+
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	if s.session != nil {
+		s.Service = secretsmanager.New(s.session)
+	}
+
+	return s
+}
+
+func WithSession(sess *session.Session) SecretsManagerOption {
+	return func(s *SecretsManager) {
+		log.Debug("using aws session")
+		s.session = sess
+	}
+}
+
+// RotateSecret starts an immediate rotation of the secret with the given id, using its configured rotation.
+// For master user secrets managed by RDS, RDS rotates the password of the database.
+func (s *SecretsManager) RotateSecret(ctx context.Context, id string) (*secretsmanager.RotateSecretOutput, error) {
+	if id == "" {
+		return nil, apierror.New(apierror.ErrBadRequest, "invalid input", nil)
+	}
+
+	log.Infof("rotating secret %s", id)
+
+	out, err := s.Service.RotateSecretWithContext(ctx, &secretsmanager.RotateSecretInput{
+		RotateImmediately: aws.Bool(true),
+		SecretId:          aws.String(id),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("got output from secretsmanager rotate secret (%s): version %s", id, aws.StringValue(out.VersionId))
+
+	return out, nil
+}
@@ -0,0 +1,71 @@
+package secretsmanager
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+)
+
+// mockSecretsManagerClient is a fake secretsmanager client
+type mockSecretsManagerClient struct {
+	secretsmanageriface.SecretsManagerAPI
+	t   *testing.T
+	err error
+}
+
+func newMockSecretsManagerClient(t *testing.T, err error) secretsmanageriface.SecretsManagerAPI {
+	return &mockSecretsManagerClient{
+		t:   t,
+		err: err,
+	}
+}
+
+func (m *mockSecretsManagerClient) RotateSecretWithContext(_ aws.Context, input *secretsmanager.RotateSecretInput, _ ...request.Option) (*secretsmanager.RotateSecretOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if !aws.BoolValue(input.RotateImmediately) {
+		m.t.Errorf("expected secret to be rotated immediately")
+	}
+
+	return &secretsmanager.RotateSecretOutput{
+		ARN:       input.SecretId,
+		VersionId: aws.String("v2"),
+	}, nil
+}
+
+func TestNewSession(t *testing.T) {
+	client := New()
+	to := reflect.TypeOf(client).String()
+	if to != "secretsmanager.SecretsManager" {
+		t.Errorf("expected type to be 'secretsmanager.SecretsManager', got %s", to)
+	}
+}
+
+func TestRotateSecret(t *testing.T) {
+	s := SecretsManager{Service: newMockSecretsManagerClient(t, nil)}
+
+	out, err := s.RotateSecret(context.TODO(), "arn:aws:secretsmanager:us-east-1:0123456789:secret:rds!db-123")
+	if err != nil {
+		t.Fatalf("expected nil error, got %s", err)
+	}
+	if aws.StringValue(out.VersionId) != "v2" {
+		t.Errorf("expected version v2, got %s", aws.StringValue(out.VersionId))
+	}
+
+	if _, err := s.RotateSecret(context.TODO(), ""); err == nil {
+		t.Error("expected error for empty secret id, got nil")
+	}
+
+	s = SecretsManager{Service: newMockSecretsManagerClient(t, awserr.New(secretsmanager.ErrCodeInvalidRequestException, "rotation in progress", nil))}
+	if _, err := s.RotateSecret(context.TODO(), "secret"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
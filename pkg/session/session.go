@@ -1,6 +1,8 @@
 package session
 
 import (
+	"time"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -9,9 +11,11 @@ import (
 
 // Session is a wrapper around the aws session service
 type Session struct {
-	Session     *session.Session
-	RoleName    string
-	ExternalID  string
+	Session    *session.Session
+	RoleName   string
+	ExternalID string
+	// Expiration is when the session credentials expire, it's zero for credentials that don't expire
+	Expiration  time.Time
 	credentials *credentials.Credentials
 	region      string
 }
@@ -46,6 +50,13 @@ func WithCredentials(key, secret, token string) SessionOption {
 	}
 }
 
+func WithExpiration(expiration time.Time) SessionOption {
+	return func(s *Session) {
+		log.Debugf("setting credentials expiration to %s", expiration)
+		s.Expiration = expiration
+	}
+}
+
 func WithRegion(region string) SessionOption {
 	return func(s *Session) {
 		log.Debugf("setting region to %s", region)